@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines
+// displayed around every change in a diff.
+const diffContext = 3
+
+// edit represents a single line of a diff.
+type edit struct {
+	op   byte
+	line string
+	// the line number in the before and after content
+	before, after int
+}
+
+// diff is a helper function to return the unified diff
+// between the before and after content for the provided name.
+func diff(name string, before, after []byte) []byte {
+	edits := diffLines(splitLines(before), splitLines(after))
+
+	b := new(bytes.Buffer)
+
+	fmt.Fprintf(b, "--- %s.orig\n+++ %s\n", name, name)
+
+	// iterate through each group of changes
+	for start := 0; start < len(edits); {
+		// find the next change
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+
+		if start == len(edits) {
+			break
+		}
+
+		// capture the context before the change
+		first := max(start-diffContext, 0)
+		end := start
+
+		// extend the hunk while changes are close to each other
+		for last := start; end < len(edits); end++ {
+			if edits[end].op != ' ' {
+				last = end
+			}
+
+			if end-last > 2*diffContext {
+				break
+			}
+		}
+
+		// trim the context after the last change
+		for end > first && edits[end-1].op == ' ' && trailing(edits[first:end]) > diffContext {
+			end--
+		}
+
+		writeHunk(b, edits[first:end])
+
+		start = end
+	}
+
+	return b.Bytes()
+}
+
+// trailing is a helper function to return the number
+// of unchanged lines at the end of the edits.
+func trailing(edits []edit) int {
+	count := 0
+
+	for i := len(edits) - 1; i >= 0 && edits[i].op == ' '; i-- {
+		count++
+	}
+
+	return count
+}
+
+// writeHunk is a helper function to write the
+// provided edits as a hunk of a unified diff.
+func writeHunk(b *bytes.Buffer, edits []edit) {
+	oldStart, oldCount, newStart, newCount := 0, 0, 0, 0
+
+	for _, e := range edits {
+		if e.op != '+' {
+			if oldCount == 0 {
+				oldStart = e.before
+			}
+
+			oldCount++
+		}
+
+		if e.op != '-' {
+			if newCount == 0 {
+				newStart = e.after
+			}
+
+			newCount++
+		}
+	}
+
+	// an empty range starts at the line before it
+	if oldCount == 0 {
+		oldStart = edits[0].before - 1
+	}
+
+	if newCount == 0 {
+		newStart = edits[0].after - 1
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, e := range edits {
+		fmt.Fprintf(b, "%c%s\n", e.op, e.line)
+	}
+}
+
+// diffLines is a helper function to return the edits to
+// change the before lines to the after lines based on the
+// longest common subsequence of the lines.
+func diffLines(before, after []string) []edit {
+	// lengths of the longest common subsequence for every suffix
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := []edit{}
+
+	i, j := 0, 0
+
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			edits = append(edits, edit{op: ' ', line: before[i], before: i + 1, after: j + 1})
+
+			i++
+			j++
+		case i < len(before) && (j == len(after) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{op: '-', line: before[i], before: i + 1, after: j + 1})
+
+			i++
+		default:
+			edits = append(edits, edit{op: '+', line: after[j], before: i + 1, after: j + 1})
+
+			j++
+		}
+	}
+
+	return edits
+}
+
+// splitLines is a helper function to split the
+// provided content into lines without newlines.
+func splitLines(content []byte) []string {
+	text := strings.TrimSuffix(string(content), "\n")
+	if len(text) == 0 {
+		return []string{}
+	}
+
+	return strings.Split(text, "\n")
+}
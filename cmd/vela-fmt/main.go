@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// This program formats Vela pipelines, similar to gofmt
+// for Go source code, by normalizing every pipeline through
+// the yaml package: consistent indentation, keys ordered
+// as documented, event shorthands collapsed and rulesets
+// written in the simple form when possible. Comments in
+// the pipeline are kept.
+//
+// Usage:
+//
+//	vela-fmt [flags] [path ...]
+//
+// Without a path, the pipeline is read from standard input
+// and the formatted pipeline is written to standard output.
+// Directories are walked for .vela.yml and .vela.yaml files.
+//
+// The flags are:
+//
+//	-l
+//		List the pipelines whose formatting differs.
+//	-d
+//		Display the diffs instead of rewriting the pipelines.
+//	-w
+//		Write the result to the pipeline instead of standard output.
+//
+// Pipelines using anchors and aliases are left unchanged, with a
+// warning, since formatting them would expand every alias.
+//
+// The exit status is 1 when -l or -d found a pipeline that is
+// not formatted, so the command can enforce a style in CI, and
+// 2 when a pipeline could not be read, parsed or written.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-vela/types/yaml"
+)
+
+// options represents the flags provided to the command.
+type options struct {
+	list  bool
+	diff  bool
+	write bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the provided arguments
+// and returns the exit status for the command.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts := new(options)
+
+	flags := flag.NewFlagSet("vela-fmt", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&opts.list, "l", false, "list pipelines whose formatting differs")
+	flags.BoolVar(&opts.diff, "d", false, "display diffs instead of rewriting pipelines")
+	flags.BoolVar(&opts.write, "w", false, "write result to the pipeline instead of stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: vela-fmt [flags] [path ...]\n")
+		flags.PrintDefaults()
+	}
+
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	// format standard input when no paths are provided
+	if flags.NArg() == 0 {
+		if opts.write {
+			fmt.Fprintln(stderr, "vela-fmt: can not use -w with standard input")
+
+			return 2
+		}
+
+		changed, err := process("<standard input>", stdin, stdout, stderr, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "vela-fmt: %v\n", err)
+
+			return 2
+		}
+
+		return status(changed, opts)
+	}
+
+	code := 0
+
+	// iterate through each provided path
+	for _, path := range flags.Args() {
+		files, err := pipelines(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "vela-fmt: %v\n", err)
+
+			code = 2
+
+			continue
+		}
+
+		for _, file := range files {
+			changed, err := processFile(file, stdout, stderr, opts)
+			if err != nil {
+				fmt.Fprintf(stderr, "vela-fmt: %s: %v\n", file, err)
+
+				code = 2
+
+				continue
+			}
+
+			if code == 0 {
+				code = status(changed, opts)
+			}
+		}
+	}
+
+	return code
+}
+
+// status is a helper function to return the exit
+// status for a pipeline with the provided result.
+func status(changed bool, opts *options) int {
+	if changed && (opts.list || opts.diff) {
+		return 1
+	}
+
+	return 0
+}
+
+// pipelines is a helper function to return the pipelines for
+// the provided path. A file is always returned while a directory
+// is walked for files named .vela.yml or .vela.yaml.
+func pipelines(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	files := []string{}
+
+	err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// skip the git metadata for a repository
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !entry.IsDir() && (entry.Name() == ".vela.yml" || entry.Name() == ".vela.yaml") {
+			files = append(files, file)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// processFile is a helper function to format the pipeline
+// in the provided file and returns whether it changed.
+func processFile(file string, stdout, stderr io.Writer, opts *options) (bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	return process(file, f, stdout, stderr, opts)
+}
+
+// process is a helper function to format the pipeline from the
+// provided reader and report the result based on the options.
+func process(name string, in io.Reader, stdout, stderr io.Writer, opts *options) (bool, error) {
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return false, err
+	}
+
+	out, err := yaml.Format(src)
+
+	switch {
+	case errors.Is(err, yaml.ErrFormatAlias):
+		// keep the pipeline as written since formatting expands the aliases
+		fmt.Fprintf(stderr, "vela-fmt: %s: skipped: %v\n", name, err)
+
+		out = src
+	case err != nil:
+		return false, err
+	}
+
+	changed := !bytes.Equal(src, out)
+
+	// write the formatted pipeline when no mode is provided
+	if !opts.list && !opts.diff && !opts.write {
+		_, err = stdout.Write(out)
+
+		return changed, err
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	if opts.list {
+		fmt.Fprintln(stdout, name)
+	}
+
+	if opts.diff {
+		_, err = stdout.Write(diff(name, src, out))
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	if opts.write {
+		info, err := os.Stat(name)
+		if err != nil {
+			return changed, err
+		}
+
+		err = os.WriteFile(name, out, info.Mode().Perm())
+		if err != nil {
+			return changed, err
+		}
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	unformatted = `steps:
+  - image: alpine
+    name: test
+    pull: not_present
+    commands: [ echo hello ]
+`
+
+	formatted = `steps:
+  - name: test
+    image: alpine
+    commands:
+      - echo hello
+`
+
+	anchored = `x-base: &base
+  image: alpine
+  pull: not_present
+
+steps:
+  - <<: *base
+    commands: [ echo hello ]
+    name: test
+`
+)
+
+func TestVelaFmt_Run(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	changed := filepath.Join(dir, ".vela.yml")
+	unchanged := filepath.Join(dir, "nested", ".vela.yaml")
+
+	err := os.WriteFile(changed, []byte(unformatted), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(unchanged), 0o700)
+	if err != nil {
+		t.Fatalf("unable to create directory: %v", err)
+	}
+
+	err = os.WriteFile(unchanged, []byte(formatted), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		args   []string
+		code   int
+		stdout string
+	}{
+		{args: []string{"-l", dir}, code: 1, stdout: changed + "\n"},
+		{args: []string{"-w", dir}, code: 0},
+		{args: []string{"-l", "-d", dir}, code: 0},
+	}
+
+	// run tests
+	for _, test := range tests {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+		code := run(test.args, strings.NewReader(""), stdout, stderr)
+
+		if code != test.code {
+			t.Errorf("run %v returned %d, want %d: %s", test.args, code, test.code, stderr)
+		}
+
+		if stdout.String() != test.stdout {
+			t.Errorf("run %v output is %s, want %s", test.args, stdout, test.stdout)
+		}
+	}
+
+	// verify the pipeline was written
+	got, err := os.ReadFile(changed)
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	if string(got) != formatted {
+		t.Errorf("pipeline is %s, want %s", got, formatted)
+	}
+}
+
+func TestVelaFmt_Run_Anchored(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	file := filepath.Join(dir, ".vela.yml")
+
+	err := os.WriteFile(file, []byte(anchored), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		args   []string
+		stdin  string
+		stdout string
+	}{
+		{args: []string{"-l", dir}},
+		{args: []string{"-d", dir}},
+		{args: []string{"-w", dir}},
+		{stdin: anchored, stdout: anchored},
+	}
+
+	// run tests
+	for _, test := range tests {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+		code := run(test.args, strings.NewReader(test.stdin), stdout, stderr)
+
+		if code != 0 {
+			t.Errorf("run %v returned %d, want 0: %s", test.args, code, stderr)
+		}
+
+		if stdout.String() != test.stdout {
+			t.Errorf("run %v output is %s, want %s", test.args, stdout, test.stdout)
+		}
+
+		if !strings.Contains(stderr.String(), "skipped: unable to format a document using aliases") {
+			t.Errorf("run %v error output is %s, want skipped warning", test.args, stderr)
+		}
+	}
+
+	// verify the pipeline was not written
+	got, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	if string(got) != anchored {
+		t.Errorf("pipeline is %s, want %s", got, anchored)
+	}
+}
+
+func TestVelaFmt_Run_Stdin(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+	}{
+		{stdin: unformatted, code: 0, stdout: formatted},
+		{args: []string{"-d"}, stdin: formatted, code: 0},
+		{args: []string{"-w"}, stdin: unformatted, code: 2},
+		{stdin: "steps: [\n", code: 2},
+	}
+
+	// run tests
+	for _, test := range tests {
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+		code := run(test.args, strings.NewReader(test.stdin), stdout, stderr)
+
+		if code != test.code {
+			t.Errorf("run %v returned %d, want %d: %s", test.args, code, test.code, stderr)
+		}
+
+		if stdout.String() != test.stdout {
+			t.Errorf("run %v output is %s, want %s", test.args, stdout, test.stdout)
+		}
+	}
+}
+
+func TestVelaFmt_Diff(t *testing.T) {
+	// setup types
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	after := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nl\nm\n"
+
+	want := `--- test.orig
++++ test
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -8,5 +8,5 @@
+ h
+ i
+ j
+-k
+ l
++m
+`
+
+	// run test
+	got := diff("test", []byte(before), []byte(after))
+
+	if string(got) != want {
+		t.Errorf("diff is %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package constants
+
+// Service and step retry backoff types.
+const (
+	// BackoffFixed defines the backoff type for waiting
+	// the same delay before every retry of a container.
+	BackoffFixed = "fixed"
+
+	// BackoffExponential defines the backoff type for doubling
+	// the delay before every retry of a container.
+	BackoffExponential = "exponential"
+)
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package constants
+
+// Cache scopes.
+const (
+	// CacheScopeRepo defines the scope for a cache shared by every build for a repo.
+	CacheScopeRepo = "repo"
+
+	// CacheScopeOrg defines the scope for a cache shared by every repo for an org.
+	CacheScopeOrg = "org"
+)
@@ -36,4 +36,28 @@ const (
 
 	// ReportStepStatusLimit defines the maximum number of steps in a pipeline that may report their status to the SCM.
 	ReportStepStatusLimit = 10
+
+	// StepRetriesMax defines the maximum number of times a step or service may be retried.
+	StepRetriesMax = 10
+
+	// ArtifactRetentionMax defines the maximum number of days an artifact may be retained.
+	ArtifactRetentionMax = 90
+
+	// MatrixCombinationsMax defines the maximum number of combinations a matrix for a step or stage may produce.
+	MatrixCombinationsMax = 256
+
+	// TemplateDepthMax defines the maximum depth of templates nested in the steps rendered from a template.
+	TemplateDepthMax = 3
+
+	// TemplateUntilMax defines the maximum count for the until function available to a Go template.
+	TemplateUntilMax = 10000
+
+	// TemplateOutputLimitDefault defines the default maximum size in bytes for the pipeline rendered from a Go template.
+	TemplateOutputLimitDefault = 1048576
+
+	// StarlarkExecLimitDefault defines the default maximum number of execution steps for a Starlark program.
+	StarlarkExecLimitDefault = 7500
+
+	// StarlarkOutputLimitDefault defines the default maximum size in bytes for the pipeline returned by a Starlark program.
+	StarlarkOutputLimitDefault = 1048576
 )
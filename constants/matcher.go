@@ -9,4 +9,7 @@ const (
 
 	// MatcherRegex defines the ruleset type for the regex matcher.
 	MatcherRegex = "regexp"
+
+	// MatcherGlob defines the ruleset type for the glob matcher.
+	MatcherGlob = "glob"
 )
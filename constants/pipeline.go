@@ -13,3 +13,13 @@ const (
 	// PipelineTemplate defines the type for a pipeline as a template.
 	PipelineTemplate = "template"
 )
+
+// Pipeline syntax versions.
+const (
+	// PipelineVersion1 defines the first syntax version for a pipeline.
+	PipelineVersion1 = "1"
+
+	// PipelineVersion2 defines the syntax version for
+	// a pipeline where the events for a ruleset are scoped.
+	PipelineVersion2 = "2"
+)
@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package constants
+
+// Pipeline diagnostic severities.
+const (
+	// SeverityError defines the severity type for a
+	// diagnostic that prevents a pipeline from compiling.
+	SeverityError = "error"
+
+	// SeverityWarning defines the severity type for a
+	// diagnostic that does not prevent a pipeline from compiling.
+	SeverityWarning = "warning"
+
+	// SeverityInfo defines the severity type for a
+	// diagnostic that is purely informational.
+	SeverityInfo = "info"
+)
@@ -4,6 +4,9 @@ package constants
 
 // Database tables.
 const (
+	// TableArtifact defines the table type for the database artifacts table.
+	TableArtifact = "artifacts"
+
 	// TableBuild defines the table type for the database builds table.
 	TableBuild = "builds"
 
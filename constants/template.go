@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package constants
+
+// Template formats.
+const (
+	// TemplateFormatGo defines the format for a template
+	// rendered with the Go text/template package.
+	TemplateFormatGo = "go"
+
+	// TemplateFormatGolang defines the alternate name
+	// for the format of a template rendered with the
+	// Go text/template package.
+	TemplateFormatGolang = "golang"
+
+	// TemplateFormatStarlark defines the format for a
+	// template evaluated as a Starlark program.
+	TemplateFormatStarlark = "starlark"
+)
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/go-vela/types/library"
+)
+
+var (
+	// ErrEmptyArtifactBuildID defines the error type when a
+	// Artifact type has an empty BuildID field provided.
+	ErrEmptyArtifactBuildID = errors.New("empty artifact build_id provided")
+
+	// ErrEmptyArtifactName defines the error type when a
+	// Artifact type has an empty Name field provided.
+	ErrEmptyArtifactName = errors.New("empty artifact name provided")
+
+	// ErrEmptyArtifactPath defines the error type when a
+	// Artifact type has an empty Path field provided.
+	ErrEmptyArtifactPath = errors.New("empty artifact path provided")
+)
+
+// Artifact is the database representation of an artifact uploaded by a step for a build.
+//
+// Deprecated: use Artifact from github.com/go-vela/server/database/types instead.
+type Artifact struct {
+	ID        sql.NullInt64  `sql:"id"`
+	BuildID   sql.NullInt64  `sql:"build_id"`
+	Name      sql.NullString `sql:"name"`
+	Step      sql.NullString `sql:"step"`
+	Path      sql.NullString `sql:"path"`
+	Size      sql.NullInt64  `sql:"size"`
+	CreatedAt sql.NullInt64  `sql:"created_at"`
+	ExpiresAt sql.NullInt64  `sql:"expires_at"`
+}
+
+// ArtifactFromLibrary converts the library.Artifact type to a database Artifact type.
+func ArtifactFromLibrary(a *library.Artifact) *Artifact {
+	artifact := &Artifact{
+		ID:        sql.NullInt64{Int64: a.GetID(), Valid: true},
+		BuildID:   sql.NullInt64{Int64: a.GetBuildID(), Valid: true},
+		Name:      sql.NullString{String: a.GetName(), Valid: true},
+		Step:      sql.NullString{String: a.GetStep(), Valid: true},
+		Path:      sql.NullString{String: a.GetPath(), Valid: true},
+		Size:      sql.NullInt64{Int64: a.GetSize(), Valid: true},
+		CreatedAt: sql.NullInt64{Int64: a.GetCreatedAt(), Valid: true},
+		ExpiresAt: sql.NullInt64{Int64: a.GetExpiresAt(), Valid: true},
+	}
+
+	return artifact.Nullify()
+}
+
+// Nullify ensures the valid flag for
+// the sql.Null types are properly set.
+//
+// When a field within the Artifact type is the zero
+// value for the field, the valid flag is set to
+// false causing it to be NULL in the database.
+func (a *Artifact) Nullify() *Artifact {
+	if a == nil {
+		return nil
+	}
+
+	// check if the ID field should be false
+	if a.ID.Int64 == 0 {
+		a.ID.Valid = false
+	}
+
+	// check if the BuildID field should be false
+	if a.BuildID.Int64 == 0 {
+		a.BuildID.Valid = false
+	}
+
+	// check if the Name field should be false
+	if len(a.Name.String) == 0 {
+		a.Name.Valid = false
+	}
+
+	// check if the Step field should be false
+	if len(a.Step.String) == 0 {
+		a.Step.Valid = false
+	}
+
+	// check if the Path field should be false
+	if len(a.Path.String) == 0 {
+		a.Path.Valid = false
+	}
+
+	// check if the Size field should be false
+	if a.Size.Int64 == 0 {
+		a.Size.Valid = false
+	}
+
+	// check if the CreatedAt field should be false
+	if a.CreatedAt.Int64 == 0 {
+		a.CreatedAt.Valid = false
+	}
+
+	// check if the ExpiresAt field should be false
+	if a.ExpiresAt.Int64 == 0 {
+		a.ExpiresAt.Valid = false
+	}
+
+	return a
+}
+
+// ToLibrary converts the Artifact type to a library Artifact type.
+func (a *Artifact) ToLibrary() *library.Artifact {
+	artifact := new(library.Artifact)
+
+	artifact.SetID(a.ID.Int64)
+	artifact.SetBuildID(a.BuildID.Int64)
+	artifact.SetName(a.Name.String)
+	artifact.SetStep(a.Step.String)
+	artifact.SetPath(a.Path.String)
+	artifact.SetSize(a.Size.Int64)
+	artifact.SetCreatedAt(a.CreatedAt.Int64)
+	artifact.SetExpiresAt(a.ExpiresAt.Int64)
+
+	return artifact
+}
+
+// Validate verifies the necessary fields for
+// the Artifact type are populated correctly.
+func (a *Artifact) Validate() error {
+	// verify the BuildID field is populated
+	if a.BuildID.Int64 <= 0 {
+		return ErrEmptyArtifactBuildID
+	}
+
+	// verify the Name field is populated
+	if len(a.Name.String) == 0 {
+		return ErrEmptyArtifactName
+	}
+
+	// verify the Path field is populated
+	if len(a.Path.String) == 0 {
+		return ErrEmptyArtifactPath
+	}
+
+	// ensure that all Artifact string fields
+	// that can be returned as JSON are sanitized
+	// to avoid unsafe HTML content
+	a.Name = sql.NullString{String: sanitize(a.Name.String), Valid: a.Name.Valid}
+	a.Step = sql.NullString{String: sanitize(a.Step.String), Valid: a.Step.Valid}
+	a.Path = sql.NullString{String: sanitize(a.Path.String), Valid: a.Path.Valid}
+
+	return nil
+}
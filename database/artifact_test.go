@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/library"
+)
+
+func TestDatabase_Artifact_Nullify(t *testing.T) {
+	// setup types
+	var a *Artifact
+
+	want := &Artifact{
+		ID:        sql.NullInt64{Int64: 0, Valid: false},
+		BuildID:   sql.NullInt64{Int64: 0, Valid: false},
+		Name:      sql.NullString{String: "", Valid: false},
+		Step:      sql.NullString{String: "", Valid: false},
+		Path:      sql.NullString{String: "", Valid: false},
+		Size:      sql.NullInt64{Int64: 0, Valid: false},
+		CreatedAt: sql.NullInt64{Int64: 0, Valid: false},
+		ExpiresAt: sql.NullInt64{Int64: 0, Valid: false},
+	}
+
+	// setup tests
+	tests := []struct {
+		artifact *Artifact
+		want     *Artifact
+	}{
+		{
+			artifact: testArtifact(),
+			want:     testArtifact(),
+		},
+		{
+			artifact: a,
+			want:     nil,
+		},
+		{
+			artifact: new(Artifact),
+			want:     want,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.artifact.Nullify()
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Nullify is %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestDatabase_Artifact_ToLibrary(t *testing.T) {
+	// setup types
+	want := new(library.Artifact)
+
+	want.SetID(1)
+	want.SetBuildID(1)
+	want.SetName("coverage")
+	want.SetStep("test")
+	want.SetPath("github/octocat/1/coverage.tar.gz")
+	want.SetSize(1024)
+	want.SetCreatedAt(1563474076)
+	want.SetExpiresAt(1563560476)
+
+	// run test
+	got := testArtifact().ToLibrary()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToLibrary is %v, want %v", got, want)
+	}
+}
+
+func TestDatabase_Artifact_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		failure  bool
+		artifact *Artifact
+	}{
+		{
+			failure:  false,
+			artifact: testArtifact(),
+		},
+		{ // no build_id set for artifact
+			failure: true,
+			artifact: &Artifact{
+				ID:   sql.NullInt64{Int64: 1, Valid: true},
+				Name: sql.NullString{String: "coverage", Valid: true},
+				Path: sql.NullString{String: "github/octocat/1/coverage.tar.gz", Valid: true},
+			},
+		},
+		{ // no name set for artifact
+			failure: true,
+			artifact: &Artifact{
+				ID:      sql.NullInt64{Int64: 1, Valid: true},
+				BuildID: sql.NullInt64{Int64: 1, Valid: true},
+				Path:    sql.NullString{String: "github/octocat/1/coverage.tar.gz", Valid: true},
+			},
+		},
+		{ // no path set for artifact
+			failure: true,
+			artifact: &Artifact{
+				ID:      sql.NullInt64{Int64: 1, Valid: true},
+				BuildID: sql.NullInt64{Int64: 1, Valid: true},
+				Name:    sql.NullString{String: "coverage", Valid: true},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.artifact.Validate()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("Validate should have returned err")
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Validate returned err: %v", err)
+		}
+	}
+}
+
+func TestDatabase_ArtifactFromLibrary(t *testing.T) {
+	// setup types
+	a := new(library.Artifact)
+
+	a.SetID(1)
+	a.SetBuildID(1)
+	a.SetName("coverage")
+	a.SetStep("test")
+	a.SetPath("github/octocat/1/coverage.tar.gz")
+	a.SetSize(1024)
+	a.SetCreatedAt(1563474076)
+	a.SetExpiresAt(1563560476)
+
+	want := testArtifact()
+
+	// run test
+	got := ArtifactFromLibrary(a)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ArtifactFromLibrary is %v, want %v", got, want)
+	}
+}
+
+// testArtifact is a test helper function to create a Artifact
+// type with all fields set to a fake value.
+func testArtifact() *Artifact {
+	return &Artifact{
+		ID:        sql.NullInt64{Int64: 1, Valid: true},
+		BuildID:   sql.NullInt64{Int64: 1, Valid: true},
+		Name:      sql.NullString{String: "coverage", Valid: true},
+		Step:      sql.NullString{String: "test", Valid: true},
+		Path:      sql.NullString{String: "github/octocat/1/coverage.tar.gz", Valid: true},
+		Size:      sql.NullInt64{Int64: 1024, Valid: true},
+		CreatedAt: sql.NullInt64{Int64: 1563474076, Valid: true},
+		ExpiresAt: sql.NullInt64{Int64: 1563560476, Valid: true},
+	}
+}
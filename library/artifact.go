@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"fmt"
+)
+
+// Artifact is the API representation of an artifact
+// uploaded by a step for a build.
+//
+// Deprecated: use Artifact from github.com/go-vela/server/api/types instead.
+type Artifact struct {
+	ID        *int64  `json:"id,omitempty"`
+	BuildID   *int64  `json:"build_id,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Step      *string `json:"step,omitempty"`
+	Path      *string `json:"path,omitempty"`
+	Size      *int64  `json:"size,omitempty"`
+	CreatedAt *int64  `json:"created_at,omitempty"`
+	ExpiresAt *int64  `json:"expires_at,omitempty"`
+}
+
+// GetID returns the ID field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetID() int64 {
+	// return zero value if Artifact type or ID field is nil
+	if a == nil || a.ID == nil {
+		return 0
+	}
+
+	return *a.ID
+}
+
+// GetBuildID returns the BuildID field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetBuildID() int64 {
+	// return zero value if Artifact type or BuildID field is nil
+	if a == nil || a.BuildID == nil {
+		return 0
+	}
+
+	return *a.BuildID
+}
+
+// GetName returns the Name field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetName() string {
+	// return zero value if Artifact type or Name field is nil
+	if a == nil || a.Name == nil {
+		return ""
+	}
+
+	return *a.Name
+}
+
+// GetStep returns the Step field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetStep() string {
+	// return zero value if Artifact type or Step field is nil
+	if a == nil || a.Step == nil {
+		return ""
+	}
+
+	return *a.Step
+}
+
+// GetPath returns the Path field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetPath() string {
+	// return zero value if Artifact type or Path field is nil
+	if a == nil || a.Path == nil {
+		return ""
+	}
+
+	return *a.Path
+}
+
+// GetSize returns the Size field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetSize() int64 {
+	// return zero value if Artifact type or Size field is nil
+	if a == nil || a.Size == nil {
+		return 0
+	}
+
+	return *a.Size
+}
+
+// GetCreatedAt returns the CreatedAt field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetCreatedAt() int64 {
+	// return zero value if Artifact type or CreatedAt field is nil
+	if a == nil || a.CreatedAt == nil {
+		return 0
+	}
+
+	return *a.CreatedAt
+}
+
+// GetExpiresAt returns the ExpiresAt field from the provided Artifact. If the object is nil,
+// or the field within the object is nil, it returns the zero value instead.
+func (a *Artifact) GetExpiresAt() int64 {
+	// return zero value if Artifact type or ExpiresAt field is nil
+	if a == nil || a.ExpiresAt == nil {
+		return 0
+	}
+
+	return *a.ExpiresAt
+}
+
+// SetID sets the ID field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetID(id int64) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.ID = &id
+}
+
+// SetBuildID sets the BuildID field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetBuildID(buildID int64) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.BuildID = &buildID
+}
+
+// SetName sets the Name field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetName(name string) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.Name = &name
+}
+
+// SetStep sets the Step field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetStep(step string) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.Step = &step
+}
+
+// SetPath sets the Path field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetPath(path string) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.Path = &path
+}
+
+// SetSize sets the Size field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetSize(size int64) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.Size = &size
+}
+
+// SetCreatedAt sets the CreatedAt field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetCreatedAt(createdAt int64) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.CreatedAt = &createdAt
+}
+
+// SetExpiresAt sets the ExpiresAt field in the provided Artifact. If the object is nil,
+// it will set nothing and immediately return making this a no-op.
+func (a *Artifact) SetExpiresAt(expiresAt int64) {
+	// return if Artifact type is nil
+	if a == nil {
+		return
+	}
+
+	a.ExpiresAt = &expiresAt
+}
+
+// String implements the Stringer interface for the Artifact type.
+func (a *Artifact) String() string {
+	return fmt.Sprintf(`{
+  BuildID: %d,
+  CreatedAt: %d,
+  ExpiresAt: %d,
+  ID: %d,
+  Name: %s,
+  Path: %s,
+  Size: %d,
+  Step: %s,
+}`,
+		a.GetBuildID(),
+		a.GetCreatedAt(),
+		a.GetExpiresAt(),
+		a.GetID(),
+		a.GetName(),
+		a.GetPath(),
+		a.GetSize(),
+		a.GetStep(),
+	)
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLibrary_Artifact_Getters(t *testing.T) {
+	tests := []struct {
+		name     string
+		artifact *Artifact
+		want     *Artifact
+	}{
+		{
+			name:     "artifact with fields",
+			artifact: testArtifact(),
+			want:     testArtifact(),
+		},
+		{
+			name:     "artifact with empty fields",
+			artifact: new(Artifact),
+			want:     new(Artifact),
+		},
+		{
+			name:     "empty artifact",
+			artifact: nil,
+			want:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.artifact.GetID() != test.want.GetID() {
+				t.Errorf("GetID is %v, want %v", test.artifact.GetID(), test.want.GetID())
+			}
+
+			if test.artifact.GetBuildID() != test.want.GetBuildID() {
+				t.Errorf("GetBuildID is %v, want %v", test.artifact.GetBuildID(), test.want.GetBuildID())
+			}
+
+			if test.artifact.GetName() != test.want.GetName() {
+				t.Errorf("GetName is %v, want %v", test.artifact.GetName(), test.want.GetName())
+			}
+
+			if test.artifact.GetStep() != test.want.GetStep() {
+				t.Errorf("GetStep is %v, want %v", test.artifact.GetStep(), test.want.GetStep())
+			}
+
+			if test.artifact.GetPath() != test.want.GetPath() {
+				t.Errorf("GetPath is %v, want %v", test.artifact.GetPath(), test.want.GetPath())
+			}
+
+			if test.artifact.GetSize() != test.want.GetSize() {
+				t.Errorf("GetSize is %v, want %v", test.artifact.GetSize(), test.want.GetSize())
+			}
+
+			if test.artifact.GetCreatedAt() != test.want.GetCreatedAt() {
+				t.Errorf("GetCreatedAt is %v, want %v", test.artifact.GetCreatedAt(), test.want.GetCreatedAt())
+			}
+
+			if test.artifact.GetExpiresAt() != test.want.GetExpiresAt() {
+				t.Errorf("GetExpiresAt is %v, want %v", test.artifact.GetExpiresAt(), test.want.GetExpiresAt())
+			}
+		})
+	}
+}
+
+func TestLibrary_Artifact_Setters(t *testing.T) {
+	tests := []struct {
+		name     string
+		artifact *Artifact
+		want     *Artifact
+	}{
+		{
+			name:     "artifact with fields",
+			artifact: testArtifact(),
+			want:     testArtifact(),
+		},
+		{
+			name:     "artifact with empty fields",
+			artifact: new(Artifact),
+			want:     new(Artifact),
+		},
+		{
+			name:     "empty artifact",
+			artifact: nil,
+			want:     nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.artifact.SetID(test.want.GetID())
+			test.artifact.SetBuildID(test.want.GetBuildID())
+			test.artifact.SetName(test.want.GetName())
+			test.artifact.SetStep(test.want.GetStep())
+			test.artifact.SetPath(test.want.GetPath())
+			test.artifact.SetSize(test.want.GetSize())
+			test.artifact.SetCreatedAt(test.want.GetCreatedAt())
+			test.artifact.SetExpiresAt(test.want.GetExpiresAt())
+			if test.artifact.GetID() != test.want.GetID() {
+				t.Errorf("SetID is %v, want %v", test.artifact.GetID(), test.want.GetID())
+			}
+
+			if test.artifact.GetBuildID() != test.want.GetBuildID() {
+				t.Errorf("SetBuildID is %v, want %v", test.artifact.GetBuildID(), test.want.GetBuildID())
+			}
+
+			if test.artifact.GetName() != test.want.GetName() {
+				t.Errorf("SetName is %v, want %v", test.artifact.GetName(), test.want.GetName())
+			}
+
+			if test.artifact.GetStep() != test.want.GetStep() {
+				t.Errorf("SetStep is %v, want %v", test.artifact.GetStep(), test.want.GetStep())
+			}
+
+			if test.artifact.GetPath() != test.want.GetPath() {
+				t.Errorf("SetPath is %v, want %v", test.artifact.GetPath(), test.want.GetPath())
+			}
+
+			if test.artifact.GetSize() != test.want.GetSize() {
+				t.Errorf("SetSize is %v, want %v", test.artifact.GetSize(), test.want.GetSize())
+			}
+
+			if test.artifact.GetCreatedAt() != test.want.GetCreatedAt() {
+				t.Errorf("SetCreatedAt is %v, want %v", test.artifact.GetCreatedAt(), test.want.GetCreatedAt())
+			}
+
+			if test.artifact.GetExpiresAt() != test.want.GetExpiresAt() {
+				t.Errorf("SetExpiresAt is %v, want %v", test.artifact.GetExpiresAt(), test.want.GetExpiresAt())
+			}
+		})
+	}
+}
+
+func TestLibrary_Artifact_String(t *testing.T) {
+	a := testArtifact()
+
+	want := fmt.Sprintf(`{
+  BuildID: %d,
+  CreatedAt: %d,
+  ExpiresAt: %d,
+  ID: %d,
+  Name: %s,
+  Path: %s,
+  Size: %d,
+  Step: %s,
+}`,
+		a.GetBuildID(),
+		a.GetCreatedAt(),
+		a.GetExpiresAt(),
+		a.GetID(),
+		a.GetName(),
+		a.GetPath(),
+		a.GetSize(),
+		a.GetStep(),
+	)
+
+	got := a.String()
+	if !strings.EqualFold(got, want) {
+		t.Errorf("String is %v, want %v", got, want)
+	}
+}
+
+// testArtifact is a test helper function to create a Artifact
+// type with all fields set to a fake value.
+func testArtifact() *Artifact {
+	a := new(Artifact)
+
+	a.SetID(1)
+	a.SetBuildID(1)
+	a.SetName("coverage")
+	a.SetStep("test")
+	a.SetPath("github/octocat/1/coverage.tar.gz")
+	a.SetSize(1024)
+	a.SetCreatedAt(time.Now().UTC().Unix())
+	a.SetExpiresAt(time.Now().Add(time.Hour * 24).UTC().Unix())
+
+	return a
+}
@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"github.com/go-vela/types/pipeline"
+)
+
+// RenderCache returns a copy of the provided cache with the key and
+// restore keys resolved from the environment for the provided build
+// and repo. The workspace and channel for the build are not known
+// when the keys are rendered, so those variables resolve to empty
+// values. The rendered keys are prefixed with the org, and the repo
+// for repo scoped caches, so executors and plugins using this
+// function agree on the keys for a cache.
+//
+// Deprecated: use RenderCache from github.com/go-vela/server/api/types instead.
+func RenderCache(c *pipeline.Cache, b *Build, r *Repo) (*pipeline.Cache, error) {
+	env := make(map[string]string)
+
+	// capture the environment for the repo
+	for key, value := range r.Environment() {
+		env[key] = value
+	}
+
+	// capture the environment for the build
+	for key, value := range b.Environment("", "") {
+		env[key] = value
+	}
+
+	return c.Render(env, r.GetOrg(), r.GetName())
+}
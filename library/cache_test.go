@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package library
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+)
+
+func TestLibrary_RenderCache(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		cache   *pipeline.Cache
+		want    *pipeline.Cache
+		failure bool
+	}{
+		{
+			cache: &pipeline.Cache{
+				Key:         "go-${VELA_BUILD_BRANCH}-${VELA_BUILD_COMMIT}",
+				Paths:       []string{".cache/go-build"},
+				RestoreKeys: []string{"go-${VELA_BUILD_BRANCH}-", "go-"},
+			},
+			want: &pipeline.Cache{
+				Key:         "github/octocat/go-main-48afb5bdc41ad69bf22588491333f7cf71135163",
+				Paths:       []string{".cache/go-build"},
+				RestoreKeys: []string{"github/octocat/go-main-", "github/octocat/go-"},
+				Scope:       constants.CacheScopeRepo,
+			},
+		},
+		{
+			cache: &pipeline.Cache{
+				Key:   "node-${VELA_REPO_BRANCH}",
+				Paths: []string{"node_modules"},
+				Scope: constants.CacheScopeOrg,
+			},
+			want: &pipeline.Cache{
+				Key:   "github/node-main",
+				Paths: []string{"node_modules"},
+				Scope: constants.CacheScopeOrg,
+			},
+		},
+		{
+			cache: &pipeline.Cache{
+				Key:   "go-${FOO}",
+				Paths: []string{".cache/go-build"},
+			},
+			failure: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := RenderCache(test.cache, testBuild(), testRepo())
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("RenderCache for %s should have returned err", test.cache.Key)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("RenderCache for %s returned err: %v", test.cache.Key, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("RenderCache for %s is %v, want %v", test.cache.Key, got, test.want)
+		}
+	}
+}
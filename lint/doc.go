@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lint provides a lint engine for Vela pipelines.
+//
+// Beyond the validation provided by the yaml package, the
+// engine walks a pipeline and reports best-practice issues
+// with rules that can be individually toggled and suppressed
+// inline with a comment in the pipeline, i.e.
+//
+//	steps:
+//	  - name: docker # vela-lint:disable privileged
+//	    image: docker:dind
+//	    privileged: true
+//
+// A comment without rule IDs disables every rule and any text
+// after "--" is ignored, so it can explain the suppression. A
+// comment at the top of the pipeline applies to the whole
+// pipeline while a comment on the first key of a step applies
+// to the whole step.
+//
+// Deprecated: use github.com/go-vela/server instead.
+package lint
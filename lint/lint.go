@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	bkyaml "github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/yaml"
+)
+
+var (
+	// ErrUnknownRule defines the error type when
+	// the provided rule ID does not exist.
+	ErrUnknownRule = errors.New("unknown lint rule")
+
+	// ErrInvalidSeverity defines the error type when
+	// the provided severity is not supported.
+	ErrInvalidSeverity = errors.New("invalid lint severity")
+)
+
+type (
+	// Issue is a single best-practice issue
+	// found while linting a pipeline.
+	Issue struct {
+		Rule     string `json:"rule"`
+		Severity string `json:"severity"`
+		Path     string `json:"path"`
+		Message  string `json:"message"`
+		Line     int    `json:"line,omitempty"`
+		Column   int    `json:"column,omitempty"`
+	}
+
+	// Issues is a list of best-practice
+	// issues found while linting a pipeline.
+	Issues []*Issue
+
+	// Finding is a single problem reported by the
+	// check for a rule. The rule and severity are
+	// added by the linter when creating an issue.
+	Finding struct {
+		Path    string
+		Message string
+	}
+
+	// Rule is a single best-practice check for a pipeline.
+	Rule struct {
+		// ID is the unique identifier used to toggle and suppress the rule.
+		ID string
+		// Severity is the default severity for issues reported by the rule.
+		Severity string
+		// Description explains the best practice enforced by the rule.
+		Description string
+		// Check returns the problems found in the provided pipeline.
+		Check func(b *yaml.Build) []*Finding
+	}
+
+	// Linter runs a set of rules against a pipeline.
+	Linter struct {
+		rules    []*Rule
+		disabled map[string]bool
+		severity map[string]string
+	}
+)
+
+// String implements the Stringer interface for the Issue type.
+func (i *Issue) String() string {
+	// check if a position was resolved for the issue
+	if i.Line > 0 {
+		return fmt.Sprintf("%d:%d: %s: %s: %s (%s)", i.Line, i.Column, i.Severity, i.Path, i.Message, i.Rule)
+	}
+
+	return fmt.Sprintf("%s: %s: %s (%s)", i.Severity, i.Path, i.Message, i.Rule)
+}
+
+// String implements the Stringer interface for the Issues type.
+func (i Issues) String() string {
+	lines := []string{}
+
+	for _, issue := range i {
+		lines = append(lines, issue.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// HasErrors returns true if any of the
+// issues has the error severity.
+func (i Issues) HasErrors() bool {
+	for _, issue := range i {
+		if issue.Severity == constants.SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findingf is a helper function to append a finding
+// with the provided path and message to the findings.
+func findingf(findings *[]*Finding, path, format string, args ...interface{}) {
+	*findings = append(*findings, &Finding{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// New returns a Linter for the provided rules. When
+// no rules are provided, the default rules are used.
+func New(rules ...*Rule) *Linter {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	return &Linter{
+		rules:    rules,
+		disabled: make(map[string]bool),
+		severity: make(map[string]string),
+	}
+}
+
+// Rules returns the rules for the Linter.
+func (l *Linter) Rules() []*Rule {
+	return l.rules
+}
+
+// Enable turns on the rules for the provided IDs.
+func (l *Linter) Enable(ids ...string) error {
+	for _, id := range ids {
+		if l.rule(id) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownRule, id)
+		}
+
+		delete(l.disabled, id)
+	}
+
+	return nil
+}
+
+// Disable turns off the rules for the provided IDs.
+func (l *Linter) Disable(ids ...string) error {
+	for _, id := range ids {
+		if l.rule(id) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownRule, id)
+		}
+
+		l.disabled[id] = true
+	}
+
+	return nil
+}
+
+// SetSeverity overrides the severity for the rule with the provided ID.
+func (l *Linter) SetSeverity(id, severity string) error {
+	if l.rule(id) == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownRule, id)
+	}
+
+	switch severity {
+	case constants.SeverityError, constants.SeverityWarning, constants.SeverityInfo:
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidSeverity, severity)
+	}
+
+	l.severity[id] = severity
+
+	return nil
+}
+
+// Lint runs every enabled rule against the provided
+// pipeline and returns the issues that were found.
+func (l *Linter) Lint(b *yaml.Build) Issues {
+	issues := Issues{}
+
+	// iterate through each rule
+	for _, rule := range l.rules {
+		// skip the rule if it was disabled
+		if l.disabled[rule.ID] {
+			continue
+		}
+
+		severity := rule.Severity
+		if override, ok := l.severity[rule.ID]; ok {
+			severity = override
+		}
+
+		for _, finding := range rule.Check(b) {
+			issues = append(issues, &Issue{
+				Rule:     rule.ID,
+				Severity: severity,
+				Path:     finding.Path,
+				Message:  finding.Message,
+			})
+		}
+	}
+
+	return issues
+}
+
+// LintBytes unmarshals the provided raw YAML document to a
+// Build type and lints it. The line and column for every
+// issue is resolved from the document and issues suppressed
+// by a comment in the document are removed.
+func (l *Linter) LintBytes(data []byte) (Issues, error) {
+	b := new(yaml.Build)
+
+	// attempt to unmarshal the document as a build
+	err := bkyaml.Unmarshal(data, b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal yaml: %w", err)
+	}
+
+	source, err := yaml.NewSourceMap(data)
+	if err != nil {
+		return nil, err
+	}
+
+	suppressed, err := suppressions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := Issues{}
+
+	// iterate through each issue
+	for _, issue := range l.Lint(b) {
+		issue.Line, issue.Column = source.Locate(issue.Path)
+
+		if suppressed.matches(issue) {
+			continue
+		}
+
+		issues = append(issues, issue)
+	}
+
+	// order the issues by their position in the document
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+
+		return issues[i].Column < issues[j].Column
+	})
+
+	return issues, nil
+}
+
+// rule is a helper function to return the
+// rule for the provided ID if it exists.
+func (l *Linter) rule(id string) *Rule {
+	for _, rule := range l.rules {
+		if rule.ID == id {
+			return rule
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/yaml"
+)
+
+func TestLint_Linter_LintBytes(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/pipeline.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	want := []string{
+		"7:5: warning: services[1].name: service redis is not referenced by any step (unused-service)",
+		"8:5: warning: services[1].image: image redis does not have a tag (image-latest)",
+		"14:5: warning: secrets[1].name: secret unused is not used by any step (unused-secret)",
+		"19:5: warning: steps[0].image: image golang:latest uses the latest tag (image-latest)",
+		"27:5: info: steps[1].pull: image target/vela-docker@sha256:2b5ae3b3bf9a6e55fd43bf2e6a4a9e9a6b1d3a0e1b6e0d1a9e8c7b6a5f4e3d2c is pinned to a digest and does not need to be pulled always (pull-always-digest)",
+		"29:5: error: steps[1].secrets: secrets for step publish are only injected with commands when the secret allows commands (secret-commands)",
+		"42:7: info: steps[3].ruleset.if.status: step cleanup runs on the build status without continue (status-without-continue)",
+	}
+
+	// run test
+	got, err := New().LintBytes(data)
+	if err != nil {
+		t.Errorf("LintBytes returned err: %v", err)
+	}
+
+	lines := []string{}
+	for _, issue := range got {
+		lines = append(lines, issue.String())
+	}
+
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("LintBytes is %v, want %v", lines, want)
+	}
+
+	if !got.HasErrors() {
+		t.Errorf("HasErrors is false, want true")
+	}
+}
+
+func TestLint_Linter_LintBytes_Suppressed(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/suppressed.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	got, err := New().LintBytes(data)
+	if err != nil {
+		t.Errorf("LintBytes returned err: %v", err)
+	}
+
+	if len(got) > 0 {
+		t.Errorf("LintBytes is %v, want no issues", got)
+	}
+}
+
+func TestLint_Linter_LintBytes_Invalid(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/invalid.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	_, err = New().LintBytes(data)
+	if err == nil {
+		t.Errorf("LintBytes should have returned err")
+	}
+}
+
+func TestLint_Linter_Toggle(t *testing.T) {
+	// setup types
+	b := &yaml.Build{
+		Steps: yaml.StepSlice{
+			{Name: "docker", Image: "docker", Privileged: true},
+		},
+	}
+
+	l := New()
+
+	// run test
+	err := l.Disable(RuleImageLatest)
+	if err != nil {
+		t.Errorf("Disable returned err: %v", err)
+	}
+
+	err = l.SetSeverity(RulePrivileged, constants.SeverityError)
+	if err != nil {
+		t.Errorf("SetSeverity returned err: %v", err)
+	}
+
+	got := l.Lint(b)
+
+	if len(got) != 1 || got[0].Rule != RulePrivileged || got[0].Severity != constants.SeverityError {
+		t.Errorf("Lint is %v, want privileged error", got)
+	}
+
+	err = l.Enable(RuleImageLatest)
+	if err != nil {
+		t.Errorf("Enable returned err: %v", err)
+	}
+
+	got = l.Lint(b)
+
+	if len(got) != 2 {
+		t.Errorf("Lint is %v, want 2 issues", got)
+	}
+}
+
+func TestLint_Linter_Toggle_Invalid(t *testing.T) {
+	// setup types
+	l := New()
+
+	// run tests
+	err := l.Enable("foo")
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Enable returned err %v, want %v", err, ErrUnknownRule)
+	}
+
+	err = l.Disable("foo")
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Disable returned err %v, want %v", err, ErrUnknownRule)
+	}
+
+	err = l.SetSeverity("foo", constants.SeverityError)
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("SetSeverity returned err %v, want %v", err, ErrUnknownRule)
+	}
+
+	err = l.SetSeverity(RulePrivileged, "fatal")
+	if !errors.Is(err, ErrInvalidSeverity) {
+		t.Errorf("SetSeverity returned err %v, want %v", err, ErrInvalidSeverity)
+	}
+}
+
+func TestLint_New_Rules(t *testing.T) {
+	// setup types
+	rule := &Rule{
+		ID:       "custom",
+		Severity: constants.SeverityInfo,
+		Check: func(b *yaml.Build) []*Finding {
+			return []*Finding{{Path: "version", Message: "custom finding"}}
+		},
+	}
+
+	// run test
+	got := New(rule).Lint(new(yaml.Build))
+
+	want := Issues{{Rule: "custom", Severity: constants.SeverityInfo, Path: "version", Message: "custom finding"}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Lint is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,309 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/yaml"
+)
+
+const (
+	// RulePullAlwaysDigest defines the ID for the rule that reports
+	// images pinned to a digest that are always pulled.
+	RulePullAlwaysDigest = "pull-always-digest"
+
+	// RuleImageLatest defines the ID for the rule that reports
+	// images without a tag or with the latest tag.
+	RuleImageLatest = "image-latest"
+
+	// RulePrivileged defines the ID for the rule
+	// that reports steps running privileged.
+	RulePrivileged = "privileged"
+
+	// RuleSecretCommands defines the ID for the rule that reports
+	// secrets injected into steps with commands or an entrypoint.
+	RuleSecretCommands = "secret-commands"
+
+	// RuleUnusedService defines the ID for the rule that
+	// reports services not referenced by any step.
+	RuleUnusedService = "unused-service"
+
+	// RuleStatusWithoutContinue defines the ID for the rule that
+	// reports rulesets matching on status without continue.
+	RuleStatusWithoutContinue = "status-without-continue"
+)
+
+type (
+	// step represents a step in a pipeline
+	// with its path in the document.
+	step struct {
+		path string
+		step *yaml.Step
+	}
+
+	// image represents an image used by a container
+	// in a pipeline with its path in the document.
+	image struct {
+		path  string
+		image string
+		pull  string
+	}
+)
+
+// DefaultRules returns the default rules for linting a pipeline.
+func DefaultRules() []*Rule {
+	return []*Rule{
+		{
+			ID:          RulePullAlwaysDigest,
+			Severity:    constants.SeverityInfo,
+			Description: "An image pinned to a digest never changes so it does not need to be pulled every time.",
+			Check:       checkPullAlwaysDigest,
+		},
+		{
+			ID:          RuleImageLatest,
+			Severity:    constants.SeverityWarning,
+			Description: "An image without a tag or with the latest tag can change between builds.",
+			Check:       checkImageLatest,
+		},
+		{
+			ID:          RulePrivileged,
+			Severity:    constants.SeverityWarning,
+			Description: "A privileged step has extended access to the host running the build.",
+			Check:       checkPrivileged,
+		},
+		{
+			ID:          RuleSecretCommands,
+			Severity:    constants.SeverityError,
+			Description: "Secrets are only injected into steps with commands or an entrypoint when the secret allows commands.",
+			Check:       checkSecretCommands,
+		},
+		{
+			ID:          RuleUnusedSecret,
+			Severity:    constants.SeverityWarning,
+			Description: "A declared secret that is not used by any step is fetched for nothing.",
+			Check:       checkUnusedSecret,
+		},
+		{
+			ID:          RuleUnusedService,
+			Severity:    constants.SeverityWarning,
+			Description: "A service that is not referenced by any step slows down the build for nothing.",
+			Check:       checkUnusedService,
+		},
+		{
+			ID:          RuleStatusWithoutContinue,
+			Severity:    constants.SeverityInfo,
+			Description: "A step running on the status of the build should continue so its failure does not fail the build.",
+			Check:       checkStatusWithoutContinue,
+		},
+		{
+			ID:          RuleUndeclaredSecret,
+			Severity:    constants.SeverityError,
+			Description: "A secret used by a step must be declared in the secrets block or provided by the items of an origin. Steps are not checked when an origin has no items.",
+			Check:       checkUndeclaredSecret,
+		},
+		{
+			ID:          RuleDuplicateSecretTarget,
+			Severity:    constants.SeverityError,
+			Description: "Secrets injected into the same environment variable overwrite each other.",
+			Check:       checkDuplicateSecretTarget,
+		},
+		{
+			ID:          RuleReservedSecretTarget,
+			Severity:    constants.SeverityError,
+			Description: "A secret injected into an environment variable reserved by Vela overwrites the build information.",
+			Check:       checkReservedSecretTarget,
+		},
+	}
+}
+
+// checkPullAlwaysDigest reports images pinned to a digest that are always pulled.
+func checkPullAlwaysDigest(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, i := range images(b) {
+		if i.pull == constants.PullAlways && strings.Contains(i.image, "@") {
+			findingf(&findings, i.path+".pull", "image %s is pinned to a digest and does not need to be pulled always", i.image)
+		}
+	}
+
+	return findings
+}
+
+// checkImageLatest reports images without a tag or with the latest tag.
+func checkImageLatest(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, i := range images(b) {
+		// skip images pinned to a digest
+		if len(i.image) == 0 || strings.Contains(i.image, "@") {
+			continue
+		}
+
+		// remove the registry and repository from the image
+		name := i.image[strings.LastIndex(i.image, "/")+1:]
+
+		_, tag, ok := strings.Cut(name, ":")
+
+		switch {
+		case !ok:
+			findingf(&findings, i.path+".image", "image %s does not have a tag", i.image)
+		case tag == "latest":
+			findingf(&findings, i.path+".image", "image %s uses the latest tag", i.image)
+		}
+	}
+
+	return findings
+}
+
+// checkPrivileged reports steps running privileged.
+func checkPrivileged(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, s := range steps(b) {
+		if s.step.Privileged {
+			findingf(&findings, s.path+".privileged", "step %s runs privileged", s.step.Name)
+		}
+	}
+
+	return findings
+}
+
+// checkSecretCommands reports secrets injected into steps with
+// commands or an entrypoint. By default, a secret does not allow
+// commands so it is not injected into these steps.
+func checkSecretCommands(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, s := range steps(b) {
+		if len(s.step.Secrets) == 0 {
+			continue
+		}
+
+		switch {
+		case len(s.step.Commands) > 0:
+			findingf(&findings, s.path+".secrets", "secrets for step %s are only injected with commands when the secret allows commands", s.step.Name)
+		case len(s.step.Entrypoint) > 0:
+			findingf(&findings, s.path+".secrets", "secrets for step %s are only injected with an entrypoint when the secret allows commands", s.step.Name)
+		}
+	}
+
+	return findings
+}
+
+// checkUnusedService reports services not referenced by any step.
+// A service is referenced by its name, which is the hostname for
+// the service, in the commands, entrypoint, environment or
+// parameters for a step.
+func checkUnusedService(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	references := []string{}
+
+	for _, s := range steps(b) {
+		references = append(references, s.step.Commands...)
+		references = append(references, s.step.Entrypoint...)
+
+		for _, value := range s.step.Environment {
+			references = append(references, value)
+		}
+
+		if len(s.step.Parameters) > 0 {
+			references = append(references, fmt.Sprint(s.step.Parameters))
+		}
+	}
+
+	text := strings.Join(references, "\n")
+
+	for i, service := range b.Services {
+		if len(service.Name) == 0 {
+			continue
+		}
+
+		pattern := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(service.Name) + `($|[^\w-])`)
+
+		if !pattern.MatchString(text) {
+			findingf(&findings, fmt.Sprintf("services[%d].name", i), "service %s is not referenced by any step", service.Name)
+		}
+	}
+
+	return findings
+}
+
+// checkStatusWithoutContinue reports rulesets matching on status without continue.
+func checkStatusWithoutContinue(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, s := range steps(b) {
+		ruleset := s.step.Ruleset
+
+		if ruleset.Continue {
+			continue
+		}
+
+		switch {
+		case len(ruleset.If.Status) > 0:
+			findingf(&findings, s.path+".ruleset.if.status", "step %s runs on the build status without continue", s.step.Name)
+		case len(ruleset.Unless.Status) > 0:
+			findingf(&findings, s.path+".ruleset.unless.status", "step %s runs on the build status without continue", s.step.Name)
+		}
+	}
+
+	return findings
+}
+
+// steps is a helper function to return the steps in
+// the provided pipeline, including steps in stages.
+func steps(b *yaml.Build) []*step {
+	s := []*step{}
+
+	for _, stage := range b.Stages {
+		for i, st := range stage.Steps {
+			s = append(s, &step{path: fmt.Sprintf("stages.%s.steps[%d]", key(stage.Name), i), step: st})
+		}
+	}
+
+	for i, st := range b.Steps {
+		s = append(s, &step{path: fmt.Sprintf("steps[%d]", i), step: st})
+	}
+
+	return s
+}
+
+// key is a helper function to return the provided name as a key
+// for a path, quoting the name when it contains a dot or bracket.
+func key(name string) string {
+	if strings.ContainsAny(name, ".[]\"") {
+		return strconv.Quote(name)
+	}
+
+	return name
+}
+
+// images is a helper function to return the images
+// for every container in the provided pipeline.
+func images(b *yaml.Build) []*image {
+	i := []*image{}
+
+	for _, s := range steps(b) {
+		i = append(i, &image{path: s.path, image: s.step.Image, pull: s.step.Pull})
+	}
+
+	for index, service := range b.Services {
+		i = append(i, &image{path: fmt.Sprintf("services[%d]", index), image: service.Image, pull: service.Pull})
+	}
+
+	for index, secret := range b.Secrets {
+		if secret.Origin.Empty() {
+			continue
+		}
+
+		i = append(i, &image{path: fmt.Sprintf("secrets[%d].origin", index), image: secret.Origin.Image, pull: secret.Origin.Pull})
+	}
+
+	return i
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/raw"
+	"github.com/go-vela/types/yaml"
+)
+
+func TestLint_DefaultRules(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		check func(b *yaml.Build) []*Finding
+		build *yaml.Build
+		want  []string
+	}{
+		{
+			name:  "pull always digest",
+			check: checkPullAlwaysDigest,
+			build: &yaml.Build{
+				Services: yaml.ServiceSlice{{Name: "db", Image: "postgres@sha256:1234", Pull: "always"}},
+				Steps: yaml.StepSlice{
+					{Name: "test", Image: "golang@sha256:1234", Pull: "not_present"},
+					{Name: "build", Image: "golang:1.23", Pull: "always"},
+				},
+			},
+			want: []string{"services[0].pull"},
+		},
+		{
+			name:  "image latest",
+			check: checkImageLatest,
+			build: &yaml.Build{
+				Secrets: yaml.SecretSlice{{Name: "vault", Origin: yaml.Origin{Name: "vault", Image: "target/secret-vault"}}},
+				Stages: yaml.StageSlice{{Name: "test", Steps: yaml.StepSlice{
+					{Name: "test", Image: "localhost:5000/golang"},
+					{Name: "lint", Image: "golang:latest"},
+					{Name: "build", Image: "golang:1.23"},
+					{Name: "pinned", Image: "golang@sha256:1234"},
+					{Name: "template", Template: yaml.StepTemplate{Name: "go"}},
+				}}},
+			},
+			want: []string{"stages.test.steps[0].image", "stages.test.steps[1].image", "secrets[0].origin.image"},
+		},
+		{
+			name:  "privileged",
+			check: checkPrivileged,
+			build: &yaml.Build{
+				Stages: yaml.StageSlice{{Name: "build.v1", Steps: yaml.StepSlice{{Name: "docker", Privileged: true}}}},
+				Steps:  yaml.StepSlice{{Name: "docker", Privileged: true}, {Name: "test"}},
+			},
+			want: []string{`stages."build.v1".steps[0].privileged`, "steps[0].privileged"},
+		},
+		{
+			name:  "secret commands",
+			check: checkSecretCommands,
+			build: &yaml.Build{Steps: yaml.StepSlice{
+				{Name: "commands", Commands: raw.StringSlice{"echo"}, Secrets: yaml.StepSecretSlice{{Source: "foo", Target: "FOO"}}},
+				{Name: "entrypoint", Entrypoint: raw.StringSlice{"sh"}, Secrets: yaml.StepSecretSlice{{Source: "foo", Target: "FOO"}}},
+				{Name: "plugin", Secrets: yaml.StepSecretSlice{{Source: "foo", Target: "FOO"}}},
+				{Name: "none", Commands: raw.StringSlice{"echo"}},
+			}},
+			want: []string{"steps[0].secrets", "steps[1].secrets"},
+		},
+		{
+			name:  "unused secret",
+			check: checkUnusedSecret,
+			build: &yaml.Build{
+				Secrets: yaml.SecretSlice{
+					{Name: "foo"},
+					{Name: "bar"},
+					{Name: "token"},
+					{Origin: yaml.Origin{Name: "vault", Secrets: yaml.StepSecretSlice{{Source: "token", Target: "VAULT_TOKEN"}}}},
+				},
+				Steps: yaml.StepSlice{{Name: "test", Secrets: yaml.StepSecretSlice{{Source: "foo", Target: "FOO"}}}},
+			},
+			want: []string{"secrets[1].name"},
+		},
+		{
+			name:  "unused service",
+			check: checkUnusedService,
+			build: &yaml.Build{
+				Services: yaml.ServiceSlice{
+					{Name: "postgres"},
+					{Name: "redis"},
+					{Name: "db"},
+					{Name: "vault"},
+					{Name: "mysql"},
+				},
+				Steps: yaml.StepSlice{{
+					Name:        "test",
+					Commands:    raw.StringSlice{"redis-cli -h redis ping"},
+					Environment: raw.StringSliceMap{"DATABASE_URL": "postgres://postgres:5432", "MONGO": "mongodb:27017"},
+					Parameters:  map[string]interface{}{"addr": "http://vault:8200"},
+				}},
+			},
+			want: []string{"services[2].name", "services[4].name"},
+		},
+		{
+			name:  "status without continue",
+			check: checkStatusWithoutContinue,
+			build: &yaml.Build{Steps: yaml.StepSlice{
+				{Name: "if", Ruleset: yaml.Ruleset{If: yaml.Rules{Status: []string{"failure"}}}},
+				{Name: "unless", Ruleset: yaml.Ruleset{Unless: yaml.Rules{Status: []string{"success"}}}},
+				{Name: "continue", Ruleset: yaml.Ruleset{If: yaml.Rules{Status: []string{"failure"}}, Continue: true}},
+				{Name: "none"},
+			}},
+			want: []string{"steps[0].ruleset.if.status", "steps[1].ruleset.unless.status"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := []string{}
+
+		for _, finding := range test.check(test.build) {
+			got = append(got, finding.Path)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s findings are %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/library"
+	"github.com/go-vela/types/yaml"
+)
+
+const (
+	// RuleUndeclaredSecret defines the ID for the rule that
+	// reports secrets used by a step that are not declared.
+	RuleUndeclaredSecret = "undeclared-secret"
+
+	// RuleUnusedSecret defines the ID for the rule that
+	// reports declared secrets not used by any step.
+	RuleUnusedSecret = "unused-secret"
+
+	// RuleDuplicateSecretTarget defines the ID for the rule that
+	// reports secrets injected into the same target in a step.
+	RuleDuplicateSecretTarget = "duplicate-secret-target"
+
+	// RuleReservedSecretTarget defines the ID for the rule that reports
+	// secrets injected into a target reserved for the build information.
+	RuleReservedSecretTarget = "reserved-secret-target"
+)
+
+// reserved represents the environment variables
+// set by Vela with information for the build.
+var reserved = reservedVariables()
+
+// consumer represents a container that has
+// secrets injected with its path in the document.
+type consumer struct {
+	path    string
+	name    string
+	secrets yaml.StepSecretSlice
+	// whether the container is an origin
+	origin bool
+}
+
+// checkUndeclaredSecret reports secrets used by a step that are not
+// declared in the secrets block or provided by an origin. The secrets
+// provided by an origin are captured from the path of every item in
+// its parameters. When an origin has no items, the secrets it provides
+// are only known when the build runs, so the secrets used by steps
+// are not reported.
+func checkUndeclaredSecret(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	declared := make(map[string]bool)
+	provided := []string{}
+	unknown := false
+
+	for _, secret := range b.Secrets {
+		if secret.Origin.Empty() {
+			declared[secret.Name] = true
+
+			continue
+		}
+
+		paths, ok := originPaths(&secret.Origin)
+		if !ok {
+			unknown = true
+		}
+
+		provided = append(provided, paths...)
+	}
+
+	for _, c := range consumers(b) {
+		// skip steps that may use secrets provided by an unknown origin
+		if unknown && !c.origin {
+			continue
+		}
+
+		for i, secret := range c.secrets {
+			if declared[secret.Source] {
+				continue
+			}
+
+			// origins only provide secrets to steps
+			if !c.origin && providedBy(secret.Source, provided) {
+				continue
+			}
+
+			findingf(&findings, fmt.Sprintf("%s.secrets[%d].source", c.path, i), "secret %s for %s is not declared", secret.Source, c.name)
+		}
+	}
+
+	return findings
+}
+
+// checkUnusedSecret reports declared secrets not used by any step.
+func checkUnusedSecret(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	used := make(map[string]bool)
+
+	// secrets may be used to retrieve the secrets from an origin
+	for _, c := range consumers(b) {
+		for _, secret := range c.secrets {
+			used[secret.Source] = true
+		}
+	}
+
+	for i, secret := range b.Secrets {
+		// skip secrets provided by an origin
+		if !secret.Origin.Empty() {
+			continue
+		}
+
+		if !used[secret.Name] {
+			findingf(&findings, fmt.Sprintf("secrets[%d].name", i), "secret %s is not used by any step", secret.Name)
+		}
+	}
+
+	return findings
+}
+
+// checkDuplicateSecretTarget reports secrets
+// injected into the same target in a step.
+func checkDuplicateSecretTarget(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, c := range consumers(b) {
+		targets := make(map[string]string)
+
+		for i, secret := range c.secrets {
+			if source, ok := targets[secret.Target]; ok {
+				findingf(&findings, fmt.Sprintf("%s.secrets[%d].target", c.path, i), "secrets %s and %s for %s are both injected into %s", source, secret.Source, c.name, secret.Target)
+
+				continue
+			}
+
+			targets[secret.Target] = secret.Source
+		}
+	}
+
+	return findings
+}
+
+// checkReservedSecretTarget reports secrets injected
+// into a target reserved for the build information.
+func checkReservedSecretTarget(b *yaml.Build) []*Finding {
+	findings := []*Finding{}
+
+	for _, c := range consumers(b) {
+		for i, secret := range c.secrets {
+			if reserved[secret.Target] {
+				findingf(&findings, fmt.Sprintf("%s.secrets[%d].target", c.path, i), "secret %s for %s overwrites the reserved variable %s", secret.Source, c.name, secret.Target)
+			}
+		}
+	}
+
+	return findings
+}
+
+// consumers is a helper function to return the steps
+// and origins with secrets in the provided pipeline.
+func consumers(b *yaml.Build) []*consumer {
+	c := []*consumer{}
+
+	for _, s := range steps(b) {
+		c = append(c, &consumer{path: s.path, name: "step " + s.step.Name, secrets: s.step.Secrets})
+	}
+
+	for i, secret := range b.Secrets {
+		if secret.Origin.Empty() {
+			continue
+		}
+
+		c = append(c, &consumer{
+			path:    fmt.Sprintf("secrets[%d].origin", i),
+			name:    "origin " + secret.Origin.Name,
+			secrets: secret.Origin.Secrets,
+			origin:  true,
+		})
+	}
+
+	return c
+}
+
+// originPaths is a helper function to return the path of every item
+// in the parameters for the provided origin. False is returned when
+// the origin has no items, so the secrets it provides are unknown.
+func originPaths(o *yaml.Origin) ([]string, bool) {
+	items, ok := o.Parameters["items"].([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, false
+	}
+
+	paths := []string{}
+
+	for _, item := range items {
+		var path interface{}
+
+		switch i := item.(type) {
+		case map[string]interface{}:
+			path = i["path"]
+		case map[interface{}]interface{}:
+			path = i["path"]
+		}
+
+		p, ok := path.(string)
+		if !ok || len(p) == 0 {
+			return nil, false
+		}
+
+		paths = append(paths, p)
+	}
+
+	return paths, true
+}
+
+// providedBy is a helper function to verify whether a secret with the
+// provided name is written by an origin to one of the provided paths.
+// A secret is provided when its name is the path or, for an item with
+// multiple keys, starts with the path followed by an underscore.
+func providedBy(name string, paths []string) bool {
+	for _, p := range paths {
+		if name == p || strings.HasPrefix(name, p+"_") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reservedVariables is a helper function to capture the VELA_*
+// environment variables set for a build from every event.
+func reservedVariables() map[string]bool {
+	variables := make(map[string]bool)
+
+	// setup builds for every event with additional variables
+	events := []struct {
+		event, action, ref string
+	}{
+		{event: constants.EventPush, ref: "refs/heads/main"},
+		{event: constants.EventPull, ref: "refs/pull/1/head"},
+		{event: constants.EventComment, ref: "refs/pull/1/head"},
+		{event: constants.EventTag, ref: "refs/tags/v1"},
+		{event: constants.EventDeploy, ref: "refs/tags/v1"},
+		{event: constants.EventDelete, action: constants.ActionTag},
+	}
+
+	for _, e := range events {
+		build := new(library.Build)
+		build.SetEvent(e.event)
+		build.SetEventAction(e.action)
+		build.SetRef(e.ref)
+
+		for key := range build.Environment("", "") {
+			if strings.HasPrefix(key, "VELA_") {
+				variables[key] = true
+			}
+		}
+	}
+
+	return variables
+}
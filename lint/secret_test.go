@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/yaml"
+)
+
+func TestLint_SecretRules(t *testing.T) {
+	// setup types
+	internal := &yaml.Build{
+		Secrets: yaml.SecretSlice{
+			{Name: "docker_username"},
+			{Name: "docker_password"},
+			{Name: "unused"},
+		},
+		Stages: yaml.StageSlice{{Name: "publish", Steps: yaml.StepSlice{{
+			Name: "docker",
+			Secrets: yaml.StepSecretSlice{
+				{Source: "docker_username", Target: "DOCKER_USERNAME"},
+				{Source: "docker_password", Target: "DOCKER_USERNAME"},
+				{Source: "docker_token", Target: "VELA_BUILD_COMMIT"},
+				{Source: "docker_password", Target: "VELA_PULL_REQUEST"},
+			},
+		}}}},
+	}
+
+	external := &yaml.Build{
+		Secrets: yaml.SecretSlice{
+			{Name: "vault_token"},
+			{Origin: yaml.Origin{
+				Name: "vault",
+				Secrets: yaml.StepSecretSlice{
+					{Source: "vault_token", Target: "VAULT_TOKEN"},
+					{Source: "vault_role", Target: "VELA_DEPLOYMENT"},
+				},
+			}},
+		},
+		Steps: yaml.StepSlice{{
+			Name:    "deploy",
+			Secrets: yaml.StepSecretSlice{{Source: "kube_config", Target: "KUBE_CONFIG"}},
+		}},
+	}
+
+	items := &yaml.Build{
+		Secrets: yaml.SecretSlice{
+			{Name: "vault_token"},
+			{Origin: yaml.Origin{
+				Name: "vault",
+				Parameters: map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{"source": "secret/kube", "path": "kube"},
+						map[interface{}]interface{}{"source": "secret/docker", "path": "docker"},
+					},
+				},
+				Secrets: yaml.StepSecretSlice{{Source: "vault_token", Target: "VAULT_TOKEN"}},
+			}},
+		},
+		Steps: yaml.StepSlice{{
+			Name: "deploy",
+			Secrets: yaml.StepSecretSlice{
+				{Source: "kube", Target: "KUBE_CONFIG"},
+				{Source: "docker_password", Target: "DOCKER_PASSWORD"},
+				{Source: "kubernetes_token", Target: "KUBE_TOKEN"},
+			},
+		}},
+	}
+
+	// setup tests
+	tests := []struct {
+		name  string
+		check func(b *yaml.Build) []*Finding
+		build *yaml.Build
+		want  []string
+	}{
+		{
+			name:  "undeclared secret",
+			check: checkUndeclaredSecret,
+			build: internal,
+			want:  []string{"stages.publish.steps[0].secrets[2].source"},
+		},
+		{
+			name:  "undeclared secret with origin",
+			check: checkUndeclaredSecret,
+			build: external,
+			want:  []string{"secrets[1].origin.secrets[1].source"},
+		},
+		{
+			name:  "undeclared secret with origin items",
+			check: checkUndeclaredSecret,
+			build: items,
+			want:  []string{"steps[0].secrets[2].source"},
+		},
+		{
+			name:  "unused secret",
+			check: checkUnusedSecret,
+			build: internal,
+			want:  []string{"secrets[2].name"},
+		},
+		{
+			name:  "unused secret with origin",
+			check: checkUnusedSecret,
+			build: external,
+			want:  []string{},
+		},
+		{
+			name:  "duplicate secret target",
+			check: checkDuplicateSecretTarget,
+			build: internal,
+			want:  []string{"stages.publish.steps[0].secrets[1].target"},
+		},
+		{
+			name:  "reserved secret target",
+			check: checkReservedSecretTarget,
+			build: internal,
+			want:  []string{"stages.publish.steps[0].secrets[2].target", "stages.publish.steps[0].secrets[3].target"},
+		},
+		{
+			name:  "reserved secret target with origin",
+			check: checkReservedSecretTarget,
+			build: external,
+			want:  []string{"secrets[1].origin.secrets[1].target"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := []string{}
+
+		for _, finding := range test.check(test.build) {
+			got = append(got, finding.Path)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s findings are %v, want %v", test.name, got, test.want)
+		}
+	}
+}
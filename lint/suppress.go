@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// directive is the prefix of a comment
+// that suppresses issues from rules.
+const directive = "vela-lint:disable"
+
+type (
+	// suppression represents the rules disabled by a
+	// comment for a range of lines in the document.
+	suppression struct {
+		start, end int
+		// rules disabled by the comment, all rules when empty
+		rules map[string]bool
+	}
+
+	// suppressionList is a list of suppressions for a document.
+	suppressionList []*suppression
+)
+
+// matches returns true if the provided issue
+// is suppressed by a comment in the document.
+func (s suppressionList) matches(issue *Issue) bool {
+	for _, suppression := range s {
+		if issue.Line < suppression.start || issue.Line > suppression.end {
+			continue
+		}
+
+		if len(suppression.rules) == 0 || suppression.rules[issue.Rule] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// suppressions is a helper function to capture the suppressions
+// from the comments in the provided raw YAML document.
+//
+// A comment above the document disables the rules for the whole
+// document, a comment on a key disables the rules for the key and
+// its value and a comment on the first key of a sequence item
+// disables the rules for the whole item.
+func suppressions(data []byte) (suppressionList, error) {
+	root := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(data, root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse source document: %w", err)
+	}
+
+	list := suppressionList{}
+
+	// capture the comments for the whole document
+	list.add(0, math.MaxInt, root.HeadComment)
+
+	for _, node := range root.Content {
+		list.add(0, math.MaxInt, node.HeadComment)
+		list.walk(node)
+	}
+
+	return list, nil
+}
+
+// walk is a helper function to capture the suppressions
+// from the comments in the provided node and its children.
+func (s *suppressionList) walk(node *yamlv3.Node) {
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			s.add(key.Line, lastLine(value), key.HeadComment, key.LineComment, value.LineComment)
+
+			s.walk(value)
+		}
+	case yamlv3.SequenceNode:
+		for _, item := range node.Content {
+			comments := []string{item.HeadComment, item.LineComment}
+
+			// comments on the first key belong to the whole item
+			if item.Kind == yamlv3.MappingNode && len(item.Content) > 1 {
+				comments = append(comments,
+					item.Content[0].HeadComment,
+					item.Content[0].LineComment,
+					item.Content[1].LineComment,
+				)
+			}
+
+			s.add(item.Line, lastLine(item), comments...)
+
+			s.walk(item)
+		}
+	}
+}
+
+// add is a helper function to append a suppression for the
+// provided lines when any of the comments is a directive.
+func (s *suppressionList) add(start, end int, comments ...string) {
+	for _, comment := range comments {
+		for _, line := range strings.Split(comment, "\n") {
+			rules, ok := parseDirective(line)
+			if !ok {
+				continue
+			}
+
+			*s = append(*s, &suppression{start: start, end: end, rules: rules})
+		}
+	}
+}
+
+// parseDirective is a helper function to return the rules
+// disabled by the provided comment line. The rules are
+// separated by commas or spaces and any text after "--"
+// is treated as the reason for the suppression.
+func parseDirective(line string) (map[string]bool, bool) {
+	line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+
+	if !strings.HasPrefix(line, directive) {
+		return nil, false
+	}
+
+	line = strings.TrimPrefix(line, directive)
+
+	// verify the directive is not the prefix of another word
+	if len(line) > 0 && line[0] != ' ' && line[0] != '\t' {
+		return nil, false
+	}
+
+	// remove the reason for the suppression
+	line, _, _ = strings.Cut(line, "--")
+
+	rules := make(map[string]bool)
+
+	for _, id := range strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	}) {
+		rules[id] = true
+	}
+
+	return rules, true
+}
+
+// lastLine is a helper function to return the
+// last line used by the provided node.
+func lastLine(node *yamlv3.Node) int {
+	line := node.Line
+
+	for _, child := range node.Content {
+		line = max(line, lastLine(child))
+	}
+
+	return line
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package lint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint_parseDirective(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		line  string
+		rules map[string]bool
+		ok    bool
+	}{
+		{line: "# vela-lint:disable", rules: map[string]bool{}, ok: true},
+		{line: "#vela-lint:disable privileged", rules: map[string]bool{"privileged": true}, ok: true},
+		{line: "# vela-lint:disable privileged, image-latest -- required for docker", rules: map[string]bool{"privileged": true, "image-latest": true}, ok: true},
+		{line: "# vela-lint:disabled privileged"},
+		{line: "# run the tests"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		rules, ok := parseDirective(test.line)
+
+		if ok != test.ok {
+			t.Errorf("parseDirective for %q is %v, want %v", test.line, ok, test.ok)
+		}
+
+		if !reflect.DeepEqual(rules, test.rules) {
+			t.Errorf("parseDirective for %q is %v, want %v", test.line, rules, test.rules)
+		}
+	}
+}
+
+func TestLint_suppressions(t *testing.T) {
+	// setup types
+	data := []byte(`steps:
+  # vela-lint:disable
+  - name: test
+    image: golang
+
+  - name: docker # vela-lint:disable privileged
+    image: docker
+    privileged: true
+
+  - name: build
+    image: golang # vela-lint:disable image-latest
+    privileged: true
+`)
+
+	// setup tests
+	tests := []struct {
+		issue *Issue
+		want  bool
+	}{
+		{issue: &Issue{Rule: "image-latest", Line: 4}, want: true},
+		{issue: &Issue{Rule: "privileged", Line: 8}, want: true},
+		{issue: &Issue{Rule: "image-latest", Line: 7}, want: false},
+		{issue: &Issue{Rule: "image-latest", Line: 11}, want: true},
+		{issue: &Issue{Rule: "privileged", Line: 12}, want: false},
+	}
+
+	got, err := suppressions(data)
+	if err != nil {
+		t.Fatalf("suppressions returned err: %v", err)
+	}
+
+	// run tests
+	for _, test := range tests {
+		if got.matches(test.issue) != test.want {
+			t.Errorf("matches for %s on line %d is %v, want %v", test.issue.Rule, test.issue.Line, !test.want, test.want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+type (
+	// ArtifactSlice is the pipeline representation
+	// of the artifacts block for a step in a pipeline.
+	//
+	// Deprecated: use ArtifactSlice from github.com/go-vela/server/compiler/types/pipeline instead.
+	ArtifactSlice []*Artifact
+
+	// Artifact is the pipeline representation of an artifact
+	// from the artifacts block for a step in a pipeline.
+	//
+	// Deprecated: use Artifact from github.com/go-vela/server/compiler/types/pipeline instead.
+	Artifact struct {
+		Name      string   `json:"name,omitempty"      yaml:"name,omitempty"`
+		Paths     []string `json:"paths,omitempty"     yaml:"paths,omitempty"`
+		Retention int      `json:"retention,omitempty" yaml:"retention,omitempty"`
+	}
+)
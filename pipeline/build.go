@@ -3,6 +3,7 @@
 package pipeline
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -24,6 +25,19 @@ type Build struct {
 	Services    ContainerSlice     `json:"services,omitempty" yaml:"services,omitempty"`
 	Stages      StageSlice         `json:"stages,omitempty"   yaml:"stages,omitempty"`
 	Steps       ContainerSlice     `json:"steps,omitempty"    yaml:"steps,omitempty"`
+	Cache       CacheSlice         `json:"cache,omitempty"    yaml:"cache,omitempty"`
+}
+
+// Compile verifies and caches every pattern and expression from
+// the ruleset for every stage, step and service in the pipeline.
+// Calling Compile before Purge reports every invalid ruleset up
+// front and avoids compiling the patterns while matching.
+func (b *Build) Compile() error {
+	return errors.Join(
+		b.Stages.Compile(),
+		b.Steps.compile("step"),
+		b.Services.compile("service"),
+	)
 }
 
 // Purge removes the steps, in every stage, that contain a ruleset
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drone/envsubst"
+
+	"github.com/go-vela/types/constants"
+)
+
+// ErrUnknownCacheVariable defines the error type when a cache
+// key references an environment variable that is not provided.
+var ErrUnknownCacheVariable = errors.New("unknown variable in cache key")
+
+type (
+	// CacheSlice is the pipeline representation
+	// of the cache block for a pipeline.
+	//
+	// Deprecated: use CacheSlice from github.com/go-vela/server/compiler/types/pipeline instead.
+	CacheSlice []*Cache
+
+	// Cache is the pipeline representation of a
+	// cache from the cache block for a pipeline.
+	//
+	// Deprecated: use Cache from github.com/go-vela/server/compiler/types/pipeline instead.
+	Cache struct {
+		Key         string   `json:"key,omitempty"          yaml:"key,omitempty"`
+		Paths       []string `json:"paths,omitempty"        yaml:"paths,omitempty"`
+		RestoreKeys []string `json:"restore_keys,omitempty" yaml:"restore_keys,omitempty"`
+		Scope       string   `json:"scope,omitempty"        yaml:"scope,omitempty"`
+	}
+)
+
+// Empty returns true if the provided cache is empty.
+func (c *Cache) Empty() bool {
+	// return true if the cache is nil
+	if c == nil {
+		return true
+	}
+
+	// return true if every cache field is empty
+	if len(c.Key) == 0 &&
+		len(c.Paths) == 0 &&
+		len(c.RestoreKeys) == 0 &&
+		len(c.Scope) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// Render returns a copy of the cache with every reference (${VAR})
+// in the key and restore keys replaced with the value from the
+// provided environment. The rendered keys are prefixed with the
+// provided org, and the repo when the cache is scoped to the repo,
+// so the keys are unique for the scope of the cache. An error is
+// returned when a key references a variable that is not provided.
+func (c *Cache) Render(env map[string]string, org, repo string) (*Cache, error) {
+	// return nothing if the cache is empty
+	if c.Empty() {
+		return nil, nil
+	}
+
+	cache := &Cache{
+		Paths: append([]string{}, c.Paths...),
+		Scope: c.Scope,
+	}
+
+	// implicitly set `scope` field if empty
+	if len(cache.Scope) == 0 {
+		cache.Scope = constants.CacheScopeRepo
+	}
+
+	// capture the prefix for the scope of the cache
+	var prefix string
+
+	switch cache.Scope {
+	case constants.CacheScopeRepo:
+		prefix = fmt.Sprintf("%s/%s/", org, repo)
+	case constants.CacheScopeOrg:
+		prefix = fmt.Sprintf("%s/", org)
+	default:
+		return nil, fmt.Errorf("invalid scope %s for cache %s", cache.Scope, c.Key)
+	}
+
+	key, err := renderKey(c.Key, env)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.Key = prefix + key
+
+	// iterate through each restore key for the cache
+	for _, restore := range c.RestoreKeys {
+		key, err := renderKey(restore, env)
+		if err != nil {
+			return nil, err
+		}
+
+		cache.RestoreKeys = append(cache.RestoreKeys, prefix+key)
+	}
+
+	return cache, nil
+}
+
+// renderKey is a helper function to replace every reference
+// to an environment variable in the provided cache key.
+func renderKey(key string, env map[string]string) (string, error) {
+	missing := []string{}
+
+	// substitute the environment variables
+	//
+	// https://pkg.go.dev/github.com/drone/envsubst?tab=doc#Eval
+	rendered, err := envsubst.Eval(key, func(name string) string {
+		value, ok := env[name]
+		if !ok {
+			missing = append(missing, name)
+		}
+
+		return value
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to render cache key %s: %w", key, err)
+	}
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w %s: %s", ErrUnknownCacheVariable, key, strings.Join(missing, ", "))
+	}
+
+	return rendered, nil
+}
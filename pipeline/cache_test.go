@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/constants"
+)
+
+func TestPipeline_Cache_Render(t *testing.T) {
+	// setup types
+	env := map[string]string{
+		"VELA_BUILD_BRANCH": "main",
+		"GO_VERSION":        "1.22",
+	}
+
+	// setup tests
+	tests := []struct {
+		name  string
+		cache *Cache
+		want  *Cache
+		err   error
+	}{
+		{
+			name:  "empty",
+			cache: new(Cache),
+			want:  nil,
+		},
+		{
+			name: "repo scope",
+			cache: &Cache{
+				Key:         "go-${GO_VERSION}-${VELA_BUILD_BRANCH}",
+				Paths:       []string{".cache"},
+				RestoreKeys: []string{"go-${GO_VERSION}-"},
+			},
+			want: &Cache{
+				Key:         "github/octocat/go-1.22-main",
+				Paths:       []string{".cache"},
+				RestoreKeys: []string{"github/octocat/go-1.22-"},
+				Scope:       constants.CacheScopeRepo,
+			},
+		},
+		{
+			name: "org scope",
+			cache: &Cache{
+				Key:   "go-${GO_VERSION}",
+				Paths: []string{".cache"},
+				Scope: constants.CacheScopeOrg,
+			},
+			want: &Cache{
+				Key:   "github/go-1.22",
+				Paths: []string{".cache"},
+				Scope: constants.CacheScopeOrg,
+			},
+		},
+		{
+			name: "unknown variable",
+			cache: &Cache{
+				Key:   "go-${FOO}",
+				Paths: []string{".cache"},
+			},
+			err: ErrUnknownCacheVariable,
+		},
+		{
+			name: "unknown restore key variable",
+			cache: &Cache{
+				Key:         "go",
+				Paths:       []string{".cache"},
+				RestoreKeys: []string{"go-${BAR}"},
+			},
+			err: ErrUnknownCacheVariable,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.cache.Render(env, "github", "octocat")
+
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("Render for %s returned err %v, want %v", test.name, err, test.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Render for %s returned err: %v", test.name, err)
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Render for %s is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"math/rand"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/drone/envsubst"
@@ -29,31 +31,62 @@ type (
 	//
 	// Deprecated: use Container from github.com/go-vela/server/compiler/types/pipeline instead.
 	Container struct {
-		ID          string            `json:"id,omitempty"          yaml:"id,omitempty"`
-		Commands    []string          `json:"commands,omitempty"    yaml:"commands,omitempty"`
-		Detach      bool              `json:"detach,omitempty"      yaml:"detach,omitempty"`
-		Directory   string            `json:"directory,omitempty"   yaml:"directory,omitempty"`
-		Entrypoint  []string          `json:"entrypoint,omitempty"  yaml:"entrypoint,omitempty"`
-		Environment map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"`
-		ExitCode    int               `json:"exit_code,omitempty"   yaml:"exit_code,omitempty"`
-		Image       string            `json:"image,omitempty"       yaml:"image,omitempty"`
-		Name        string            `json:"name,omitempty"        yaml:"name,omitempty"`
-		Needs       []string          `json:"needs,omitempty"       yaml:"needs,omitempty"`
-		Networks    []string          `json:"networks,omitempty"    yaml:"networks,omitempty"`
-		Number      int               `json:"number,omitempty"      yaml:"number,omitempty"`
-		Ports       []string          `json:"ports,omitempty"       yaml:"ports,omitempty"`
-		Privileged  bool              `json:"privileged,omitempty"  yaml:"privileged,omitempty"`
-		Pull        string            `json:"pull,omitempty"        yaml:"pull,omitempty"`
-		Ruleset     Ruleset           `json:"ruleset,omitempty"     yaml:"ruleset,omitempty"`
-		Secrets     StepSecretSlice   `json:"secrets,omitempty"     yaml:"secrets,omitempty"`
-		Ulimits     UlimitSlice       `json:"ulimits,omitempty"     yaml:"ulimits,omitempty"`
-		Volumes     VolumeSlice       `json:"volumes,omitempty"     yaml:"volumes,omitempty"`
-		User        string            `json:"user,omitempty"        yaml:"user,omitempty"`
-		ReportAs    string            `json:"report_as,omitempty" yaml:"report_as,omitempty"`
-		IDRequest   string            `json:"id_request,omitempty" yaml:"id_request,omitempty"`
+		ID           string            `json:"id,omitempty"          yaml:"id,omitempty"`
+		Commands     []string          `json:"commands,omitempty"    yaml:"commands,omitempty"`
+		Detach       bool              `json:"detach,omitempty"      yaml:"detach,omitempty"`
+		Directory    string            `json:"directory,omitempty"   yaml:"directory,omitempty"`
+		Entrypoint   []string          `json:"entrypoint,omitempty"  yaml:"entrypoint,omitempty"`
+		Environment  map[string]string `json:"environment,omitempty" yaml:"environment,omitempty"`
+		ExitCode     int               `json:"exit_code,omitempty"   yaml:"exit_code,omitempty"`
+		Image        string            `json:"image,omitempty"       yaml:"image,omitempty"`
+		Name         string            `json:"name,omitempty"        yaml:"name,omitempty"`
+		Needs        []string          `json:"needs,omitempty"       yaml:"needs,omitempty"`
+		Networks     []string          `json:"networks,omitempty"    yaml:"networks,omitempty"`
+		Number       int               `json:"number,omitempty"      yaml:"number,omitempty"`
+		Ports        []string          `json:"ports,omitempty"       yaml:"ports,omitempty"`
+		Privileged   bool              `json:"privileged,omitempty"  yaml:"privileged,omitempty"`
+		Pull         string            `json:"pull,omitempty"        yaml:"pull,omitempty"`
+		Ruleset      Ruleset           `json:"ruleset,omitempty"     yaml:"ruleset,omitempty"`
+		Secrets      StepSecretSlice   `json:"secrets,omitempty"     yaml:"secrets,omitempty"`
+		Ulimits      UlimitSlice       `json:"ulimits,omitempty"     yaml:"ulimits,omitempty"`
+		Volumes      VolumeSlice       `json:"volumes,omitempty"     yaml:"volumes,omitempty"`
+		User         string            `json:"user,omitempty"        yaml:"user,omitempty"`
+		ReportAs     string            `json:"report_as,omitempty" yaml:"report_as,omitempty"`
+		IDRequest    string            `json:"id_request,omitempty" yaml:"id_request,omitempty"`
+		Timeout      time.Duration     `json:"timeout,omitempty"     yaml:"timeout,omitempty"`
+		Retries      int               `json:"retries,omitempty"     yaml:"retries,omitempty"`
+		RetryBackoff *RetryBackoff     `json:"retry_backoff,omitempty" yaml:"retry_backoff,omitempty"`
+		Resources    *Resources        `json:"resources,omitempty"   yaml:"resources,omitempty"`
+		Cache        CacheSlice        `json:"cache,omitempty"       yaml:"cache,omitempty"`
+		Artifacts    ArtifactSlice     `json:"artifacts,omitempty"   yaml:"artifacts,omitempty"`
+		Consume      []string          `json:"consume,omitempty"     yaml:"consume,omitempty"`
 	}
 )
 
+// Compile verifies and caches every pattern and expression from
+// the ruleset for every Container in the pipeline. An error is
+// returned, with the name of the container, for every invalid ruleset.
+func (c *ContainerSlice) Compile() error {
+	return c.compile("container")
+}
+
+// compile is a helper function to verify and cache the ruleset for
+// every Container in the pipeline. The provided kind of container,
+// i.e. step or service, is used to describe every invalid ruleset.
+func (c *ContainerSlice) compile(kind string) error {
+	errs := []error{}
+
+	// iterate through each Container in the pipeline
+	for _, container := range *c {
+		err := container.Ruleset.Compile()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid ruleset for %s %s: %w", kind, container.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Purge removes the Containers that have a ruleset
 // that do not match the provided ruledata.
 func (c *ContainerSlice) Purge(r *RuleData) (*ContainerSlice, error) {
@@ -63,7 +96,7 @@ func (c *ContainerSlice) Purge(r *RuleData) (*ContainerSlice, error) {
 	// iterate through each Container in the pipeline
 	for _, container := range *c {
 		// verify ruleset matches
-		match, err := container.Ruleset.Match(r)
+		match, err := container.Ruleset.Match(container.ruleData(r))
 		if err != nil {
 			return nil, fmt.Errorf("unable to process ruleset for step %s: %w", container.Name, err)
 		}
@@ -135,7 +168,14 @@ func (c *Container) Empty() bool {
 		len(c.Volumes) == 0 &&
 		len(c.User) == 0 &&
 		len(c.ReportAs) == 0 &&
-		len(c.IDRequest) == 0 {
+		len(c.IDRequest) == 0 &&
+		c.Timeout == 0 &&
+		c.Retries == 0 &&
+		c.RetryBackoff.Empty() &&
+		c.Resources.Empty() &&
+		len(c.Cache) == 0 &&
+		len(c.Artifacts) == 0 &&
+		len(c.Consume) == 0 {
 		return true
 	}
 
@@ -146,13 +186,30 @@ func (c *Container) Empty() bool {
 // Execute returns true when the provided ruledata matches
 // the conditions when we should be running the container on the worker.
 func (c *Container) Execute(r *RuleData) (bool, error) {
+	execute, _, err := c.ExecuteTrace(r)
+
+	return execute, err
+}
+
+// ExecuteTrace returns the same result as Execute along with a
+// trace explaining how the result was determined, including every
+// evaluation of the ruleset with the build status used for it.
+func (c *Container) ExecuteTrace(r *RuleData) (bool, *ExecuteTrace, error) {
+	trace := new(ExecuteTrace)
+
 	// return false if the container is nil
 	if c == nil {
-		return false, nil
+		trace.Reason = "container is nil"
+
+		return false, trace, nil
 	}
 
-	// Skip evaluating path, comment, and label in ruleset,
-	// as the worker lacks necessary rule data.
+	trace.Status = r.Status
+	trace.Ignored = c.workerIgnored()
+
+	// Skip evaluating path, comment, label, instance, message, author,
+	// sender, base ref and changed files in ruleset, as the worker
+	// lacks necessary rule data.
 	//
 	// The compiler determines whether a container will run based on
 	// these rules.
@@ -168,17 +225,72 @@ func (c *Container) Execute(r *RuleData) (bool, error) {
 	c.Ruleset.If.Instance = []string{}
 	c.Ruleset.Unless.Instance = []string{}
 
+	c.Ruleset.If.Message = []string{}
+	c.Ruleset.Unless.Message = []string{}
+
+	c.Ruleset.If.Author = []string{}
+	c.Ruleset.Unless.Author = []string{}
+
+	c.Ruleset.If.Sender = []string{}
+	c.Ruleset.Unless.Sender = []string{}
+
+	c.Ruleset.If.BaseRef = []string{}
+	c.Ruleset.Unless.BaseRef = []string{}
+
+	c.Ruleset.If.ChangedFiles = []string{}
+	c.Ruleset.Unless.ChangedFiles = []string{}
+
+	// Skip evaluating the terms of expressions that
+	// reference these rule data for the same reason.
+	c.Ruleset.If.Expression = workerExpression(c.Ruleset.If.Expression, true)
+	c.Ruleset.Unless.Expression = workerExpression(c.Ruleset.Unless.Expression, false)
+
+	// provide the container environment to the expressions
+	//
+	// the environment is restored since the status
+	// for the provided ruledata is updated in place
+	if data := c.ruleData(r); data != r {
+		env := r.Env
+		r.Env = data.Env
+
+		defer func() { r.Env = env }()
+	}
+
+	// evaluate is a helper function to match the ruleset
+	// and capture the evaluation in the trace
+	evaluate := func(reason string) (bool, error) {
+		match, ruleset, err := c.Ruleset.MatchTrace(r)
+
+		trace.Evaluations = append(trace.Evaluations, &EvaluationTrace{
+			Status:  r.Status,
+			Reason:  reason,
+			Result:  match,
+			Ruleset: ruleset,
+		})
+
+		return match, err
+	}
+
 	// check if the build is in a running state
 	if strings.EqualFold(r.Status, constants.StatusRunning) {
 		// treat the ruleset status as success
 		r.Status = constants.StatusSuccess
 
 		// return if the container ruleset matches the conditions
-		return c.Ruleset.Match(r)
+		match, err := evaluate("build is running, status treated as success")
+		if err != nil {
+			return false, trace, err
+		}
+
+		trace.Result = match
+		trace.Reason = "ruleset " + matchString(match) + " while the build is running"
+
+		return match, trace, nil
 	}
 
 	// assume you will execute the container
 	execute := true
+	trace.Reason = "build is successful and no status rules prevent execution"
 
 	// capture the build status out of the ruleset
 	status := r.Status
@@ -187,10 +299,11 @@ func (c *Container) Execute(r *RuleData) (bool, error) {
 	if !strings.EqualFold(status, constants.StatusSuccess) {
 		// disregard the need to run the container
 		execute = false
+		trace.Reason = "build is not successful and the ruleset does not run on failure"
 
-		match, err := c.Ruleset.Match(r)
+		match, err := evaluate("build is not successful, checking for a status failure ruleset")
 		if err != nil {
-			return false, err
+			return false, trace, err
 		}
 
 		// check if you need to run a status failure ruleset
@@ -200,14 +313,15 @@ func (c *Container) Execute(r *RuleData) (bool, error) {
 			match {
 			// approve the need to run the container
 			execute = true
+			trace.Reason = "build is not successful and the ruleset runs on failure"
 		}
 	}
 
 	r.Status = constants.StatusFailure
 
-	match, err := c.Ruleset.Match(r)
+	match, err := evaluate("checking for a status failure ruleset")
 	if err != nil {
-		return false, err
+		return false, trace, err
 	}
 
 	// check if you need to skip a status failure ruleset
@@ -216,18 +330,23 @@ func (c *Container) Execute(r *RuleData) (bool, error) {
 		!(c.Ruleset.If.Empty() && c.Ruleset.Unless.Empty()) && match {
 		r.Status = constants.StatusSuccess
 
-		match, err = c.Ruleset.Match(r)
+		match, err = evaluate("ruleset matches a failure, checking the ruleset with status success")
 		if err != nil {
-			return false, err
+			return false, trace, err
 		}
 
 		if !match {
 			// disregard the need to run the container
 			execute = false
+			trace.Reason = "build is successful and the ruleset only runs on failure"
+		} else {
+			trace.Reason = "build is successful and the ruleset matches"
 		}
 	}
 
-	return execute, nil
+	trace.Result = execute
+
+	return execute, trace, nil
 }
 
 // MergeEnv takes a list of environment variables and attempts
@@ -280,6 +399,8 @@ func (c *Container) Sanitize(driver string) *Container {
 			c.ID = strings.ReplaceAll(c.ID, "/", "-")
 		}
 
+		container.Resources = c.Resources.Sanitize(driver)
+
 		return container
 	// sanitize container for Kubernetes
 	case constants.DriverKubernetes:
@@ -314,6 +435,8 @@ func (c *Container) Sanitize(driver string) *Container {
 			)
 		}
 
+		container.Resources = c.Resources.Sanitize(driver)
+
 		return container
 	// unrecognized driver
 	default:
@@ -324,7 +447,9 @@ func (c *Container) Sanitize(driver string) *Container {
 
 // Substitute replaces every reference (${VAR} or $${VAR}) to an
 // environment variable in the container configuration with the
-// corresponding value for that environment variable.
+// corresponding value for that environment variable. A reference
+// to a matrix value (${matrix.x}) is replaced with the value of
+// the environment variable injected for the matrix axis.
 func (c *Container) Substitute() error {
 	// check if container or container environment are nil
 	if c == nil || c.Environment == nil {
@@ -353,8 +478,11 @@ func (c *Container) Substitute() error {
 
 	// substitute the environment variables
 	//
+	// references to matrix values, like ${matrix.go}, are
+	// resolved with the environment variable for the axis
+	//
 	// https://pkg.go.dev/github.com/drone/envsubst?tab=doc#Eval
-	ctn, err := envsubst.Eval(string(body), subFunc)
+	ctn, err := envsubst.Eval(expandMatrix(string(body)), subFunc)
 	if err != nil {
 		return err
 	}
@@ -397,3 +525,91 @@ func dnsSafeRandomString(n int) string {
 
 	return string(b)
 }
+
+// ruleData is a helper function to return a copy of the provided
+// ruledata with the environment for the container. The values
+// from the provided ruledata take precedence over the values
+// from the container environment.
+func (c *Container) ruleData(r *RuleData) *RuleData {
+	// return the ruledata unchanged if the container has no environment
+	if r == nil || len(c.Environment) == 0 {
+		return r
+	}
+
+	data := *r
+	data.Env = make(map[string]string)
+
+	for key, value := range c.Environment {
+		data.Env[key] = value
+	}
+
+	for key, value := range r.Env {
+		data.Env[key] = value
+	}
+
+	return &data
+}
+
+// compilerFields are the ruledata fields referenced by
+// an expression that are not available on the worker.
+var compilerFields = []string{"author", "base_ref", "comment", "instance", "label", "message", "path", "sender"}
+
+// workerExpression is a helper function to remove the terms from
+// the provided expression that reference ruledata not available on
+// the worker. The removed terms are treated as producing the provided
+// result, i.e. true for the if block and false for the unless block,
+// so they never prevent the container from running. The compiler
+// determines whether a container will run based on these terms.
+func workerExpression(expression string, result bool) string {
+	// return the expression unchanged if it is empty
+	if len(expression) == 0 {
+		return expression
+	}
+
+	expr, err := ParseExpression(expression)
+	if err != nil {
+		return expression
+	}
+
+	if !expr.References(compilerFields...) {
+		return expression
+	}
+
+	return expr.partial(result, compilerFields...)
+}
+
+// workerIgnored is a helper function to return the name of every
+// rule from the ruleset that is not evaluated on the worker.
+func (c *Container) workerIgnored() []string {
+	ignored := []string{}
+
+	// capture every ruletype ignored by the worker
+	for name, rules := range map[string][2][]string{
+		"author":        {c.Ruleset.If.Author, c.Ruleset.Unless.Author},
+		"base_ref":      {c.Ruleset.If.BaseRef, c.Ruleset.Unless.BaseRef},
+		"changed_files": {c.Ruleset.If.ChangedFiles, c.Ruleset.Unless.ChangedFiles},
+		"comment":       {c.Ruleset.If.Comment, c.Ruleset.Unless.Comment},
+		"instance":      {c.Ruleset.If.Instance, c.Ruleset.Unless.Instance},
+		"label":         {c.Ruleset.If.Label, c.Ruleset.Unless.Label},
+		"message":       {c.Ruleset.If.Message, c.Ruleset.Unless.Message},
+		"path":          {c.Ruleset.If.Path, c.Ruleset.Unless.Path},
+		"sender":        {c.Ruleset.If.Sender, c.Ruleset.Unless.Sender},
+	} {
+		if len(rules[0]) > 0 || len(rules[1]) > 0 {
+			ignored = append(ignored, name)
+		}
+	}
+
+	sort.Strings(ignored)
+
+	// capture every expression with terms ignored by the worker
+	if workerExpression(c.Ruleset.If.Expression, true) != c.Ruleset.If.Expression {
+		ignored = append(ignored, "if expression")
+	}
+
+	if workerExpression(c.Ruleset.Unless.Expression, false) != c.Ruleset.Unless.Expression {
+		ignored = append(ignored, "unless expression")
+	}
+
+	return ignored
+}
@@ -835,6 +835,191 @@ func TestPipeline_Container_Execute(t *testing.T) {
 			},
 			want: true,
 		},
+		{ // status and path expression, failure container with build failure
+			container: &Container{
+				Name:     "status-path-expression-failure",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Expression: "status == 'failure' && contains(path, 'src/')",
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // status and path expression, failure container with build success
+			container: &Container{
+				Name:     "status-path-expression-success",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Expression: "status == 'failure' && contains(path, 'src/')",
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "success",
+			},
+			want: false,
+		},
+		{ // branch and negated path expression, success container with build running
+			container: &Container{
+				Name:     "status-path-expression-running",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Expression: "branch == 'dev' && !contains(path, 'docs/')",
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "running",
+			},
+			want: false,
+		},
+		{ // message ruleset, failure container with build failure
+			container: &Container{
+				Name:     "message",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Message: []string{"*[docs]*"},
+						Status:  []string{constants.StatusFailure},
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // author ruleset, failure container with build failure
+			container: &Container{
+				Name:     "author",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Author: []string{"dependabot[bot]"},
+						Status: []string{constants.StatusFailure},
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // sender ruleset, failure container with build failure
+			container: &Container{
+				Name:     "sender",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Sender: []string{"renovate[bot]"},
+						Status: []string{constants.StatusFailure},
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // base ref ruleset, failure container with build failure
+			container: &Container{
+				Name:     "base-ref",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						BaseRef: []string{"release/*"},
+						Status:  []string{constants.StatusFailure},
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // changed files ruleset, failure container with build failure
+			container: &Container{
+				Name:     "changed-files",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						ChangedFiles: []string{"<5"},
+						Status:       []string{constants.StatusFailure},
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
+		{ // message and status expression, failure container with build failure
+			container: &Container{
+				Name:     "message-expression",
+				Image:    "alpine:latest",
+				Commands: []string{"echo \"Hey Vela\""},
+				Ruleset: Ruleset{
+					If: Rules{
+						Expression: "status == 'failure' && contains(message, '[docs]') && author != 'octocat' && sender != 'octocat' && base_ref == 'main'",
+					},
+					Operator: "and",
+				},
+			},
+			ruleData: &RuleData{
+				Branch: "main",
+				Event:  "push",
+				Repo:   "foo/bar",
+				Status: "failure",
+			},
+			want: true,
+		},
 	}
 
 	// run tests
@@ -965,6 +1150,27 @@ func TestPipeline_Container_Substitute(t *testing.T) {
 			},
 			failure: false,
 		},
+		{
+			container: &Container{
+				ID:          "step_github_octocat_1_test_1.22",
+				Commands:    []string{"go test ./...", "echo ${matrix.go}", "echo $${matrix.go}"},
+				Environment: map[string]string{"VELA_MATRIX_GO": "1.22"},
+				Image:       "golang:${matrix.go}",
+				Name:        "test_1.22",
+				Number:      1,
+				Pull:        "always",
+			},
+			want: &Container{
+				ID:          "step_github_octocat_1_test_1.22",
+				Commands:    []string{"go test ./...", "echo 1.22", "echo ${matrix.go}"},
+				Environment: map[string]string{"VELA_MATRIX_GO": "1.22"},
+				Image:       "golang:1.22",
+				Name:        "test_1.22",
+				Number:      1,
+				Pull:        "always",
+			},
+			failure: false,
+		},
 		{
 			container: nil,
 			want:      nil,
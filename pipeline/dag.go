@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDuplicateNode defines the error type when the
+	// same name is used for multiple nodes in a graph.
+	ErrDuplicateNode = errors.New("duplicate name in dependency graph")
+
+	// ErrUnknownNeeds defines the error type when a node
+	// in a graph needs a node that does not exist.
+	ErrUnknownNeeds = errors.New("unknown needs reference")
+
+	// ErrSelfNeeds defines the error type when a
+	// node in a graph needs itself.
+	ErrSelfNeeds = errors.New("self needs reference")
+
+	// ErrCycle defines the error type when the
+	// needs for a graph contain a cycle.
+	ErrCycle = errors.New("dependency cycle detected")
+)
+
+// CycleError is the error returned when the needs for a
+// graph contain a cycle. The path starts and ends with
+// the same name, i.e. [a b c a].
+type CycleError struct {
+	Path []string
+}
+
+// Error implements the error interface for the CycleError type.
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrCycle, strings.Join(e.Path, " -> "))
+}
+
+// Is allows the CycleError type to match the ErrCycle error.
+func (e *CycleError) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// DAG is the pipeline representation of the directed acyclic graph
+// created from the needs for the stages or steps in a pipeline.
+//
+// Deprecated: use DAG from github.com/go-vela/server/compiler/types/pipeline instead.
+type DAG struct {
+	nodes      []string
+	index      map[string]int
+	upstream   map[string][]string
+	downstream map[string][]string
+}
+
+// DAG creates the dependency graph from the needs of every stage
+// in the StageSlice type. An error is returned when a stage needs
+// a stage that does not exist, or the needs contain a cycle.
+func (s *StageSlice) DAG() (*DAG, error) {
+	names := []string{}
+	needs := make(map[string][]string)
+
+	// iterate through each stage in the pipeline
+	for _, stage := range *s {
+		names = append(names, stage.Name)
+		needs[stage.Name] = stage.Needs
+	}
+
+	return newDAG("stage", names, needs)
+}
+
+// DAG creates the dependency graph from the needs of every container
+// in the ContainerSlice type. When none of the containers declare any
+// needs, every container implicitly needs the container before it,
+// preserving the sequential execution of a pipeline. Otherwise, only
+// the declared needs are used and containers without needs can start
+// immediately. An error is returned when a container needs itself, a
+// container that does not exist, or the needs contain a cycle.
+func (c *ContainerSlice) DAG() (*DAG, error) {
+	names := []string{}
+	needs := make(map[string][]string)
+
+	// check if any container declares needs
+	declared := false
+
+	for _, container := range *c {
+		if len(container.Needs) > 0 {
+			declared = true
+
+			break
+		}
+	}
+
+	// iterate through each container in the pipeline
+	for i, container := range *c {
+		names = append(names, container.Name)
+
+		switch {
+		case declared:
+			needs[container.Name] = container.Needs
+		case i > 0:
+			// implicitly need the previous container
+			needs[container.Name] = []string{(*c)[i-1].Name}
+		}
+	}
+
+	return newDAG("step", names, needs)
+}
+
+// newDAG is a helper function to create and verify a
+// dependency graph from the provided names and needs.
+func newDAG(kind string, names []string, needs map[string][]string) (*DAG, error) {
+	d := &DAG{
+		nodes:      []string{},
+		index:      make(map[string]int),
+		upstream:   make(map[string][]string),
+		downstream: make(map[string][]string),
+	}
+
+	errs := []error{}
+
+	// capture every node in the order it was declared
+	for _, name := range names {
+		if _, ok := d.index[name]; ok {
+			errs = append(errs, fmt.Errorf("%w: %s %s", ErrDuplicateNode, kind, name))
+
+			continue
+		}
+
+		d.index[name] = len(d.nodes)
+		d.nodes = append(d.nodes, name)
+	}
+
+	// capture the edges between every node
+	for _, name := range d.nodes {
+		seen := make(map[string]bool)
+
+		for _, need := range needs[name] {
+			// skip needs that have already been processed
+			if seen[need] {
+				continue
+			}
+
+			seen[need] = true
+
+			if need == name {
+				errs = append(errs, fmt.Errorf("%w: %s %s needs itself", ErrSelfNeeds, kind, name))
+
+				continue
+			}
+
+			if _, ok := d.index[need]; !ok {
+				errs = append(errs, fmt.Errorf("%w: %s %s needs %s", ErrUnknownNeeds, kind, name, need))
+
+				continue
+			}
+
+			d.upstream[name] = append(d.upstream[name], need)
+			d.downstream[need] = append(d.downstream[need], name)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// verify the graph does not contain a cycle
+	cycle := d.cycle()
+	if cycle != nil {
+		return nil, &CycleError{Path: cycle}
+	}
+
+	return d, nil
+}
+
+// Nodes returns the name of every node in
+// the graph in the order they were declared.
+func (d *DAG) Nodes() []string {
+	return append([]string{}, d.nodes...)
+}
+
+// Needs returns the names of the nodes the
+// provided node directly depends on.
+func (d *DAG) Needs(name string) []string {
+	return append([]string{}, d.upstream[name]...)
+}
+
+// Waves returns the nodes of the graph grouped into ordered
+// waves. Every node in a wave only depends on nodes from the
+// previous waves, so the nodes in a wave can run in parallel.
+// The nodes in every wave are in the order they were declared.
+func (d *DAG) Waves() [][]string {
+	waves := [][]string{}
+
+	// capture the number of unfinished dependencies for every node
+	remaining := make(map[string]int)
+	for _, name := range d.nodes {
+		remaining[name] = len(d.upstream[name])
+	}
+
+	// capture the nodes without any dependencies
+	wave := []string{}
+
+	for _, name := range d.nodes {
+		if remaining[name] == 0 {
+			wave = append(wave, name)
+		}
+	}
+
+	for len(wave) > 0 {
+		waves = append(waves, wave)
+
+		ready := make(map[string]bool)
+
+		// release the nodes that depend on the current wave
+		for _, name := range wave {
+			for _, child := range d.downstream[name] {
+				remaining[child]--
+
+				if remaining[child] == 0 {
+					ready[child] = true
+				}
+			}
+		}
+
+		wave = d.sort(ready)
+	}
+
+	return waves
+}
+
+// Upstream returns the names of every node the provided node
+// transitively depends on in the order they were declared.
+func (d *DAG) Upstream(name string) []string {
+	return d.walk(name, d.upstream)
+}
+
+// Downstream returns the names of every node that transitively
+// depends on the provided node in the order they were declared.
+func (d *DAG) Downstream(name string) []string {
+	return d.walk(name, d.downstream)
+}
+
+// walk is a helper function to capture every node that can
+// be reached from the provided node using the provided edges.
+func (d *DAG) walk(name string, edges map[string][]string) []string {
+	found := make(map[string]bool)
+	queue := append([]string{}, edges[name]...)
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		if found[next] {
+			continue
+		}
+
+		found[next] = true
+		queue = append(queue, edges[next]...)
+	}
+
+	return d.sort(found)
+}
+
+// sort is a helper function to return the provided
+// set of nodes in the order they were declared.
+func (d *DAG) sort(set map[string]bool) []string {
+	sorted := []string{}
+
+	for _, name := range d.nodes {
+		if set[name] {
+			sorted = append(sorted, name)
+		}
+	}
+
+	return sorted
+}
+
+// cycle is a helper function to return the path for the
+// first cycle found in the graph. When the graph does
+// not contain a cycle, the function returns nil.
+func (d *DAG) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	stack := []string{}
+
+	var visit func(name string) []string
+
+	visit = func(name string) []string {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		for _, need := range d.upstream[name] {
+			switch state[need] {
+			case visiting:
+				// capture the path from the start of the cycle
+				for i, n := range stack {
+					if n == need {
+						return append(append([]string{}, stack[i:]...), need)
+					}
+				}
+			case unvisited:
+				if path := visit(need); path != nil {
+					return path
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	for _, name := range d.nodes {
+		if state[name] != unvisited {
+			continue
+		}
+
+		if path := visit(name); path != nil {
+			return path
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPipeline_StageSlice_DAG(t *testing.T) {
+	// setup types
+	stages := &StageSlice{
+		{Name: "clone"},
+		{Name: "install", Needs: []string{"clone"}},
+		{Name: "lint", Needs: []string{"install"}},
+		{Name: "test", Needs: []string{"install", "clone"}},
+		{Name: "docs", Needs: []string{"clone"}},
+		{Name: "publish", Needs: []string{"lint", "test"}},
+	}
+
+	// run test
+	got, err := stages.DAG()
+	if err != nil {
+		t.Fatalf("DAG returned err: %v", err)
+	}
+
+	wantWaves := [][]string{
+		{"clone"},
+		{"install", "docs"},
+		{"lint", "test"},
+		{"publish"},
+	}
+
+	if !reflect.DeepEqual(got.Waves(), wantWaves) {
+		t.Errorf("Waves is %v, want %v", got.Waves(), wantWaves)
+	}
+
+	wantUpstream := []string{"clone", "install", "lint", "test"}
+
+	if !reflect.DeepEqual(got.Upstream("publish"), wantUpstream) {
+		t.Errorf("Upstream is %v, want %v", got.Upstream("publish"), wantUpstream)
+	}
+
+	wantDownstream := []string{"lint", "test", "publish"}
+
+	if !reflect.DeepEqual(got.Downstream("install"), wantDownstream) {
+		t.Errorf("Downstream is %v, want %v", got.Downstream("install"), wantDownstream)
+	}
+
+	if len(got.Upstream("clone")) != 0 {
+		t.Errorf("Upstream for clone is %v, want []", got.Upstream("clone"))
+	}
+
+	if !reflect.DeepEqual(got.Needs("test"), []string{"install", "clone"}) {
+		t.Errorf("Needs is %v, want [install clone]", got.Needs("test"))
+	}
+
+	if !reflect.DeepEqual(got.Nodes(), []string{"clone", "install", "lint", "test", "docs", "publish"}) {
+		t.Errorf("Nodes is %v", got.Nodes())
+	}
+}
+
+func TestPipeline_StageSlice_DAG_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		stages *StageSlice
+		want   error
+	}{
+		{
+			name: "unknown",
+			stages: &StageSlice{
+				{Name: "clone"},
+				{Name: "test", Needs: []string{"clone", "install"}},
+			},
+			want: ErrUnknownNeeds,
+		},
+		{
+			name: "self",
+			stages: &StageSlice{
+				{Name: "test", Needs: []string{"test"}},
+			},
+			want: ErrSelfNeeds,
+		},
+		{
+			name: "duplicate",
+			stages: &StageSlice{
+				{Name: "test"},
+				{Name: "test"},
+			},
+			want: ErrDuplicateNode,
+		},
+		{
+			name: "cycle",
+			stages: &StageSlice{
+				{Name: "clone"},
+				{Name: "a", Needs: []string{"clone", "c"}},
+				{Name: "b", Needs: []string{"a"}},
+				{Name: "c", Needs: []string{"b"}},
+			},
+			want: ErrCycle,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := test.stages.DAG()
+
+		if !errors.Is(err, test.want) {
+			t.Errorf("DAG for %s returned err %v, want %v", test.name, err, test.want)
+		}
+	}
+}
+
+func TestPipeline_CycleError_Error(t *testing.T) {
+	// setup types
+	stages := &StageSlice{
+		{Name: "a", Needs: []string{"c"}},
+		{Name: "b", Needs: []string{"a"}},
+		{Name: "c", Needs: []string{"b"}},
+	}
+
+	want := []string{"a", "c", "b", "a"}
+
+	// run test
+	_, err := stages.DAG()
+
+	var cycle *CycleError
+	if !errors.As(err, &cycle) {
+		t.Fatalf("DAG returned err %v, want CycleError", err)
+	}
+
+	if !reflect.DeepEqual(cycle.Path, want) {
+		t.Errorf("Path is %v, want %v", cycle.Path, want)
+	}
+
+	if cycle.Error() != "dependency cycle detected: a -> c -> b -> a" {
+		t.Errorf("Error is %s", cycle.Error())
+	}
+}
+
+func TestPipeline_ContainerSlice_DAG(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name       string
+		containers *ContainerSlice
+		want       [][]string
+	}{
+		{
+			name: "sequential",
+			containers: &ContainerSlice{
+				{Name: "clone"},
+				{Name: "install"},
+				{Name: "test"},
+			},
+			want: [][]string{{"clone"}, {"install"}, {"test"}},
+		},
+		{
+			name: "needs",
+			containers: &ContainerSlice{
+				{Name: "clone"},
+				{Name: "api", Needs: []string{"clone"}},
+				{Name: "web", Needs: []string{"clone"}},
+				{Name: "docs"},
+				{Name: "deploy", Needs: []string{"api", "web"}},
+			},
+			want: [][]string{{"clone", "docs"}, {"api", "web"}, {"deploy"}},
+		},
+		{
+			name:       "empty",
+			containers: new(ContainerSlice),
+			want:       [][]string{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.containers.DAG()
+		if err != nil {
+			t.Errorf("DAG for %s returned err: %v", test.name, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got.Waves(), test.want) {
+			t.Errorf("Waves for %s is %v, want %v", test.name, got.Waves(), test.want)
+		}
+	}
+}
+
+func TestPipeline_ContainerSlice_DAG_Failure(t *testing.T) {
+	// setup types
+	containers := &ContainerSlice{
+		{Name: "clone"},
+		{Name: "test", Needs: []string{"test", "build"}},
+	}
+
+	// run test
+	_, err := containers.DAG()
+
+	if !errors.Is(err, ErrSelfNeeds) || !errors.Is(err, ErrUnknownNeeds) {
+		t.Errorf("DAG returned err %v, want %v and %v", err, ErrSelfNeeds, ErrUnknownNeeds)
+	}
+}
@@ -0,0 +1,1129 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ExpressionError is the error returned when an expression for
+// a ruleset can not be parsed or evaluated. The column is the
+// position, starting at 1, in the expression for the problem.
+type ExpressionError struct {
+	Expression string
+	Column     int
+	Message    string
+}
+
+// Error implements the error interface for the ExpressionError type.
+func (e *ExpressionError) Error() string {
+	return fmt.Sprintf("invalid expression %q at column %d: %s", e.Expression, e.Column, e.Message)
+}
+
+// Expression is the pipeline representation of a parsed
+// expression from the if or unless block of a ruleset,
+// like `branch == 'main' && (event == 'push' || tag in ['v1'])`.
+//
+// The following rule data fields can be referenced:
+//
+//   - branch, comment, event, repo, status, tag, target, instance (string)
+//   - message, author, sender, base_ref (string)
+//   - path, label (list)
+//   - env.NAME (string) for the environment variable NAME
+//
+// The following operators are supported, in order of precedence:
+//
+//   - ( ) for grouping
+//   - ! for negation
+//   - ==, != and in for comparison
+//   - && for and
+//   - || for or
+//
+// The following functions are supported. When a list is provided
+// as the first argument, the function returns true when any value
+// from the list matches:
+//
+//   - matches(value, regexp)
+//   - glob(value, pattern), see MatchGlob for the syntax
+//   - contains(value, substring)
+//   - startsWith(value, prefix)
+//   - endsWith(value, suffix)
+//
+// Deprecated: use Expression from github.com/go-vela/server/compiler/types/pipeline instead.
+type Expression struct {
+	raw  string
+	root exprNode
+}
+
+// ParseExpression parses and type checks the provided expression.
+// An ExpressionError is returned with the position of the problem
+// when the expression is invalid.
+//
+// Parsed expressions are cached, so the same expression
+// may be returned for multiple calls.
+func ParseExpression(expr string) (*Expression, error) {
+	key := "expression:" + expr
+
+	// return the parsed expression from the cache if it exists
+	if e, ok := patterns.get(key); ok {
+		return e.(*Expression), nil
+	}
+
+	p := &exprParser{raw: expr}
+
+	// tokenize the expression
+	err := p.tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	// parse the tokens into a tree
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the entire expression was parsed
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok.pos, "unexpected %s", tok)
+	}
+
+	// verify the expression produces a boolean
+	if root.kind() != kindBool {
+		return nil, p.errorf(root.pos(), "expression must produce a boolean, got %s", root.kind())
+	}
+
+	e := &Expression{raw: expr, root: root}
+
+	patterns.add(key, e)
+
+	return e, nil
+}
+
+// String implements the Stringer interface for the Expression type.
+func (e *Expression) String() string {
+	return e.raw
+}
+
+// Eval returns the result of evaluating the
+// expression against the provided ruledata.
+func (e *Expression) Eval(data *RuleData) (bool, error) {
+	// use empty ruledata if none is provided
+	if data == nil {
+		data = new(RuleData)
+	}
+
+	v, err := e.root.eval(&exprContext{raw: e.raw, data: data})
+	if err != nil {
+		return false, err
+	}
+
+	return v.b, nil
+}
+
+// Fields returns the name of every ruledata field, in sorted
+// order, referenced by the expression, i.e. `branch` or
+// `env.DEPLOY_TARGET`.
+func (e *Expression) Fields() []string {
+	set := make(map[string]bool)
+
+	e.root.fields(set)
+
+	fields := []string{}
+
+	for field := range set {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// References returns true if the expression
+// references any of the provided ruledata fields.
+func (e *Expression) References(fields ...string) bool {
+	set := make(map[string]bool)
+
+	e.root.fields(set)
+
+	for _, field := range fields {
+		if set[field] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// partial returns the source for the expression with every term
+// referencing any of the provided fields replaced with a boolean
+// literal, so the term never prevents the expression from producing
+// the provided result. An empty string is returned when every term
+// of the expression references the provided fields.
+func (e *Expression) partial(result bool, fields ...string) string {
+	set := make(map[string]bool)
+
+	for _, field := range fields {
+		set[field] = true
+	}
+
+	root := partialNode(e.root, result, set)
+
+	// return an empty string if nothing is left to evaluate
+	if _, ok := root.(*literalNode); ok {
+		return ""
+	}
+
+	return root.format()
+}
+
+// partialNode is a helper function to replace every term of the
+// provided node referencing the provided fields with the provided
+// result. The operands of the logical operators are folded when
+// they are replaced so the result contains no redundant literals.
+func partialNode(n exprNode, result bool, fields map[string]bool) exprNode {
+	switch node := n.(type) {
+	case *notNode:
+		// the negated term must produce the opposite result
+		x := partialNode(node.x, !result, fields)
+
+		if lit, ok := x.(*literalNode); ok {
+			return &literalNode{at: node.at, typ: kindBool, value: exprValue{b: !lit.value.b}}
+		}
+
+		return &notNode{at: node.at, x: x}
+	case *binaryNode:
+		if node.op != "&&" && node.op != "||" {
+			break
+		}
+
+		left := partialNode(node.left, result, fields)
+		right := partialNode(node.right, result, fields)
+
+		// fold the operands that are replaced with a literal
+		for _, pair := range [][2]exprNode{{left, right}, {right, left}} {
+			lit, ok := pair[0].(*literalNode)
+			if !ok {
+				continue
+			}
+
+			// `true && x` and `false || x` produce x
+			if lit.value.b == (node.op == "&&") {
+				return pair[1]
+			}
+
+			// `false && x` and `true || x` produce the literal
+			return lit
+		}
+
+		return &binaryNode{at: node.at, op: node.op, left: left, right: right}
+	}
+
+	set := make(map[string]bool)
+
+	n.fields(set)
+
+	// replace the term if it references any of the fields
+	for field := range set {
+		if fields[field] {
+			return &literalNode{at: n.pos(), typ: kindBool, value: exprValue{b: result}}
+		}
+	}
+
+	return n
+}
+
+// exprKind represents the type of a value in an expression.
+type exprKind int
+
+const (
+	kindBool exprKind = iota
+	kindString
+	kindList
+)
+
+// String implements the Stringer interface for the exprKind type.
+func (k exprKind) String() string {
+	switch k {
+	case kindBool:
+		return "boolean"
+	case kindString:
+		return "string"
+	default:
+		return "list"
+	}
+}
+
+// exprValue represents the value produced by a node in an expression.
+type exprValue struct {
+	b    bool
+	s    string
+	list []string
+}
+
+// exprContext represents the data available while evaluating an expression.
+type exprContext struct {
+	raw  string
+	data *RuleData
+}
+
+// exprNode represents a node from the tree for a parsed expression.
+type exprNode interface {
+	eval(ctx *exprContext) (exprValue, error)
+	fields(set map[string]bool)
+	format() string
+	kind() exprKind
+	pos() int
+}
+
+type (
+	// literalNode represents a string or boolean literal.
+	literalNode struct {
+		at    int
+		typ   exprKind
+		value exprValue
+	}
+
+	// listNode represents a list of strings, i.e. `['push', 'tag']`.
+	listNode struct {
+		at    int
+		items []exprNode
+	}
+
+	// fieldNode represents a reference to a ruledata field.
+	fieldNode struct {
+		at   int
+		name string
+	}
+
+	// notNode represents the negation of a boolean.
+	notNode struct {
+		at int
+		x  exprNode
+	}
+
+	// binaryNode represents an operator with two operands.
+	binaryNode struct {
+		at    int
+		op    string
+		left  exprNode
+		right exprNode
+	}
+
+	// callNode represents a call to a function.
+	callNode struct {
+		at   int
+		name string
+		args []exprNode
+		re   *regexp.Regexp
+	}
+)
+
+// exprFields represents the type for every
+// ruledata field that can be referenced.
+var exprFields = map[string]exprKind{
+	"author":   kindString,
+	"base_ref": kindString,
+	"branch":   kindString,
+	"comment":  kindString,
+	"event":    kindString,
+	"instance": kindString,
+	"label":    kindList,
+	"message":  kindString,
+	"path":     kindList,
+	"repo":     kindString,
+	"sender":   kindString,
+	"status":   kindString,
+	"tag":      kindString,
+	"target":   kindString,
+}
+
+// exprFunctions represents every function that can be called
+// with the function used to compare a value to the argument.
+var exprFunctions = map[string]func(value, arg string, re *regexp.Regexp) bool{
+	"contains":   func(value, arg string, _ *regexp.Regexp) bool { return strings.Contains(value, arg) },
+	"endsWith":   func(value, arg string, _ *regexp.Regexp) bool { return strings.HasSuffix(value, arg) },
+	"startsWith": func(value, arg string, _ *regexp.Regexp) bool { return strings.HasPrefix(value, arg) },
+	"matches":    func(value, _ string, re *regexp.Regexp) bool { return re.MatchString(value) },
+	"glob": func(value, arg string, _ *regexp.Regexp) bool {
+		ok, _ := MatchGlob(arg, value)
+		return ok
+	},
+}
+
+// eval returns the value for the literal.
+func (n *literalNode) eval(_ *exprContext) (exprValue, error) {
+	return n.value, nil
+}
+
+// fields captures the fields referenced by the literal.
+func (n *literalNode) fields(_ map[string]bool) {}
+
+// kind returns the type for the literal.
+func (n *literalNode) kind() exprKind {
+	return n.typ
+}
+
+// format returns the source for the literal.
+func (n *literalNode) format() string {
+	if n.typ == kindBool {
+		return fmt.Sprintf("%t", n.value.b)
+	}
+
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(n.value.s) + "'"
+}
+
+// pos returns the position for the literal.
+func (n *literalNode) pos() int {
+	return n.at
+}
+
+// eval returns the value for the list.
+func (n *listNode) eval(ctx *exprContext) (exprValue, error) {
+	list := []string{}
+
+	for _, item := range n.items {
+		v, err := item.eval(ctx)
+		if err != nil {
+			return exprValue{}, err
+		}
+
+		list = append(list, v.s)
+	}
+
+	return exprValue{list: list}, nil
+}
+
+// fields captures the fields referenced by the list.
+func (n *listNode) fields(set map[string]bool) {
+	for _, item := range n.items {
+		item.fields(set)
+	}
+}
+
+// format returns the source for the list.
+func (n *listNode) format() string {
+	items := []string{}
+
+	for _, item := range n.items {
+		items = append(items, item.format())
+	}
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// kind returns the type for the list.
+func (n *listNode) kind() exprKind {
+	return kindList
+}
+
+// pos returns the position for the list.
+func (n *listNode) pos() int {
+	return n.at
+}
+
+// eval returns the value for the field.
+func (n *fieldNode) eval(ctx *exprContext) (exprValue, error) {
+	d := ctx.data
+
+	// handle references to environment variables
+	if strings.HasPrefix(n.name, "env.") {
+		return exprValue{s: d.Env[strings.TrimPrefix(n.name, "env.")]}, nil
+	}
+
+	switch n.name {
+	case "author":
+		return exprValue{s: d.Author}, nil
+	case "base_ref":
+		return exprValue{s: d.BaseRef}, nil
+	case "branch":
+		return exprValue{s: d.Branch}, nil
+	case "comment":
+		return exprValue{s: d.Comment}, nil
+	case "event":
+		return exprValue{s: d.Event}, nil
+	case "instance":
+		return exprValue{s: d.Instance}, nil
+	case "label":
+		return exprValue{list: d.Label}, nil
+	case "message":
+		return exprValue{s: d.Message}, nil
+	case "path":
+		return exprValue{list: d.Path}, nil
+	case "repo":
+		return exprValue{s: d.Repo}, nil
+	case "sender":
+		return exprValue{s: d.Sender}, nil
+	case "status":
+		return exprValue{s: d.Status}, nil
+	case "tag":
+		return exprValue{s: d.Tag}, nil
+	default:
+		return exprValue{s: d.Target}, nil
+	}
+}
+
+// fields captures the fields referenced by the field.
+func (n *fieldNode) fields(set map[string]bool) {
+	set[n.name] = true
+}
+
+// format returns the source for the field.
+func (n *fieldNode) format() string {
+	return n.name
+}
+
+// pos returns the position for the field.
+func (n *fieldNode) pos() int {
+	return n.at
+}
+
+// kind returns the type for the field.
+func (n *fieldNode) kind() exprKind {
+	if k, ok := exprFields[n.name]; ok {
+		return k
+	}
+
+	return kindString
+}
+
+// eval returns the value for the negation.
+func (n *notNode) eval(ctx *exprContext) (exprValue, error) {
+	v, err := n.x.eval(ctx)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	return exprValue{b: !v.b}, nil
+}
+
+// fields captures the fields referenced by the negation.
+func (n *notNode) fields(set map[string]bool) {
+	n.x.fields(set)
+}
+
+// format returns the source for the negation.
+func (n *notNode) format() string {
+	return "!" + group(n.x, 4)
+}
+
+// kind returns the type for the negation.
+func (n *notNode) kind() exprKind {
+	return kindBool
+}
+
+// pos returns the position for the negation.
+func (n *notNode) pos() int {
+	return n.at
+}
+
+// eval returns the value for the operator.
+func (n *binaryNode) eval(ctx *exprContext) (exprValue, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	// short circuit the logical operators
+	switch {
+	case n.op == "&&" && !left.b:
+		return exprValue{b: false}, nil
+	case n.op == "||" && left.b:
+		return exprValue{b: true}, nil
+	}
+
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	switch n.op {
+	case "&&", "||":
+		return exprValue{b: right.b}, nil
+	case "==":
+		// operands are verified to have the same type when parsing
+		return exprValue{b: left.s == right.s && left.b == right.b}, nil
+	case "!=":
+		return exprValue{b: left.s != right.s || left.b != right.b}, nil
+	default:
+		// handle the `in` operator
+		for _, item := range right.list {
+			if item == left.s {
+				return exprValue{b: true}, nil
+			}
+		}
+
+		return exprValue{b: false}, nil
+	}
+}
+
+// fields captures the fields referenced by the operator.
+func (n *binaryNode) fields(set map[string]bool) {
+	n.left.fields(set)
+	n.right.fields(set)
+}
+
+// format returns the source for the operator.
+func (n *binaryNode) format() string {
+	p := precedence(n)
+
+	return group(n.left, p) + " " + n.op + " " + group(n.right, p)
+}
+
+// kind returns the type for the operator.
+func (n *binaryNode) kind() exprKind {
+	return kindBool
+}
+
+// pos returns the position for the operator.
+func (n *binaryNode) pos() int {
+	return n.at
+}
+
+// eval returns the value for the function call.
+func (n *callNode) eval(ctx *exprContext) (exprValue, error) {
+	value, err := n.args[0].eval(ctx)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	arg, err := n.args[1].eval(ctx)
+	if err != nil {
+		return exprValue{}, err
+	}
+
+	re := n.re
+
+	// compile the pattern when it is not a literal
+	if n.name == "matches" && re == nil {
+		re, err = regexp.Compile(arg.s)
+		if err != nil {
+			return exprValue{}, &ExpressionError{Expression: ctx.raw, Column: n.args[1].pos(), Message: fmt.Sprintf("invalid regexp %q: %v", arg.s, err)}
+		}
+	}
+
+	fn := exprFunctions[n.name]
+
+	// compare every value when a list is provided
+	if n.args[0].kind() == kindList {
+		for _, item := range value.list {
+			if fn(item, arg.s, re) {
+				return exprValue{b: true}, nil
+			}
+		}
+
+		return exprValue{b: false}, nil
+	}
+
+	return exprValue{b: fn(value.s, arg.s, re)}, nil
+}
+
+// fields captures the fields referenced by the function call.
+func (n *callNode) fields(set map[string]bool) {
+	for _, arg := range n.args {
+		arg.fields(set)
+	}
+}
+
+// format returns the source for the function call.
+func (n *callNode) format() string {
+	args := []string{}
+
+	for _, arg := range n.args {
+		args = append(args, arg.format())
+	}
+
+	return n.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// kind returns the type for the function call.
+func (n *callNode) kind() exprKind {
+	return kindBool
+}
+
+// pos returns the position for the function call.
+func (n *callNode) pos() int {
+	return n.at
+}
+
+// precedence is a helper function to return the
+// precedence of the provided node when formatting.
+func precedence(n exprNode) int {
+	node, ok := n.(*binaryNode)
+	if !ok {
+		return 5
+	}
+
+	switch node.op {
+	case "||":
+		return 1
+	case "&&":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// group is a helper function to return the source for the provided
+// operand with parentheses when they are needed for the operator
+// with the provided precedence. The comparison operators are not
+// associative so a comparison operand is always grouped.
+func group(n exprNode, parent int) string {
+	p := precedence(n)
+
+	if p < parent || (p == parent && p == 3) {
+		return "(" + n.format() + ")"
+	}
+
+	return n.format()
+}
+
+// token kinds for an expression.
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenPunct
+)
+
+// exprToken represents a single token from an expression.
+type exprToken struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// String implements the Stringer interface for the exprToken type.
+func (t exprToken) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// exprParser represents the state for parsing an expression.
+type exprParser struct {
+	raw    string
+	tokens []exprToken
+	index  int
+}
+
+// errorf is a helper function to create an
+// error for the provided position.
+func (p *exprParser) errorf(pos int, format string, args ...interface{}) error {
+	return &ExpressionError{Expression: p.raw, Column: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// tokenize is a helper function to break the
+// expression into tokens for the parser.
+func (p *exprParser) tokenize() error {
+	runes := []rune(p.raw)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+
+			p.tokens = append(p.tokens, exprToken{kind: tokenIdent, value: string(runes[start:i]), pos: pos})
+		case r == '\'' || r == '"':
+			var value strings.Builder
+
+			i++
+
+			for {
+				if i >= len(runes) {
+					return p.errorf(pos, "unterminated string")
+				}
+
+				// handle escaped characters
+				if runes[i] == '\\' && i+1 < len(runes) {
+					value.WriteRune(runes[i+1])
+
+					i += 2
+
+					continue
+				}
+
+				if runes[i] == r {
+					i++
+
+					break
+				}
+
+				value.WriteRune(runes[i])
+
+				i++
+			}
+
+			p.tokens = append(p.tokens, exprToken{kind: tokenString, value: value.String(), pos: pos})
+		case r == '(' || r == ')' || r == '[' || r == ']' || r == ',':
+			p.tokens = append(p.tokens, exprToken{kind: tokenPunct, value: string(r), pos: pos})
+
+			i++
+		default:
+			// capture operators with two characters
+			if i+1 < len(runes) {
+				op := string(runes[i : i+2])
+
+				switch op {
+				case "==", "!=", "&&", "||":
+					p.tokens = append(p.tokens, exprToken{kind: tokenOperator, value: op, pos: pos})
+
+					i += 2
+
+					continue
+				}
+			}
+
+			if r == '!' {
+				p.tokens = append(p.tokens, exprToken{kind: tokenOperator, value: "!", pos: pos})
+
+				i++
+
+				continue
+			}
+
+			return p.errorf(pos, "unexpected character %q", r)
+		}
+	}
+
+	p.tokens = append(p.tokens, exprToken{kind: tokenEOF, pos: len(runes) + 1})
+
+	return nil
+}
+
+// peek is a helper function to return the next token.
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.index]
+}
+
+// next is a helper function to consume the next token.
+func (p *exprParser) next() exprToken {
+	tok := p.tokens[p.index]
+
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+
+	return tok
+}
+
+// expect is a helper function to consume the next
+// token and verify it has the provided value.
+func (p *exprParser) expect(value string) error {
+	tok := p.next()
+	if tok.kind == tokenString || tok.value != value {
+		return p.errorf(tok.pos, "expected %q, got %s", value, tok)
+	}
+
+	return nil
+}
+
+// parseOr is a helper function to parse the `||` operator.
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOperator && p.peek().value == "||" {
+		tok := p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left, err = p.logical(tok, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return left, nil
+}
+
+// parseAnd is a helper function to parse the `&&` operator.
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOperator && p.peek().value == "&&" {
+		tok := p.next()
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		left, err = p.logical(tok, left, right)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return left, nil
+}
+
+// logical is a helper function to create and type
+// check a node for the `&&` and `||` operators.
+func (p *exprParser) logical(tok exprToken, left, right exprNode) (exprNode, error) {
+	for _, operand := range []exprNode{left, right} {
+		if operand.kind() != kindBool {
+			return nil, p.errorf(operand.pos(), "operator %s requires boolean operands, got %s", tok.value, operand.kind())
+		}
+	}
+
+	return &binaryNode{at: tok.pos, op: tok.value, left: left, right: right}, nil
+}
+
+// parseUnary is a helper function to parse the `!` operator.
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && tok.value == "!" {
+		p.next()
+
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		if x.kind() != kindBool {
+			return nil, p.errorf(x.pos(), "operator ! requires a boolean operand, got %s", x.kind())
+		}
+
+		return &notNode{at: tok.pos, x: x}, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison is a helper function to parse
+// the `==`, `!=` and `in` operators.
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+
+	switch {
+	case tok.kind == tokenOperator && (tok.value == "==" || tok.value == "!="):
+		p.next()
+
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		// verify both operands have the same type
+		if left.kind() == kindList || right.kind() == kindList {
+			return nil, p.errorf(tok.pos, "operator %s can not compare a list, use `in` or a function", tok.value)
+		}
+
+		if left.kind() != right.kind() {
+			return nil, p.errorf(tok.pos, "operator %s can not compare %s and %s", tok.value, left.kind(), right.kind())
+		}
+
+		return &binaryNode{at: tok.pos, op: tok.value, left: left, right: right}, nil
+	case tok.kind == tokenIdent && tok.value == "in":
+		p.next()
+
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		// verify a string is checked against a list
+		if left.kind() != kindString || right.kind() != kindList {
+			return nil, p.errorf(tok.pos, "operator in requires a string and a list, got %s and %s", left.kind(), right.kind())
+		}
+
+		return &binaryNode{at: tok.pos, op: "in", left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+// parsePrimary is a helper function to parse literals,
+// fields, lists, function calls and groups.
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenString:
+		return &literalNode{at: tok.pos, typ: kindString, value: exprValue{s: tok.value}}, nil
+	case tokenIdent:
+		switch tok.value {
+		case "true", "false":
+			return &literalNode{at: tok.pos, typ: kindBool, value: exprValue{b: tok.value == "true"}}, nil
+		}
+
+		// handle the identifier as a function call
+		if next := p.peek(); next.kind == tokenPunct && next.value == "(" {
+			return p.parseCall(tok)
+		}
+
+		// verify the identifier is a known field
+		if _, ok := exprFields[tok.value]; ok {
+			return &fieldNode{at: tok.pos, name: tok.value}, nil
+		}
+
+		if strings.HasPrefix(tok.value, "env.") && len(tok.value) > len("env.") && !strings.Contains(strings.TrimPrefix(tok.value, "env."), ".") {
+			return &fieldNode{at: tok.pos, name: tok.value}, nil
+		}
+
+		return nil, p.errorf(tok.pos, "unknown field %s", tok.value)
+	case tokenPunct:
+		switch tok.value {
+		case "(":
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+
+			err = p.expect(")")
+			if err != nil {
+				return nil, err
+			}
+
+			return x, nil
+		case "[":
+			return p.parseList(tok)
+		}
+	}
+
+	return nil, p.errorf(tok.pos, "unexpected %s", tok)
+}
+
+// parseList is a helper function to parse a list of strings.
+func (p *exprParser) parseList(open exprToken) (exprNode, error) {
+	list := &listNode{at: open.pos}
+
+	for {
+		// handle the end of the list
+		if tok := p.peek(); tok.kind == tokenPunct && tok.value == "]" {
+			p.next()
+
+			return list, nil
+		}
+
+		item, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		if item.kind() != kindString {
+			return nil, p.errorf(item.pos(), "list items must be strings, got %s", item.kind())
+		}
+
+		list.items = append(list.items, item)
+
+		// handle the separator between items
+		if tok := p.peek(); tok.kind == tokenPunct && tok.value == "," {
+			p.next()
+
+			continue
+		}
+
+		err = p.expect("]")
+		if err != nil {
+			return nil, err
+		}
+
+		return list, nil
+	}
+}
+
+// parseCall is a helper function to parse a function call.
+func (p *exprParser) parseCall(name exprToken) (exprNode, error) {
+	// verify the function exists
+	if _, ok := exprFunctions[name.value]; !ok {
+		return nil, p.errorf(name.pos, "unknown function %s", name.value)
+	}
+
+	// consume the opening parenthesis
+	p.next()
+
+	call := &callNode{at: name.pos, name: name.value}
+
+	for {
+		if tok := p.peek(); tok.kind == tokenPunct && tok.value == ")" && len(call.args) == 0 {
+			break
+		}
+
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		call.args = append(call.args, arg)
+
+		if tok := p.peek(); tok.kind == tokenPunct && tok.value == "," {
+			p.next()
+
+			continue
+		}
+
+		break
+	}
+
+	err := p.expect(")")
+	if err != nil {
+		return nil, err
+	}
+
+	// verify the arguments for the function
+	if len(call.args) != 2 {
+		return nil, p.errorf(name.pos, "function %s expects 2 arguments, got %d", name.value, len(call.args))
+	}
+
+	if call.args[0].kind() == kindBool {
+		return nil, p.errorf(call.args[0].pos(), "function %s expects a string or list, got %s", name.value, call.args[0].kind())
+	}
+
+	if call.args[1].kind() != kindString {
+		return nil, p.errorf(call.args[1].pos(), "function %s expects a string, got %s", name.value, call.args[1].kind())
+	}
+
+	// compile the pattern when it is a literal
+	if literal, ok := call.args[1].(*literalNode); ok && call.name == "matches" {
+		re, err := regexp.Compile(literal.value.s)
+		if err != nil {
+			return nil, p.errorf(literal.pos(), "invalid regexp %q: %v", literal.value.s, err)
+		}
+
+		call.re = re
+	}
+
+	// verify the pattern when it is a literal
+	if literal, ok := call.args[1].(*literalNode); ok && call.name == "glob" {
+		_, err := compileGlob(literal.value.s)
+		if err != nil {
+			return nil, p.errorf(literal.pos(), "%v", err)
+		}
+	}
+
+	return call, nil
+}
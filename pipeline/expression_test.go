@@ -0,0 +1,357 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPipeline_ParseExpression(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		expression string
+		column     int
+		message    string
+	}{
+		{
+			expression: "branch == 'main' && (event == 'push' || tag in ['v1', 'v2'])",
+		},
+		{
+			expression: "!matches(path, '^docs/') && env.DEPLOY != 'false'",
+		},
+		{
+			expression: "branch == 'main' &&",
+			column:     20,
+			message:    "unexpected end of expression",
+		},
+		{
+			expression: "branch",
+			column:     1,
+			message:    "expression must produce a boolean, got string",
+		},
+		{
+			expression: "branch == true",
+			column:     8,
+			message:    "operator == can not compare string and boolean",
+		},
+		{
+			expression: "path == 'foo.txt'",
+			column:     6,
+			message:    "operator == can not compare a list, use `in` or a function",
+		},
+		{
+			expression: "event == 'push' && committer == 'octocat'",
+			column:     20,
+			message:    "unknown field committer",
+		},
+		{
+			expression: "lower(branch) == 'main'",
+			column:     1,
+			message:    "unknown function lower",
+		},
+		{
+			expression: "matches(tag, '[')",
+			column:     14,
+			message:    "invalid regexp \"[\": error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			expression: "branch == 'main",
+			column:     11,
+			message:    "unterminated string",
+		},
+		{
+			expression: "contains(branch)",
+			column:     1,
+			message:    "function contains expects 2 arguments, got 1",
+		},
+		{
+			expression: "(branch == 'main'",
+			column:     18,
+			message:    "expected \")\", got end of expression",
+		},
+		{
+			expression: "branch == 'main' & event == 'push'",
+			column:     18,
+			message:    "unexpected character '&'",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := ParseExpression(test.expression)
+
+		if len(test.message) == 0 {
+			if err != nil {
+				t.Errorf("ParseExpression for %s returned err: %v", test.expression, err)
+			}
+
+			continue
+		}
+
+		var exprErr *ExpressionError
+
+		if !errors.As(err, &exprErr) {
+			t.Errorf("ParseExpression for %s should have returned ExpressionError, got %v", test.expression, err)
+
+			continue
+		}
+
+		if exprErr.Column != test.column {
+			t.Errorf("ParseExpression column for %s is %d, want %d", test.expression, exprErr.Column, test.column)
+		}
+
+		if exprErr.Message != test.message {
+			t.Errorf("ParseExpression message for %s is %s, want %s", test.expression, exprErr.Message, test.message)
+		}
+	}
+}
+
+func TestPipeline_Expression_Eval(t *testing.T) {
+	// setup types
+	data := &RuleData{
+		Branch:  "main",
+		Comment: "run the tests",
+		Event:   "push",
+		Path:    []string{"README.md", "docs/index.md"},
+		Repo:    "github/octocat",
+		Status:  "success",
+		Tag:     "refs/tags/v1.2.3",
+		Label:   []string{"enhancement"},
+		Env:     map[string]string{"DEPLOY_TARGET": "production"},
+	}
+
+	// setup tests
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{expression: "branch == 'main' && (event == 'push' || matches(tag, '^refs/tags/v'))", want: true},
+		{expression: "branch == 'dev' && (event == 'push' || matches(tag, '^refs/tags/v'))", want: false},
+		{expression: "branch != 'main' || event == 'tag'", want: false},
+		{expression: "!(branch == 'dev')", want: true},
+		{expression: "event in ['push', 'tag']", want: true},
+		{expression: "event in []", want: false},
+		{expression: "contains(comment, 'tests')", want: true},
+		{expression: "startsWith(repo, 'github/')", want: true},
+		{expression: "endsWith(tag, '.3')", want: true},
+		{expression: "glob(path, 'docs/*')", want: true},
+		{expression: "startsWith(path, 'src/')", want: false},
+		{expression: "contains(label, 'enhance')", want: true},
+		{expression: "env.DEPLOY_TARGET == 'production'", want: true},
+		{expression: "env.MISSING == ''", want: true},
+		{expression: "matches(branch, env.DEPLOY_TARGET)", want: false},
+		{expression: "status == \"success\" && true", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		expr, err := ParseExpression(test.expression)
+		if err != nil {
+			t.Errorf("ParseExpression for %s returned err: %v", test.expression, err)
+
+			continue
+		}
+
+		got, err := expr.Eval(data)
+		if err != nil {
+			t.Errorf("Eval for %s returned err: %v", test.expression, err)
+		}
+
+		if got != test.want {
+			t.Errorf("Eval for %s is %v, want %v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_Expression_Eval_Invalid(t *testing.T) {
+	// setup types
+	expr, err := ParseExpression("matches(branch, env.PATTERN)")
+	if err != nil {
+		t.Fatalf("ParseExpression returned err: %v", err)
+	}
+
+	// run test
+	_, err = expr.Eval(&RuleData{Env: map[string]string{"PATTERN": "("}})
+
+	var exprErr *ExpressionError
+
+	if !errors.As(err, &exprErr) {
+		t.Fatalf("Eval should have returned ExpressionError, got %v", err)
+	}
+
+	if exprErr.Column != 17 {
+		t.Errorf("Eval column is %d, want 17", exprErr.Column)
+	}
+}
+
+func TestPipeline_Expression_Fields(t *testing.T) {
+	// setup types
+	expr, err := ParseExpression("branch == 'main' && (status == 'failure' || contains(path, env.DIR))")
+	if err != nil {
+		t.Fatalf("ParseExpression returned err: %v", err)
+	}
+
+	want := []string{"branch", "env.DIR", "path", "status"}
+
+	// run test
+	got := expr.Fields()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields is %v, want %v", got, want)
+	}
+
+	if !expr.References("tag", "status") {
+		t.Errorf("References should have returned true")
+	}
+
+	if expr.References("tag", "comment") {
+		t.Errorf("References should have returned false")
+	}
+}
+
+func TestPipeline_Expression_partial(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		expression string
+		result     bool
+		want       string
+	}{
+		{
+			expression: "status == 'failure' && contains(path, 'src/')",
+			result:     true,
+			want:       "status == 'failure'",
+		},
+		{
+			expression: "status == 'failure' && !contains(path, 'src/')",
+			result:     true,
+			want:       "status == 'failure'",
+		},
+		{
+			expression: "(branch == 'main' || comment == 'deploy') && status != 'failure'",
+			result:     true,
+			want:       "status != 'failure'",
+		},
+		{
+			expression: "!(branch == 'main' && contains(label, 'skip')) || tag == 'it\\'s'",
+			result:     false,
+			want:       "!(branch == 'main') || tag == 'it\\'s'",
+		},
+		{
+			expression: "(branch == 'main' || event == 'push') && instance == 'vela'",
+			result:     false,
+			want:       "",
+		},
+		{
+			expression: "contains(path, 'src/') || comment == 'run'",
+			result:     true,
+			want:       "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		expr, err := ParseExpression(test.expression)
+		if err != nil {
+			t.Fatalf("ParseExpression returned err: %v", err)
+		}
+
+		got := expr.partial(test.result, "path", "comment", "label", "instance")
+
+		if got != test.want {
+			t.Errorf("partial for %s is %q, want %q", test.expression, got, test.want)
+		}
+
+		// verify the partial expression can be parsed
+		if len(got) > 0 {
+			_, err = ParseExpression(got)
+			if err != nil {
+				t.Errorf("ParseExpression for %s returned err: %v", got, err)
+			}
+		}
+	}
+}
+
+func TestPipeline_Rules_Match_Expression(t *testing.T) {
+	// setup types
+	data := &RuleData{Branch: "main", Event: "push", Tag: "refs/heads/main"}
+
+	// setup tests
+	tests := []struct {
+		rules    *Rules
+		operator string
+		want     bool
+	}{
+		{
+			rules:    &Rules{Expression: "branch == 'main' && event == 'push'"},
+			operator: "and",
+			want:     true,
+		},
+		{
+			rules:    &Rules{Expression: "branch == 'main'", Event: []string{"tag"}},
+			operator: "and",
+			want:     false,
+		},
+		{
+			rules:    &Rules{Expression: "branch == 'main'", Event: []string{"tag"}},
+			operator: "or",
+			want:     true,
+		},
+		{
+			rules:    &Rules{Expression: "branch == 'dev'", Event: []string{"push"}},
+			operator: "and",
+			want:     false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rules.Match(data, "filepath", test.operator)
+		if err != nil {
+			t.Errorf("Match returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("Match for %s is %v, want %v", test.rules.Expression, got, test.want)
+		}
+	}
+
+	// verify an invalid expression returns an error
+	_, err := (&Rules{Expression: "branch =="}).Match(data, "filepath", "and")
+	if err == nil {
+		t.Errorf("Match should have returned err")
+	}
+}
+
+func TestPipeline_Ruleset_Match_Expression(t *testing.T) {
+	// setup types
+	ruleset := &Ruleset{
+		If:       Rules{Expression: "branch == 'main' && (event == 'push' || matches(tag, '^refs/tags/v'))"},
+		Unless:   Rules{Expression: "env.SKIP == 'true'"},
+		Operator: "and",
+	}
+
+	// setup tests
+	tests := []struct {
+		data *RuleData
+		want bool
+	}{
+		{data: &RuleData{Branch: "main", Event: "push"}, want: true},
+		{data: &RuleData{Branch: "main", Event: "tag", Tag: "refs/tags/v1.0.0"}, want: true},
+		{data: &RuleData{Branch: "main", Event: "tag", Tag: "refs/tags/1.0.0"}, want: false},
+		{data: &RuleData{Branch: "main", Event: "push", Env: map[string]string{"SKIP": "true"}}, want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := ruleset.Match(test.data)
+		if err != nil {
+			t.Errorf("Match returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("Match for %v is %v, want %v", test.data, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidGlob defines the error type when a
+// glob pattern for a ruleset can not be compiled.
+var ErrInvalidGlob = errors.New("invalid glob pattern")
+
+// MatchGlob returns true when the provided value matches
+// the provided glob pattern. The following syntax is supported:
+//
+//   - `*` matches any sequence of characters except `/`
+//   - `?` matches any single character except `/`
+//   - `**` matches any sequence of characters including `/`,
+//     so `src/**/*.go` matches `src/main.go` and `src/a/b/main.go`
+//   - `[abc]`, `[a-z]` and `[!abc]` match a character class
+//   - `{a,b}` matches any of the comma separated alternatives
+//   - `\` escapes the next character
+//
+// Patterns are compiled once and cached for later calls.
+func MatchGlob(pattern, value string) (bool, error) {
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(value), nil
+}
+
+// compileGlob is a helper function to return the compiled
+// regular expression for the provided glob pattern.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	key := "glob:" + pattern
+
+	// return the compiled pattern from the cache if it exists
+	if re, ok := patterns.get(key); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	expr, err := globToRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidGlob, pattern, err)
+	}
+
+	patterns.add(key, re)
+
+	return re, nil
+}
+
+// globToRegexp is a helper function to translate the
+// provided glob pattern to a regular expression.
+func globToRegexp(pattern string) (string, error) {
+	runes := []rune(pattern)
+
+	b := new(strings.Builder)
+	b.WriteString("^")
+
+	// capture the depth of nested braces
+	braces := 0
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch r {
+		case '\\':
+			// verify the escape is followed by a character
+			if i+1 >= len(runes) {
+				return "", fmt.Errorf("%w %s: trailing escape character", ErrInvalidGlob, pattern)
+			}
+
+			i++
+
+			b.WriteString(regexp.QuoteMeta(string(runes[i])))
+		case '*':
+			// handle the `**` wildcard
+			if i+1 < len(runes) && runes[i+1] == '*' {
+				i++
+
+				// handle `**/` at the start of a path segment to match zero or more directories
+				if (i == 1 || runes[i-2] == '/') && i+1 < len(runes) && runes[i+1] == '/' {
+					i++
+
+					b.WriteString("(?:.*/)?")
+
+					continue
+				}
+
+				b.WriteString(".*")
+
+				continue
+			}
+
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			// find the end of the character class
+			end := i + 1
+
+			// handle a negated character class
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+
+			// handle a closing bracket as the first character in the class
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+
+			if end >= len(runes) {
+				return "", fmt.Errorf("%w %s: unterminated character class", ErrInvalidGlob, pattern)
+			}
+
+			class := runes[i+1 : end]
+
+			b.WriteString("[")
+
+			if class[0] == '!' || class[0] == '^' {
+				b.WriteString("^")
+
+				class = class[1:]
+			}
+
+			for _, c := range class {
+				// escape characters with a special meaning in a regexp class
+				if c == '\\' || c == '[' || c == ']' || c == '^' {
+					b.WriteRune('\\')
+				}
+
+				b.WriteRune(c)
+			}
+
+			b.WriteString("]")
+
+			i = end
+		case '{':
+			braces++
+
+			b.WriteString("(?:")
+		case ',':
+			// handle a separator between alternatives
+			if braces > 0 {
+				b.WriteString("|")
+
+				continue
+			}
+
+			b.WriteString(",")
+		case '}':
+			// handle the end of the alternatives
+			if braces > 0 {
+				braces--
+
+				b.WriteString(")")
+
+				continue
+			}
+
+			b.WriteString(regexp.QuoteMeta("}"))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	// verify every brace was closed
+	if braces > 0 {
+		return "", fmt.Errorf("%w %s: unterminated brace expansion", ErrInvalidGlob, pattern)
+	}
+
+	b.WriteString("$")
+
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPipeline_MatchGlob(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "main", value: "main", want: true},
+		{pattern: "release/*", value: "release/v1", want: true},
+		{pattern: "release/*", value: "release/v1/hotfix", want: false},
+		{pattern: "src/**/*.go", value: "src/main.go", want: true},
+		{pattern: "src/**/*.go", value: "src/a/b/main.go", want: true},
+		{pattern: "src/**/*.go", value: "src/a/b/main.js", want: false},
+		{pattern: "src/**", value: "src/a/b/main.go", want: true},
+		{pattern: "**/*.md", value: "README.md", want: true},
+		{pattern: "**/*.md", value: "docs/api/index.md", want: true},
+		{pattern: "docs/**/index.md", value: "docs/index.md", want: true},
+		{pattern: "*.{yml,yaml}", value: ".vela.yaml", want: true},
+		{pattern: "*.{yml,yaml}", value: ".vela.json", want: false},
+		{pattern: "{api,web}/{cmd,pkg}/*", value: "web/pkg/server.go", want: true},
+		{pattern: "v[0-9].*", value: "v1.2", want: true},
+		{pattern: "v[!0-9].*", value: "v1.2", want: false},
+		{pattern: "v[!0-9].*", value: "vx.2", want: true},
+		{pattern: "file?.txt", value: "file1.txt", want: true},
+		{pattern: "file?.txt", value: "file/.txt", want: false},
+		{pattern: `\*.txt`, value: "*.txt", want: true},
+		{pattern: `\*.txt`, value: "a.txt", want: false},
+		{pattern: "a+b(c).txt", value: "a+b(c).txt", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := MatchGlob(test.pattern, test.value)
+		if err != nil {
+			t.Errorf("MatchGlob for %s returned err: %v", test.pattern, err)
+		}
+
+		if got != test.want {
+			t.Errorf("MatchGlob for %s against %s is %v, want %v", test.pattern, test.value, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_MatchGlob_Invalid(t *testing.T) {
+	// setup tests
+	tests := []string{
+		"src/[abc",
+		"*.{yml,yaml",
+		`trailing\`,
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := MatchGlob(test, "value")
+		if !errors.Is(err, ErrInvalidGlob) {
+			t.Errorf("MatchGlob for %s should have returned ErrInvalidGlob, got %v", test, err)
+		}
+	}
+}
+
+func TestPipeline_Ruletype_MatchMultiple_Glob(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		rule Ruletype
+		data []string
+		want bool
+	}{
+		{
+			rule: []string{"src/**/*.go", "!src/**/*_test.go"},
+			data: []string{"src/pkg/server.go"},
+			want: true,
+		},
+		{
+			rule: []string{"src/**/*.go", "!src/**/*_test.go"},
+			data: []string{"src/pkg/server_test.go", "README.md"},
+			want: false,
+		},
+		{
+			rule: []string{"src/**/*.go", "!src/**/*_test.go"},
+			data: []string{"src/pkg/server_test.go", "src/pkg/server.go"},
+			want: true,
+		},
+		{
+			rule: []string{"!docs/**"},
+			data: []string{"docs/index.md"},
+			want: false,
+		},
+		{
+			rule: []string{"!docs/**"},
+			data: []string{"docs/index.md", "go.mod"},
+			want: true,
+		},
+		{
+			rule: []string{"!docs/**"},
+			data: []string{},
+			want: false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rule.MatchMultiple(test.data, "glob", "and")
+		if err != nil {
+			t.Errorf("MatchMultiple returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("MatchMultiple for %v against %v is %v, want %v", test.rule, test.data, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_Ruletype_MatchSingle_Glob(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		rule Ruletype
+		data string
+		want bool
+	}{
+		{rule: []string{"release/**"}, data: "release/v1/hotfix", want: true},
+		{rule: []string{"release/**", "!release/**/wip"}, data: "release/v1/wip", want: false},
+		{rule: []string{"!main"}, data: "dev", want: true},
+		{rule: []string{"{main,master}"}, data: "master", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rule.MatchSingle(test.data, "glob", "and")
+		if err != nil {
+			t.Errorf("MatchSingle returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("MatchSingle for %v against %s is %v, want %v", test.rule, test.data, got, test.want)
+		}
+	}
+
+	// verify an invalid pattern returns an error
+	_, err := (&Ruletype{"[main"}).MatchSingle("main", "glob", "and")
+	if err == nil {
+		t.Errorf("MatchSingle should have returned err")
+	}
+}
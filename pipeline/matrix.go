@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+)
+
+// matrixReference is the pattern used to capture a reference
+// to a matrix value, like ${matrix.go}, in a container.
+var matrixReference = regexp.MustCompile(`\$?\$\{matrix\.([A-Za-z0-9_.-]+)\}`)
+
+// MatrixEnv returns the name of the environment variable used
+// to inject the value for the provided matrix axis into a
+// container, i.e. `go_version` becomes `VELA_MATRIX_GO_VERSION`.
+//
+// Deprecated: use MatrixEnv from github.com/go-vela/server/compiler/types/pipeline instead.
+func MatrixEnv(axis string) string {
+	key := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, axis)
+
+	return "VELA_MATRIX_" + key
+}
+
+// expandMatrix is a helper function to replace every reference
+// to a matrix value, like ${matrix.go}, with a reference to the
+// environment variable injected for the matrix axis. An escaped
+// reference, like $${matrix.go}, is left unchanged.
+func expandMatrix(body string) string {
+	return matrixReference.ReplaceAllStringFunc(body, func(ref string) string {
+		// skip escaped references
+		if strings.HasPrefix(ref, "$$") {
+			return ref
+		}
+
+		axis := matrixReference.FindStringSubmatch(ref)[1]
+
+		return "${" + MatrixEnv(axis) + "}"
+	})
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import "testing"
+
+func TestPipeline_MatrixEnv(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		axis string
+		want string
+	}{
+		{axis: "go", want: "VELA_MATRIX_GO"},
+		{axis: "go_version", want: "VELA_MATRIX_GO_VERSION"},
+		{axis: "node-version", want: "VELA_MATRIX_NODE_VERSION"},
+		{axis: "os.arch", want: "VELA_MATRIX_OS_ARCH"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := MatrixEnv(test.axis)
+
+		if got != test.want {
+			t.Errorf("MatrixEnv for %s is %s, want %s", test.axis, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"container/list"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// patternCacheSize represents the maximum number of compiled
+// patterns and parsed expressions kept in the cache.
+const patternCacheSize = 4096
+
+// patterns represents the cache for every compiled
+// pattern and parsed expression from a ruleset.
+var patterns = newPatternCache(patternCacheSize)
+
+type (
+	// patternCache represents a bounded, concurrency-safe cache
+	// that evicts the least recently used entry when full.
+	patternCache struct {
+		mu      sync.Mutex
+		size    int
+		order   *list.List
+		entries map[string]*list.Element
+	}
+
+	// patternEntry represents a single entry in the pattern cache.
+	patternEntry struct {
+		key   string
+		value interface{}
+	}
+)
+
+// newPatternCache is a helper function to create
+// a pattern cache holding up to the provided size.
+func newPatternCache(size int) *patternCache {
+	return &patternCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// get returns the value for the provided key from the cache.
+func (c *patternCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	// mark the entry as the most recently used
+	c.order.MoveToFront(e)
+
+	return e.Value.(*patternEntry).value, true
+}
+
+// add stores the value for the provided key in the cache,
+// evicting the least recently used entry when full.
+func (c *patternCache) add(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// update the entry if it already exists
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*patternEntry).value = value
+
+		c.order.MoveToFront(e)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&patternEntry{key: key, value: value})
+
+	// evict the least recently used entry
+	if c.order.Len() > c.size {
+		e := c.order.Back()
+
+		c.order.Remove(e)
+
+		delete(c.entries, e.Value.(*patternEntry).key)
+	}
+}
+
+// len returns the number of entries in the cache.
+func (c *patternCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// MatchRegexp returns true if the value matches the provided regular
+// expression pattern. Patterns are compiled once and cached for later
+// calls, and an invalid pattern returns an error.
+func MatchRegexp(pattern, value string) (bool, error) {
+	re, err := compileRegexp(pattern)
+	if err != nil {
+		return false, err
+	}
+
+	return re.MatchString(value), nil
+}
+
+// compileRegexp is a helper function to return the compiled
+// regular expression for the provided pattern from the cache.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	key := "regexp:" + pattern
+
+	// return the compiled pattern from the cache if it exists
+	if re, ok := patterns.get(key); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error in regex pattern %s: %w", pattern, err)
+	}
+
+	patterns.add(key, re)
+
+	return re, nil
+}
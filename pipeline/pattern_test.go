@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPipeline_patternCache(t *testing.T) {
+	// setup types
+	cache := newPatternCache(2)
+
+	// run test
+	cache.add("a", 1)
+	cache.add("b", 2)
+
+	// mark a as the most recently used entry
+	if v, ok := cache.get("a"); !ok || v != 1 {
+		t.Errorf("get for a is %v, want 1", v)
+	}
+
+	// evict b as the least recently used entry
+	cache.add("c", 3)
+
+	if _, ok := cache.get("b"); ok {
+		t.Errorf("get for b should have been evicted")
+	}
+
+	if _, ok := cache.get("a"); !ok {
+		t.Errorf("get for a should not have been evicted")
+	}
+
+	// update an existing entry
+	cache.add("c", 4)
+
+	if v, _ := cache.get("c"); v != 4 {
+		t.Errorf("get for c is %v, want 4", v)
+	}
+
+	if cache.len() != 2 {
+		t.Errorf("len is %d, want 2", cache.len())
+	}
+}
+
+func TestPipeline_MatchRegexp(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+		failure bool
+	}{
+		{pattern: "^release/v[0-9]+$", value: "release/v12", want: true},
+		{pattern: "^release/v[0-9]+$", value: "release/latest", want: false},
+		{pattern: "release/(", value: "release/v1", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := MatchRegexp(test.pattern, test.value)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("MatchRegexp for %s should have returned err", test.pattern)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("MatchRegexp for %s returned err: %v", test.pattern, err)
+		}
+
+		if got != test.want {
+			t.Errorf("MatchRegexp for %s is %v, want %v", test.pattern, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_patternCache_Concurrent(t *testing.T) {
+	// setup types
+	cache := newPatternCache(16)
+
+	wg := new(sync.WaitGroup)
+
+	// run test
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				key := fmt.Sprintf("%d-%d", i, j%32)
+
+				cache.add(key, j)
+				cache.get(key)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if cache.len() != 16 {
+		t.Errorf("len is %d, want 16", cache.len())
+	}
+}
+
+func TestPipeline_Ruleset_Compile(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ruleset *Ruleset
+		want    string
+	}{
+		{
+			ruleset: &Ruleset{If: Rules{Branch: []string{"^main$"}}, Matcher: "regexp"},
+			want:    "",
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Branch: []string{"["}}, Matcher: "filepath"},
+			want:    "",
+		},
+		{
+			ruleset: &Ruleset{
+				If:      Rules{Branch: []string{"main"}, Tag: []string{"v(1"}},
+				Unless:  Rules{Expression: "branch =="},
+				Matcher: "regexp",
+			},
+			want: "if.tag: error in regex pattern v(1: error parsing regexp: missing closing ): `v(1`\n" +
+				"unless.expression: invalid expression \"branch ==\" at column 10: unexpected end of expression",
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Path: []string{"src/**", "!{docs"}}, Matcher: "glob"},
+			want:    "if.path: invalid glob pattern {docs: unterminated brace expansion",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.ruleset.Compile()
+
+		if len(test.want) == 0 {
+			if err != nil {
+				t.Errorf("Compile returned err: %v", err)
+			}
+
+			continue
+		}
+
+		if err == nil || err.Error() != test.want {
+			t.Errorf("Compile is %v, want %s", err, test.want)
+		}
+	}
+}
+
+func TestPipeline_Build_Compile(t *testing.T) {
+	// setup types
+	b := &Build{
+		Stages: StageSlice{
+			{
+				Name: "test",
+				Steps: ContainerSlice{
+					{Name: "unit", Ruleset: Ruleset{If: Rules{Branch: []string{"("}}, Matcher: "regexp"}},
+				},
+			},
+		},
+		Steps: ContainerSlice{
+			{Name: "lint", Ruleset: Ruleset{If: Rules{Branch: []string{"main"}}, Matcher: "regexp"}},
+			{Name: "build", Ruleset: Ruleset{If: Rules{Event: []string{"push", "*"}}, Matcher: "regexp"}},
+		},
+		Services: ContainerSlice{
+			{Name: "redis", Ruleset: Ruleset{If: Rules{Expression: "branch =="}}},
+		},
+	}
+
+	// run test
+	err := b.Compile()
+	if err == nil {
+		t.Fatalf("Compile should have returned err")
+	}
+
+	for _, want := range []string{
+		"invalid ruleset for step unit in stage test: if.branch: error in regex pattern (",
+		"invalid ruleset for step build: if.event: error in regex pattern *",
+		"invalid ruleset for service redis: if.expression: invalid expression",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Compile is %v, want %s", err, want)
+		}
+	}
+
+	if strings.Contains(err.Error(), "lint") {
+		t.Errorf("Compile is %v, should not contain step lint", err)
+	}
+}
+
+// benchmarkPaths is a helper function to create
+// the provided number of changed file paths.
+func benchmarkPaths(n int) []string {
+	paths := make([]string, 0, n)
+
+	for i := 0; i < n; i++ {
+		paths = append(paths, fmt.Sprintf("services/service-%d/internal/pkg/file_%d.go", i%50, i))
+	}
+
+	return paths
+}
+
+// benchmarkSteps is a helper function to create the provided
+// number of steps with a regexp ruleset for the paths.
+func benchmarkSteps(n int) *ContainerSlice {
+	steps := new(ContainerSlice)
+
+	for i := 0; i < n; i++ {
+		*steps = append(*steps, &Container{
+			Name: fmt.Sprintf("step-%d", i),
+			Ruleset: Ruleset{
+				If: Rules{
+					Branch: []string{"^main$"},
+					Path:   []string{fmt.Sprintf(`^services/service-%d/.*\.go$`, i%50), `^docs/.*\.md$`},
+				},
+				Matcher:  "regexp",
+				Operator: "and",
+			},
+		})
+	}
+
+	return steps
+}
+
+func BenchmarkPipeline_Ruletype_MatchMultiple_Regexp(b *testing.B) {
+	// setup types
+	rule := Ruletype{`^docs/.*\.md$`, `^services/service-49/.*_test\.go$`}
+	paths := benchmarkPaths(2000)
+
+	b.ResetTimer()
+
+	// run benchmark
+	for i := 0; i < b.N; i++ {
+		_, err := rule.MatchMultiple(paths, "regexp", "and")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipeline_Ruletype_MatchMultiple_Regexp_Uncached(b *testing.B) {
+	// setup types
+	rule := Ruletype{`^docs/.*\.md$`, `^services/service-49/.*_test\.go$`}
+	paths := benchmarkPaths(2000)
+
+	b.ResetTimer()
+
+	// run benchmark compiling every pattern for every value
+	for i := 0; i < b.N; i++ {
+		for _, pattern := range rule {
+			for _, path := range paths {
+				_, err := regexp.MatchString(pattern, path)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkPipeline_Ruletype_MatchMultiple_Glob(b *testing.B) {
+	// setup types
+	rule := Ruletype{"docs/**/*.md", "services/service-49/**/*_test.go", "!services/**/vendor/**"}
+	paths := benchmarkPaths(2000)
+
+	b.ResetTimer()
+
+	// run benchmark
+	for i := 0; i < b.N; i++ {
+		_, err := rule.MatchMultiple(paths, "glob", "and")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipeline_ContainerSlice_Purge(b *testing.B) {
+	// setup types
+	steps := benchmarkSteps(200)
+	data := &RuleData{Branch: "main", Event: "push", Path: benchmarkPaths(2000)}
+
+	err := steps.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	// run benchmark
+	for i := 0; i < b.N; i++ {
+		_, err := steps.Purge(data)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipeline_Expression_Eval(b *testing.B) {
+	// setup types
+	rules := &Rules{Expression: "branch == 'main' && (event == 'push' || matches(tag, '^refs/tags/v'))"}
+	data := &RuleData{Branch: "main", Event: "tag", Tag: "refs/tags/v1.0.0"}
+
+	b.ResetTimer()
+
+	// run benchmark
+	for i := 0; i < b.N; i++ {
+		_, err := rules.Match(data, "filepath", "and")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
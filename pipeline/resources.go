@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/go-vela/types/constants"
+)
+
+// ErrInvalidQuantity defines the error type when a resource
+// quantity, like `500m` or `2Gi`, can not be parsed.
+var ErrInvalidQuantity = errors.New("invalid resource quantity")
+
+type (
+	// Resources is the pipeline representation of the compute
+	// resources block for a step or service in a pipeline.
+	//
+	// Deprecated: use Resources from github.com/go-vela/server/compiler/types/pipeline instead.
+	Resources struct {
+		Requests ResourceList `json:"requests,omitempty" yaml:"requests,omitempty"`
+		Limits   ResourceList `json:"limits,omitempty"   yaml:"limits,omitempty"`
+	}
+
+	// ResourceList is the pipeline representation of the amount of
+	// compute resources requested by, or allowed for, a container.
+	// The CPU is measured in millicores and the memory in bytes.
+	//
+	// Deprecated: use ResourceList from github.com/go-vela/server/compiler/types/pipeline instead.
+	ResourceList struct {
+		CPU    int64 `json:"cpu,omitempty"    yaml:"cpu,omitempty"`
+		Memory int64 `json:"memory,omitempty" yaml:"memory,omitempty"`
+	}
+)
+
+// memoryUnits represents the suffixes supported for
+// a memory quantity with the number of bytes for each.
+var memoryUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"Pi", 1 << 50},
+	{"Ei", 1 << 60},
+	{"k", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+	{"P", 1e15},
+	{"E", 1e18},
+}
+
+// Empty returns true if the provided resources are empty.
+func (r *Resources) Empty() bool {
+	// return true if the resources are nil
+	if r == nil {
+		return true
+	}
+
+	// return true if every resources field is empty
+	if r.Requests.Empty() &&
+		r.Limits.Empty() {
+		return true
+	}
+
+	return false
+}
+
+// Validate verifies the requests for the resources
+// do not exceed the limits for the resources.
+func (r *Resources) Validate() error {
+	// return nothing if the resources are empty
+	if r.Empty() {
+		return nil
+	}
+
+	errs := []error{}
+
+	// verify the cpu request does not exceed the cpu limit
+	if r.Limits.CPU > 0 && r.Requests.CPU > r.Limits.CPU {
+		errs = append(errs, fmt.Errorf("cpu request %s exceeds cpu limit %s",
+			FormatCPU(r.Requests.CPU), FormatCPU(r.Limits.CPU)))
+	}
+
+	// verify the memory request does not exceed the memory limit
+	if r.Limits.Memory > 0 && r.Requests.Memory > r.Limits.Memory {
+		errs = append(errs, fmt.Errorf("memory request %s exceeds memory limit %s",
+			FormatMemory(r.Requests.Memory), FormatMemory(r.Limits.Memory)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Sanitize returns the resources converted for the provided
+// runtime driver. Currently, this function supports the
+// following runtimes:
+//
+//   - Docker: CPU requests are removed since Docker only
+//     supports CPU limits. Memory requests are kept and used
+//     as the memory reservation for the container.
+//   - Kubernetes: any request that is not provided defaults
+//     to the limit, matching the behavior of the scheduler.
+func (r *Resources) Sanitize(driver string) *Resources {
+	// return nothing if the resources are empty
+	if r.Empty() {
+		return nil
+	}
+
+	resources := *r
+
+	switch driver {
+	// sanitize resources for Docker
+	case constants.DriverDocker:
+		resources.Requests.CPU = 0
+	// sanitize resources for Kubernetes
+	case constants.DriverKubernetes:
+		if resources.Requests.CPU == 0 {
+			resources.Requests.CPU = resources.Limits.CPU
+		}
+
+		if resources.Requests.Memory == 0 {
+			resources.Requests.Memory = resources.Limits.Memory
+		}
+	}
+
+	// return nothing if no resources remain
+	if resources.Empty() {
+		return nil
+	}
+
+	return &resources
+}
+
+// Empty returns true if the provided resource list is empty.
+func (r *ResourceList) Empty() bool {
+	// return true if the resource list is nil
+	if r == nil {
+		return true
+	}
+
+	return r.CPU == 0 && r.Memory == 0
+}
+
+// NanoCPUs returns the CPU for the resource list in units
+// of 10^-9 CPUs as expected by the Docker runtime.
+func (r *ResourceList) NanoCPUs() int64 {
+	// return no CPUs if the resource list is nil
+	if r == nil {
+		return 0
+	}
+
+	return r.CPU * 1e6
+}
+
+// Quantities returns the resource list as Kubernetes quantity
+// strings keyed by the resource name, i.e. `cpu` and `memory`.
+func (r *ResourceList) Quantities() map[string]string {
+	quantities := make(map[string]string)
+
+	// return no quantities if the resource list is empty
+	if r.Empty() {
+		return quantities
+	}
+
+	if r.CPU > 0 {
+		quantities["cpu"] = FormatCPU(r.CPU)
+	}
+
+	if r.Memory > 0 {
+		quantities["memory"] = FormatMemory(r.Memory)
+	}
+
+	return quantities
+}
+
+// ParseCPU parses the provided CPU quantity, like `500m`,
+// `0.5` or `2`, and returns the number of millicores.
+// Fractions of a millicore are rounded up.
+//
+// Deprecated: use ParseCPU from github.com/go-vela/server/compiler/types/pipeline instead.
+func ParseCPU(quantity string) (int64, error) {
+	value := strings.TrimSpace(quantity)
+	scale := big.NewRat(1000, 1)
+
+	// check if the quantity is provided in millicores
+	if strings.HasSuffix(value, "m") {
+		value = strings.TrimSuffix(value, "m")
+		scale = big.NewRat(1, 1)
+	}
+
+	return parseQuantity(quantity, value, scale)
+}
+
+// ParseMemory parses the provided memory quantity, like `2Gi`,
+// `512M` or `1024`, and returns the number of bytes. Both the
+// binary (Ki, Mi, Gi, ...) and decimal (k, M, G, ...) suffixes
+// are supported. Fractions of a byte are rounded up.
+//
+// Deprecated: use ParseMemory from github.com/go-vela/server/compiler/types/pipeline instead.
+func ParseMemory(quantity string) (int64, error) {
+	value := strings.TrimSpace(quantity)
+	scale := big.NewRat(1, 1)
+
+	// capture the scale for the suffix of the quantity
+	for _, unit := range memoryUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			scale = big.NewRat(unit.bytes, 1)
+
+			break
+		}
+	}
+
+	return parseQuantity(quantity, value, scale)
+}
+
+// FormatCPU returns the provided number of millicores as a
+// quantity string, using whole cores when possible.
+//
+// Deprecated: use FormatCPU from github.com/go-vela/server/compiler/types/pipeline instead.
+func FormatCPU(millicores int64) string {
+	if millicores%1000 == 0 {
+		return fmt.Sprintf("%d", millicores/1000)
+	}
+
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// FormatMemory returns the provided number of bytes as a quantity
+// string, using the largest suffix that represents it exactly.
+//
+// Deprecated: use FormatMemory from github.com/go-vela/server/compiler/types/pipeline instead.
+func FormatMemory(bytes int64) string {
+	suffix, value := "", bytes
+
+	// capture the largest suffix that divides the bytes evenly
+	for _, unit := range memoryUnits {
+		if bytes == 0 || bytes%unit.bytes != 0 {
+			continue
+		}
+
+		if bytes/unit.bytes < value {
+			suffix, value = unit.suffix, bytes/unit.bytes
+		}
+	}
+
+	return fmt.Sprintf("%d%s", value, suffix)
+}
+
+// parseQuantity is a helper function to parse the provided
+// number and multiply it by the provided scale, rounding
+// any fraction up to the next whole number.
+func parseQuantity(quantity, value string, scale *big.Rat) (int64, error) {
+	number, ok := new(big.Rat).SetString(value)
+	if !ok || len(value) == 0 || strings.ContainsAny(value, "/eE") {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidQuantity, quantity)
+	}
+
+	// verify the quantity is not negative
+	if number.Sign() < 0 {
+		return 0, fmt.Errorf("%w: %s must not be negative", ErrInvalidQuantity, quantity)
+	}
+
+	number.Mul(number, scale)
+
+	// round the number up to the next whole number
+	result := new(big.Int).Quo(number.Num(), number.Denom())
+	if !number.IsInt() {
+		result.Add(result, big.NewInt(1))
+	}
+
+	// verify the quantity fits in the result
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("%w: %s is too large", ErrInvalidQuantity, quantity)
+	}
+
+	return result.Int64(), nil
+}
@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/constants"
+)
+
+func TestPipeline_ParseCPU(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		quantity string
+		want     int64
+		failure  bool
+	}{
+		{quantity: "500m", want: 500},
+		{quantity: "2", want: 2000},
+		{quantity: "0.5", want: 500},
+		{quantity: "0.0005", want: 1},
+		{quantity: "1.5m", want: 2},
+		{quantity: "", failure: true},
+		{quantity: "m", failure: true},
+		{quantity: "-1", failure: true},
+		{quantity: "1/2", failure: true},
+		{quantity: "2Gi", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := ParseCPU(test.quantity)
+
+		if test.failure {
+			if !errors.Is(err, ErrInvalidQuantity) {
+				t.Errorf("ParseCPU for %q returned err %v, want %v", test.quantity, err, ErrInvalidQuantity)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseCPU for %q returned err: %v", test.quantity, err)
+		}
+
+		if got != test.want {
+			t.Errorf("ParseCPU for %q is %v, want %v", test.quantity, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_ParseMemory(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		quantity string
+		want     int64
+		failure  bool
+	}{
+		{quantity: "1024", want: 1024},
+		{quantity: "2Gi", want: 2 << 30},
+		{quantity: "512Mi", want: 512 << 20},
+		{quantity: "1.5Ki", want: 1536},
+		{quantity: "128M", want: 128e6},
+		{quantity: "1k", want: 1000},
+		{quantity: "", failure: true},
+		{quantity: "Gi", failure: true},
+		{quantity: "2GB", failure: true},
+		{quantity: "512K", failure: true},
+		{quantity: "16Ei", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := ParseMemory(test.quantity)
+
+		if test.failure {
+			if !errors.Is(err, ErrInvalidQuantity) {
+				t.Errorf("ParseMemory for %q returned err %v, want %v", test.quantity, err, ErrInvalidQuantity)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("ParseMemory for %q returned err: %v", test.quantity, err)
+		}
+
+		if got != test.want {
+			t.Errorf("ParseMemory for %q is %v, want %v", test.quantity, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_FormatCPU(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		millicores int64
+		want       string
+	}{
+		{millicores: 0, want: "0"},
+		{millicores: 500, want: "500m"},
+		{millicores: 2000, want: "2"},
+		{millicores: 1500, want: "1500m"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := FormatCPU(test.millicores)
+
+		if got != test.want {
+			t.Errorf("FormatCPU for %d is %v, want %v", test.millicores, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_FormatMemory(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{bytes: 0, want: "0"},
+		{bytes: 1023, want: "1023"},
+		{bytes: 2 << 30, want: "2Gi"},
+		{bytes: 1536, want: "1536"},
+		{bytes: 128e6, want: "128M"},
+		{bytes: 1024000, want: "1000Ki"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := FormatMemory(test.bytes)
+
+		if got != test.want {
+			t.Errorf("FormatMemory for %d is %v, want %v", test.bytes, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_Resources_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		resources *Resources
+		failure   bool
+	}{
+		{
+			name:      "nil",
+			resources: nil,
+		},
+		{
+			name: "requests within limits",
+			resources: &Resources{
+				Requests: ResourceList{CPU: 500, Memory: 1 << 30},
+				Limits:   ResourceList{CPU: 1000, Memory: 2 << 30},
+			},
+		},
+		{
+			name: "requests without limits",
+			resources: &Resources{
+				Requests: ResourceList{CPU: 500, Memory: 1 << 30},
+			},
+		},
+		{
+			name: "cpu request exceeds limit",
+			resources: &Resources{
+				Requests: ResourceList{CPU: 2000},
+				Limits:   ResourceList{CPU: 1000},
+			},
+			failure: true,
+		},
+		{
+			name: "memory request exceeds limit",
+			resources: &Resources{
+				Requests: ResourceList{Memory: 2 << 30},
+				Limits:   ResourceList{Memory: 1 << 30},
+			},
+			failure: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := test.resources.Validate()
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("Validate for %s should have returned err", test.name)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Validate for %s returned err: %v", test.name, err)
+		}
+	}
+}
+
+func TestPipeline_Resources_Sanitize(t *testing.T) {
+	// setup types
+	resources := &Resources{
+		Requests: ResourceList{CPU: 500},
+		Limits:   ResourceList{CPU: 1000, Memory: 2 << 30},
+	}
+
+	// setup tests
+	tests := []struct {
+		driver    string
+		resources *Resources
+		want      *Resources
+	}{
+		{
+			driver:    constants.DriverDocker,
+			resources: resources,
+			want: &Resources{
+				Limits: ResourceList{CPU: 1000, Memory: 2 << 30},
+			},
+		},
+		{
+			driver:    constants.DriverKubernetes,
+			resources: resources,
+			want: &Resources{
+				Requests: ResourceList{CPU: 500, Memory: 2 << 30},
+				Limits:   ResourceList{CPU: 1000, Memory: 2 << 30},
+			},
+		},
+		{
+			driver:    constants.DriverDocker,
+			resources: &Resources{Requests: ResourceList{CPU: 500}},
+			want:      nil,
+		},
+		{
+			driver:    constants.DriverKubernetes,
+			resources: nil,
+			want:      nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.resources.Sanitize(test.driver)
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Sanitize for %s is %v, want %v", test.driver, got, test.want)
+		}
+	}
+
+	// verify the original resources were not modified
+	if resources.Requests.Memory != 0 || resources.Requests.CPU != 500 {
+		t.Errorf("Sanitize modified the original resources: %v", resources)
+	}
+}
+
+func TestPipeline_ResourceList_Quantities(t *testing.T) {
+	// setup types
+	list := &ResourceList{CPU: 250, Memory: 512 << 20}
+
+	want := map[string]string{
+		"cpu":    "250m",
+		"memory": "512Mi",
+	}
+
+	// run test
+	got := list.Quantities()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Quantities is %v, want %v", got, want)
+	}
+
+	if list.NanoCPUs() != 250000000 {
+		t.Errorf("NanoCPUs is %v, want %v", list.NanoCPUs(), 250000000)
+	}
+}
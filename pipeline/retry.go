@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"time"
+
+	"github.com/go-vela/types/constants"
+)
+
+// RetryBackoff is the pipeline representation of the
+// retry_backoff block for a step or service in a pipeline.
+//
+// Deprecated: use RetryBackoff from github.com/go-vela/server/compiler/types/pipeline instead.
+type RetryBackoff struct {
+	Type  string        `json:"type,omitempty"  yaml:"type,omitempty"`
+	Delay time.Duration `json:"delay,omitempty" yaml:"delay,omitempty"`
+	Max   time.Duration `json:"max,omitempty"   yaml:"max,omitempty"`
+}
+
+// Empty returns true if the provided retry backoff is empty.
+func (r *RetryBackoff) Empty() bool {
+	// return true if the retry backoff is nil
+	if r == nil {
+		return true
+	}
+
+	// return true if every retry backoff field is empty
+	if len(r.Type) == 0 &&
+		r.Delay == 0 &&
+		r.Max == 0 {
+		return true
+	}
+
+	return false
+}
+
+// Duration returns the delay to wait before the provided
+// retry attempt, starting at 1 for the first retry. For the
+// exponential backoff type, the delay doubles for every retry
+// and is capped at the max delay when one is provided.
+func (r *RetryBackoff) Duration(retry int) time.Duration {
+	// return no delay if the retry backoff is empty
+	if r.Empty() || retry < 1 {
+		return 0
+	}
+
+	delay := r.Delay
+
+	// double the delay for every retry after the first one
+	if r.Type == constants.BackoffExponential {
+		for i := 1; i < retry; i++ {
+			// stop doubling once the max delay is reached
+			if r.Max > 0 && delay >= r.Max {
+				break
+			}
+
+			delay *= 2
+		}
+	}
+
+	// cap the delay at the max delay
+	if r.Max > 0 && delay > r.Max {
+		delay = r.Max
+	}
+
+	return delay
+}
+
+// Retry returns true when the container should be retried after the
+// provided attempt, starting at 1 for the first run, exited with the
+// provided exit code. A container is only retried when it failed and
+// has not used all of its retries. When the container should be
+// retried, the delay to wait before the next attempt is returned.
+func (c *Container) Retry(attempt, exitCode int) (bool, time.Duration) {
+	// return false if the container is nil
+	if c == nil {
+		return false, 0
+	}
+
+	// return false if the container was successful
+	if exitCode == 0 {
+		return false, 0
+	}
+
+	// return false if the container has used all of its retries
+	if attempt < 1 || attempt > c.Retries {
+		return false, 0
+	}
+
+	return true, c.RetryBackoff.Duration(attempt)
+}
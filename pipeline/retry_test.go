@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-vela/types/constants"
+)
+
+func TestPipeline_RetryBackoff_Duration(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		backoff *RetryBackoff
+		retry   int
+		want    time.Duration
+	}{
+		{
+			name:    "nil",
+			backoff: nil,
+			retry:   1,
+			want:    0,
+		},
+		{
+			name:    "fixed",
+			backoff: &RetryBackoff{Type: constants.BackoffFixed, Delay: 10 * time.Second},
+			retry:   3,
+			want:    10 * time.Second,
+		},
+		{
+			name:    "exponential first retry",
+			backoff: &RetryBackoff{Type: constants.BackoffExponential, Delay: 10 * time.Second},
+			retry:   1,
+			want:    10 * time.Second,
+		},
+		{
+			name:    "exponential third retry",
+			backoff: &RetryBackoff{Type: constants.BackoffExponential, Delay: 10 * time.Second},
+			retry:   3,
+			want:    40 * time.Second,
+		},
+		{
+			name:    "exponential capped",
+			backoff: &RetryBackoff{Type: constants.BackoffExponential, Delay: 10 * time.Second, Max: 30 * time.Second},
+			retry:   5,
+			want:    30 * time.Second,
+		},
+		{
+			name:    "fixed capped",
+			backoff: &RetryBackoff{Type: constants.BackoffFixed, Delay: time.Minute, Max: 30 * time.Second},
+			retry:   1,
+			want:    30 * time.Second,
+		},
+		{
+			name:    "invalid retry",
+			backoff: &RetryBackoff{Type: constants.BackoffFixed, Delay: 10 * time.Second},
+			retry:   0,
+			want:    0,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.backoff.Duration(test.retry)
+
+		if got != test.want {
+			t.Errorf("Duration for %s is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_Container_Retry(t *testing.T) {
+	// setup types
+	c := &Container{
+		Name:    "test",
+		Retries: 2,
+		RetryBackoff: &RetryBackoff{
+			Type:  constants.BackoffExponential,
+			Delay: 5 * time.Second,
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		container *Container
+		attempt   int
+		exitCode  int
+		want      bool
+		wantDelay time.Duration
+	}{
+		{
+			name:      "nil container",
+			container: nil,
+			attempt:   1,
+			exitCode:  1,
+			want:      false,
+		},
+		{
+			name:      "success",
+			container: c,
+			attempt:   1,
+			exitCode:  0,
+			want:      false,
+		},
+		{
+			name:      "first failure",
+			container: c,
+			attempt:   1,
+			exitCode:  1,
+			want:      true,
+			wantDelay: 5 * time.Second,
+		},
+		{
+			name:      "second failure",
+			container: c,
+			attempt:   2,
+			exitCode:  137,
+			want:      true,
+			wantDelay: 10 * time.Second,
+		},
+		{
+			name:      "retries exhausted",
+			container: c,
+			attempt:   3,
+			exitCode:  1,
+			want:      false,
+		},
+		{
+			name:      "no retries",
+			container: &Container{Name: "test"},
+			attempt:   1,
+			exitCode:  1,
+			want:      false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, delay := test.container.Retry(test.attempt, test.exitCode)
+
+		if got != test.want {
+			t.Errorf("Retry for %s is %v, want %v", test.name, got, test.want)
+		}
+
+		if delay != test.wantDelay {
+			t.Errorf("Retry delay for %s is %v, want %v", test.name, delay, test.wantDelay)
+		}
+	}
+}
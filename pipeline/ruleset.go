@@ -3,14 +3,20 @@
 package pipeline
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
-	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-vela/types/constants"
 )
 
+// ErrInvalidThreshold defines the error type when a
+// threshold for the changed files ruletype is invalid.
+var ErrInvalidThreshold = errors.New("invalid changed files threshold")
+
 type (
 	// Ruleset is the pipeline representation of
 	// a ruleset block for a step in a pipeline.
@@ -29,17 +35,24 @@ type (
 	//
 	// Deprecated: use Rules from github.com/go-vela/server/compiler/types/pipeline instead.
 	Rules struct {
-		Branch   Ruletype `json:"branch,omitempty"   yaml:"branch,omitempty"`
-		Comment  Ruletype `json:"comment,omitempty"  yaml:"comment,omitempty"`
-		Event    Ruletype `json:"event,omitempty"    yaml:"event,omitempty"`
-		Path     Ruletype `json:"path,omitempty"     yaml:"path,omitempty"`
-		Repo     Ruletype `json:"repo,omitempty"     yaml:"repo,omitempty"`
-		Status   Ruletype `json:"status,omitempty"   yaml:"status,omitempty"`
-		Tag      Ruletype `json:"tag,omitempty"      yaml:"tag,omitempty"`
-		Target   Ruletype `json:"target,omitempty"   yaml:"target,omitempty"`
-		Label    Ruletype `json:"label,omitempty"    yaml:"label,omitempty"`
-		Instance Ruletype `json:"instance,omitempty" yaml:"instance,omitempty"`
-		Parallel bool     `json:"-"                  yaml:"-"`
+		Branch       Ruletype            `json:"branch,omitempty"        yaml:"branch,omitempty"`
+		Comment      Ruletype            `json:"comment,omitempty"       yaml:"comment,omitempty"`
+		Event        Ruletype            `json:"event,omitempty"         yaml:"event,omitempty"`
+		Path         Ruletype            `json:"path,omitempty"          yaml:"path,omitempty"`
+		Repo         Ruletype            `json:"repo,omitempty"          yaml:"repo,omitempty"`
+		Status       Ruletype            `json:"status,omitempty"        yaml:"status,omitempty"`
+		Tag          Ruletype            `json:"tag,omitempty"           yaml:"tag,omitempty"`
+		Target       Ruletype            `json:"target,omitempty"        yaml:"target,omitempty"`
+		Label        Ruletype            `json:"label,omitempty"         yaml:"label,omitempty"`
+		Instance     Ruletype            `json:"instance,omitempty"      yaml:"instance,omitempty"`
+		Message      Ruletype            `json:"message,omitempty"       yaml:"message,omitempty"`
+		Author       Ruletype            `json:"author,omitempty"        yaml:"author,omitempty"`
+		Sender       Ruletype            `json:"sender,omitempty"        yaml:"sender,omitempty"`
+		BaseRef      Ruletype            `json:"base_ref,omitempty"      yaml:"base_ref,omitempty"`
+		ChangedFiles Ruletype            `json:"changed_files,omitempty" yaml:"changed_files,omitempty"`
+		Env          map[string]Ruletype `json:"env,omitempty"           yaml:"env,omitempty"`
+		Expression   string              `json:"expression,omitempty"    yaml:"expression,omitempty"`
+		Parallel     bool                `json:"-"                       yaml:"-"`
 	}
 
 	// Ruletype is the pipeline representation of an element
@@ -53,17 +66,23 @@ type (
 	//
 	// Deprecated: use RuleData from github.com/go-vela/server/compiler/types/pipeline instead.
 	RuleData struct {
-		Branch   string   `json:"branch,omitempty"   yaml:"branch,omitempty"`
-		Comment  string   `json:"comment,omitempty"  yaml:"comment,omitempty"`
-		Event    string   `json:"event,omitempty"    yaml:"event,omitempty"`
-		Path     []string `json:"path,omitempty"     yaml:"path,omitempty"`
-		Repo     string   `json:"repo,omitempty"     yaml:"repo,omitempty"`
-		Status   string   `json:"status,omitempty"   yaml:"status,omitempty"`
-		Tag      string   `json:"tag,omitempty"      yaml:"tag,omitempty"`
-		Target   string   `json:"target,omitempty"   yaml:"target,omitempty"`
-		Label    []string `json:"label,omitempty"    yaml:"label,omitempty"`
-		Instance string   `json:"instance,omitempty" yaml:"instance,omitempty"`
-		Parallel bool     `json:"-"                  yaml:"-"`
+		Branch       string            `json:"branch,omitempty"        yaml:"branch,omitempty"`
+		Comment      string            `json:"comment,omitempty"       yaml:"comment,omitempty"`
+		Event        string            `json:"event,omitempty"         yaml:"event,omitempty"`
+		Path         []string          `json:"path,omitempty"          yaml:"path,omitempty"`
+		Repo         string            `json:"repo,omitempty"          yaml:"repo,omitempty"`
+		Status       string            `json:"status,omitempty"        yaml:"status,omitempty"`
+		Tag          string            `json:"tag,omitempty"           yaml:"tag,omitempty"`
+		Target       string            `json:"target,omitempty"        yaml:"target,omitempty"`
+		Label        []string          `json:"label,omitempty"         yaml:"label,omitempty"`
+		Instance     string            `json:"instance,omitempty"      yaml:"instance,omitempty"`
+		Message      string            `json:"message,omitempty"       yaml:"message,omitempty"`
+		Author       string            `json:"author,omitempty"        yaml:"author,omitempty"`
+		Sender       string            `json:"sender,omitempty"        yaml:"sender,omitempty"`
+		BaseRef      string            `json:"base_ref,omitempty"      yaml:"base_ref,omitempty"`
+		ChangedFiles int               `json:"changed_files,omitempty" yaml:"changed_files,omitempty"`
+		Env          map[string]string `json:"env,omitempty"           yaml:"env,omitempty"`
+		Parallel     bool              `json:"-"                       yaml:"-"`
 	}
 )
 
@@ -73,36 +92,186 @@ type (
 // true. When both the provided if and unless rules are empty,
 // the function also returns true.
 func (r *Ruleset) Match(from *RuleData) (bool, error) {
+	match, _, err := r.MatchTrace(from)
+
+	return match, err
+}
+
+// MatchTrace returns the same result as Match along with
+// a trace explaining how the result was determined.
+func (r *Ruleset) MatchTrace(from *RuleData) (bool, *RulesetTrace, error) {
+	trace := &RulesetTrace{
+		Matcher:  r.Matcher,
+		Operator: r.Operator,
+	}
+
 	// return true when the if and unless rules are empty
 	if r.If.Empty() && r.Unless.Empty() {
-		return true, nil
+		trace.Result = true
+		trace.Reason = "no if or unless rules provided"
+
+		return true, trace, nil
 	}
 
 	// return false when the unless rules are not empty and match
 	if !r.Unless.Empty() {
-		match, err := r.Unless.Match(from, r.Matcher, r.Operator)
+		match, unless, err := r.Unless.MatchTrace(from, r.Matcher, r.Operator)
+
+		trace.Unless = unless
+
 		if err != nil {
-			return false, err
+			return false, trace, err
 		}
 
 		if match {
-			return false, nil
+			trace.Reason = "unless rules matched"
+
+			return false, trace, nil
 		}
 	}
 
 	// return true when the if rules are empty
 	if r.If.Empty() {
-		return true, nil
+		trace.Result = true
+		trace.Reason = "unless rules did not match and no if rules provided"
+
+		return true, trace, nil
 	}
 
 	// return true when the if rules match
-	match, err := r.If.Match(from, r.Matcher, r.Operator)
+	match, rules, err := r.If.MatchTrace(from, r.Matcher, r.Operator)
 
-	return match, err
+	trace.If = rules
+	trace.Result = match
+
+	if err != nil {
+		return false, trace, err
+	}
+
+	if match {
+		trace.Reason = "if rules matched"
+	} else {
+		trace.Reason = "if rules did not match"
+	}
+
+	return match, trace, nil
+}
+
+// Compile verifies and caches every pattern and expression
+// from the ruleset so they are not compiled while matching.
+// An error is returned for every invalid pattern or expression.
+func (r *Ruleset) Compile() error {
+	return errors.Join(
+		r.If.compile("if", r.Matcher),
+		r.Unless.compile("unless", r.Matcher),
+	)
+}
+
+// compile is a helper function to verify and cache every pattern and
+// expression from the rules for the provided matcher.
+func (r *Rules) compile(name, matcher string) error {
+	errs := []error{}
+
+	// verify and cache the expression
+	if len(r.Expression) > 0 {
+		_, err := ParseExpression(r.Expression)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.expression: %w", name, err))
+		}
+	}
+
+	// verify every threshold for the changed files
+	for _, threshold := range r.ChangedFiles {
+		_, _, err := parseThreshold(threshold)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.changed_files: %w", name, err))
+		}
+	}
+
+	// capture every ruletype with patterns to compile
+	ruletypes := map[string]Ruletype{
+		"author":   r.Author,
+		"base_ref": r.BaseRef,
+		"branch":   r.Branch,
+		"comment":  r.Comment,
+		"event":    r.Event,
+		"instance": r.Instance,
+		"label":    r.Label,
+		"message":  r.Message,
+		"path":     r.Path,
+		"repo":     r.Repo,
+		"sender":   r.Sender,
+		"status":   r.Status,
+		"tag":      r.Tag,
+		"target":   r.Target,
+	}
+
+	// iterate through each ruletype in a consistent order
+	for _, ruletype := range []string{"author", "base_ref", "branch", "comment", "event", "instance", "label", "message", "path", "repo", "sender", "status", "tag", "target"} {
+		for _, pattern := range ruletypes[ruletype] {
+			var err error
+
+			// compile the pattern based off the matcher provided
+			switch matcher {
+			case constants.MatcherRegex, "regex":
+				_, err = compileRegexp(pattern)
+			case constants.MatcherGlob:
+				_, err = compileGlob(strings.TrimPrefix(pattern, "!"))
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", name, ruletype, err))
+			}
+		}
+	}
+
+	// iterate through each environment variable ruletype
+	for _, env := range r.envNames() {
+		for _, pattern := range r.Env[env] {
+			var err error
+
+			// compile the pattern based off the matcher provided
+			switch matcher {
+			case constants.MatcherRegex, "regex":
+				_, err = compileRegexp(pattern)
+			case constants.MatcherGlob:
+				_, err = compileGlob(strings.TrimPrefix(pattern, "!"))
+			}
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.env.%s: %w", name, env, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// envNames is a helper function to return the name of
+// every environment variable ruletype in sorted order.
+func (r *Rules) envNames() []string {
+	names := []string{}
+
+	for name := range r.Env {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
-// NoStatus returns true if the status field is empty.
+// NoStatus returns true if the status field is empty
+// and the expression does not reference the status.
 func (r *Rules) NoStatus() bool {
+	// return false if the expression references the status
+	if len(r.Expression) > 0 {
+		expr, err := ParseExpression(r.Expression)
+		if err == nil && expr.References("status") {
+			return false
+		}
+	}
+
 	// return true if every ruletype is empty
 	return len(r.Status) == 0
 }
@@ -119,7 +288,14 @@ func (r *Rules) Empty() bool {
 		len(r.Tag) == 0 &&
 		len(r.Target) == 0 &&
 		len(r.Label) == 0 &&
-		len(r.Instance) == 0 {
+		len(r.Instance) == 0 &&
+		len(r.Message) == 0 &&
+		len(r.Author) == 0 &&
+		len(r.Sender) == 0 &&
+		len(r.BaseRef) == 0 &&
+		len(r.ChangedFiles) == 0 &&
+		len(r.Env) == 0 &&
+		len(r.Expression) == 0 {
 		return true
 	}
 
@@ -133,69 +309,171 @@ func (r *Rules) Empty() bool {
 // ruletypes from the rules match the provided ruledata. For
 // both operators, when none of the ruletypes from the rules
 // match the provided ruledata, the function returns false.
+//
+// Every environment variable from the env ruletype is matched
+// as its own ruletype against the environment from the ruledata.
+//
+// When an expression is provided for the rules, the result of
+// the expression is combined with the result of the ruletypes
+// using the provided operator. When no ruletypes are provided,
+// only the result of the expression is returned.
 func (r *Rules) Match(from *RuleData, matcher, op string) (bool, error) {
-	status := true
+	match, _, err := r.MatchTrace(from, matcher, op)
 
-	var err error
+	return match, err
+}
 
-	if len(from.Status) != 0 {
-		status, err = r.Status.MatchSingle(from.Status, matcher, op)
-		if err != nil {
-			return false, err
-		}
+// MatchTrace returns the same result as Match along with
+// a trace explaining how the result was determined.
+func (r *Rules) MatchTrace(from *RuleData, matcher, op string) (bool, *RulesTrace, error) {
+	trace := &RulesTrace{
+		Operator:   op,
+		Expression: r.Expression,
 	}
 
-	matchBranch, err := r.Branch.MatchSingle(from.Branch, matcher, op)
-	if err != nil {
-		return false, err
+	// return the result of the ruletypes when no expression is provided
+	if len(r.Expression) == 0 {
+		match, err := r.match(from, matcher, op, trace)
+
+		trace.Result = match
+
+		return match, trace, err
 	}
 
-	matchComment, err := r.Comment.MatchSingle(from.Comment, matcher, op)
+	expr, err := ParseExpression(r.Expression)
 	if err != nil {
-		return false, err
+		return false, trace, err
 	}
 
-	matchEvent, err := r.Event.MatchSingle(from.Event, matcher, op)
+	matchExpression, err := expr.Eval(from)
 	if err != nil {
-		return false, err
+		return false, trace, err
 	}
 
-	matchPath, err := r.Path.MatchMultiple(from.Path, matcher, op)
-	if err != nil {
-		return false, err
+	trace.ExpressionResult = matchExpression
+
+	// capture the ruletypes without the expression
+	rules := *r
+	rules.Expression = ""
+
+	// return the result of the expression when no ruletypes are provided
+	if rules.Empty() {
+		trace.Result = matchExpression
+
+		return matchExpression, trace, nil
 	}
 
-	matchRepo, err := r.Repo.MatchSingle(from.Repo, matcher, op)
+	matchRules, err := rules.match(from, matcher, op, trace)
 	if err != nil {
-		return false, err
+		return false, trace, err
 	}
 
-	matchTag, err := r.Tag.MatchSingle(from.Tag, matcher, op)
-	if err != nil {
-		return false, err
+	switch op {
+	case constants.OperatorOr:
+		trace.Result = matchExpression || matchRules
+	default:
+		trace.Result = matchExpression && matchRules
 	}
 
-	matchTarget, err := r.Target.MatchSingle(from.Target, matcher, op)
-	if err != nil {
-		return false, err
+	return trace.Result, trace, nil
+}
+
+// match is a helper function to return the result of
+// matching the ruletypes against the provided ruledata.
+// Every ruletype that affects the result is captured in
+// the provided trace.
+func (r *Rules) match(from *RuleData, matcher, op string, trace *RulesTrace) (bool, error) {
+	// treat the status as a match when the ruledata has no status
+	if len(from.Status) == 0 {
+		// only capture the status when it affects the result
+		if len(r.Status) > 0 || strings.EqualFold(op, constants.OperatorOr) {
+			trace.Rules = append(trace.Rules, &RuleTrace{
+				Type:     "status",
+				Patterns: r.Status,
+				Result:   true,
+				Reason:   "no status provided",
+			})
+		}
 	}
 
-	matchLabel, err := r.Label.MatchMultiple(from.Label, matcher, op)
-	if err != nil {
-		return false, err
+	// ruletypeData represents a ruletype with the ruledata to match against
+	type ruletypeData struct {
+		name     string
+		ruletype Ruletype
+		data     []string
 	}
 
-	matchInstance, err := r.Instance.MatchSingle(from.Instance, matcher, op)
-	if err != nil {
-		return false, err
+	// capture every ruletype with the ruledata to match against
+	ruletypes := []ruletypeData{
+		{name: "status", ruletype: r.Status, data: []string{from.Status}},
+		{name: "branch", ruletype: r.Branch, data: []string{from.Branch}},
+		{name: "comment", ruletype: r.Comment, data: []string{from.Comment}},
+		{name: "event", ruletype: r.Event, data: []string{from.Event}},
+		{name: "path", ruletype: r.Path, data: from.Path},
+		{name: "repo", ruletype: r.Repo, data: []string{from.Repo}},
+		{name: "tag", ruletype: r.Tag, data: []string{from.Tag}},
+		{name: "target", ruletype: r.Target, data: []string{from.Target}},
+		{name: "label", ruletype: r.Label, data: from.Label},
+		{name: "instance", ruletype: r.Instance, data: []string{from.Instance}},
+		{name: "message", ruletype: r.Message, data: []string{from.Message}},
+		{name: "author", ruletype: r.Author, data: []string{from.Author}},
+		{name: "sender", ruletype: r.Sender, data: []string{from.Sender}},
+		{name: "base_ref", ruletype: r.BaseRef, data: []string{from.BaseRef}},
+		{name: "changed_files", ruletype: r.ChangedFiles, data: []string{strconv.Itoa(from.ChangedFiles)}},
 	}
 
-	switch op {
-	case constants.OperatorOr:
-		return (matchBranch || matchComment || matchEvent || matchPath || matchRepo || matchTag || matchTarget || matchLabel || matchInstance || status), nil
-	default:
-		return (matchBranch && matchComment && matchEvent && matchPath && matchRepo && matchTag && matchTarget && matchLabel && matchInstance && status), nil
+	// capture every environment variable ruletype in a consistent order
+	for _, name := range r.envNames() {
+		ruletypes = append(ruletypes, ruletypeData{name: "env." + name, ruletype: r.Env[name], data: []string{from.Env[name]}})
 	}
+
+	// assume every ruletype matches for the `and` operator
+	// and none of the ruletypes match for the `or` operator
+	result := !strings.EqualFold(op, constants.OperatorOr)
+
+	// iterate through each ruletype
+	for _, rt := range ruletypes {
+		// treat the status as a match when the ruledata has no status
+		match := true
+
+		if rt.name != "status" || len(from.Status) > 0 {
+			var (
+				pattern string
+				err     error
+			)
+
+			// compare the number of changed files against the thresholds
+			if rt.name == "changed_files" {
+				match, pattern, err = rt.ruletype.findCount(from.ChangedFiles, op)
+			} else {
+				match, pattern, err = rt.ruletype.find(rt.data, matcher, op)
+			}
+
+			if err != nil {
+				return false, err
+			}
+
+			// only capture the ruletype when it is provided
+			if len(rt.ruletype) > 0 {
+				trace.Rules = append(trace.Rules, &RuleTrace{
+					Type:     rt.name,
+					Patterns: rt.ruletype,
+					Data:     rt.data,
+					Matched:  pattern,
+					Result:   match,
+				})
+			}
+		}
+
+		switch op {
+		case constants.OperatorOr:
+			result = result || match
+		default:
+			result = result && match
+		}
+	}
+
+	return result, nil
 }
 
 // MatchSingle returns true when the provided ruletype
@@ -203,35 +481,110 @@ func (r *Rules) Match(from *RuleData, matcher, op string) (bool, error) {
 // ruletype is empty, the function returns true for
 // the `and` operator and false for the `or` operator.
 func (r *Ruletype) MatchSingle(data, matcher, logic string) (bool, error) {
+	match, _, err := r.find([]string{data}, matcher, logic)
+
+	return match, err
+}
+
+// MatchMultiple returns true when the provided ruletype
+// matches the provided ruledata. When the provided
+// ruletype is empty, the function returns true for
+// the `and` operator and false for the `or` operator.
+func (r *Ruletype) MatchMultiple(data []string, matcher, logic string) (bool, error) {
+	match, _, err := r.find(data, matcher, logic)
+
+	return match, err
+}
+
+// MatchCount returns true when the provided count matches
+// any of the thresholds from the ruletype, i.e. `<10`, `>=5`
+// or `0`. When the provided ruletype is empty, the function
+// returns true for the `and` operator and false for the `or`
+// operator.
+func (r *Ruletype) MatchCount(count int, logic string) (bool, error) {
+	match, _, err := r.findCount(count, logic)
+
+	return match, err
+}
+
+// findCount is a helper function to return true, with the
+// matching threshold, when a threshold from the ruletype
+// matches the provided count.
+func (r *Ruletype) findCount(count int, logic string) (bool, string, error) {
 	// return true for `and`, false for `or` if an empty ruletype is provided
 	if len(*r) == 0 {
-		return strings.EqualFold(logic, constants.OperatorAnd), nil
+		return strings.EqualFold(logic, constants.OperatorAnd), "", nil
 	}
 
-	// iterate through each pattern in the ruletype
-	for _, pattern := range *r {
-		match, err := match(data, matcher, pattern)
+	// iterate through each threshold in the ruletype
+	for _, threshold := range *r {
+		op, value, err := parseThreshold(threshold)
 		if err != nil {
-			return false, err
+			return false, "", err
+		}
+
+		var match bool
+
+		switch op {
+		case "<":
+			match = count < value
+		case "<=":
+			match = count <= value
+		case ">":
+			match = count > value
+		case ">=":
+			match = count >= value
+		case "!=":
+			match = count != value
+		default:
+			match = count == value
 		}
 
 		if match {
-			return true, nil
+			return true, threshold, nil
 		}
 	}
 
 	// return false if no match is found
-	return false, nil
+	return false, "", nil
 }
 
-// MatchMultiple returns true when the provided ruletype
-// matches the provided ruledata. When the provided
-// ruletype is empty, the function returns true for
-// the `and` operator and false for the `or` operator.
-func (r *Ruletype) MatchMultiple(data []string, matcher, logic string) (bool, error) {
+// parseThreshold is a helper function to return the comparison
+// operator and the value for the provided threshold.
+func parseThreshold(threshold string) (string, int, error) {
+	t := strings.TrimSpace(threshold)
+	op := "=="
+
+	// capture the comparison operator for the threshold
+	for _, prefix := range []string{"<=", ">=", "==", "!=", "<", ">"} {
+		if strings.HasPrefix(t, prefix) {
+			op = prefix
+			t = strings.TrimSpace(strings.TrimPrefix(t, prefix))
+
+			break
+		}
+	}
+
+	value, err := strconv.Atoi(t)
+	if err != nil || value < 0 {
+		return "", 0, fmt.Errorf("%w: %s", ErrInvalidThreshold, threshold)
+	}
+
+	return op, value, nil
+}
+
+// find is a helper function to return true, with the
+// matching pattern, when a pattern from the ruletype
+// matches any of the provided ruledata values.
+func (r *Ruletype) find(data []string, matcher, logic string) (bool, string, error) {
 	// return true for `and`, false for `or` if an empty ruletype is provided
 	if len(*r) == 0 {
-		return strings.EqualFold(logic, constants.OperatorAnd), nil
+		return strings.EqualFold(logic, constants.OperatorAnd), "", nil
+	}
+
+	// handle the exclusions for the glob matcher
+	if matcher == constants.MatcherGlob {
+		return r.findGlob(data)
 	}
 
 	// iterate through each pattern in the ruletype
@@ -239,17 +592,84 @@ func (r *Ruletype) MatchMultiple(data []string, matcher, logic string) (bool, er
 		for _, value := range data {
 			match, err := match(value, matcher, pattern)
 			if err != nil {
-				return false, err
+				return false, "", err
 			}
 
 			if match {
-				return true, nil
+				return true, pattern, nil
 			}
 		}
 	}
 
 	// return false if no match is found
-	return false, nil
+	return false, "", nil
+}
+
+// findGlob is a helper function to return true, with the matching
+// pattern, when a value from the provided ruledata matches a glob
+// pattern from the ruletype and does not match any of the patterns
+// prefixed with `!`. When the ruletype only contains exclusions,
+// every value not matching an exclusion is a match.
+func (r *Ruletype) findGlob(data []string) (bool, string, error) {
+	include := []string{}
+	exclude := []string{}
+
+	// split the patterns into inclusions and exclusions
+	for _, pattern := range *r {
+		if strings.HasPrefix(pattern, "!") {
+			exclude = append(exclude, strings.TrimPrefix(pattern, "!"))
+
+			continue
+		}
+
+		include = append(include, pattern)
+	}
+
+	// iterate through each value in the ruledata
+	for _, value := range data {
+		excluded, _, err := matchGlobs(exclude, value)
+		if err != nil {
+			return false, "", err
+		}
+
+		if excluded {
+			continue
+		}
+
+		// return true if the ruletype only contains exclusions
+		if len(include) == 0 {
+			return true, "", nil
+		}
+
+		match, pattern, err := matchGlobs(include, value)
+		if err != nil {
+			return false, "", err
+		}
+
+		if match {
+			return true, pattern, nil
+		}
+	}
+
+	// return false if no match is found
+	return false, "", nil
+}
+
+// matchGlobs is a helper function to return true, with the matching
+// pattern, when any of the glob patterns match the provided value.
+func matchGlobs(patterns []string, value string) (bool, string, error) {
+	for _, pattern := range patterns {
+		match, err := MatchGlob(pattern, value)
+		if err != nil {
+			return false, "", err
+		}
+
+		if match {
+			return true, pattern, nil
+		}
+	}
+
+	return false, "", nil
 }
 
 // match is a helper function that compares data against a pattern
@@ -259,15 +679,24 @@ func match(data, matcher, pattern string) (bool, error) {
 	// handle the pattern based off the matcher provided
 	switch matcher {
 	case constants.MatcherRegex, "regex":
-		regExpPattern, err := regexp.Compile(pattern)
+		regExpPattern, err := compileRegexp(pattern)
 		if err != nil {
-			return false, fmt.Errorf("error in regex pattern %s: %w", pattern, err)
+			return false, err
 		}
 
 		// return true if the regexp pattern matches the ruledata
 		if regExpPattern.MatchString(data) {
 			return true, nil
 		}
+	case constants.MatcherGlob:
+		// handle a pattern excluding the ruledata
+		if strings.HasPrefix(pattern, "!") {
+			match, err := MatchGlob(strings.TrimPrefix(pattern, "!"), data)
+
+			return !match && err == nil, err
+		}
+
+		return MatchGlob(pattern, data)
 	case constants.MatcherFilepath:
 		fallthrough
 	default:
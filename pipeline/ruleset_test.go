@@ -3,6 +3,7 @@
 package pipeline
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/go-vela/types/constants"
@@ -676,3 +677,173 @@ func TestPipeline_Ruletype_MatchOr(t *testing.T) {
 		}
 	}
 }
+
+func TestPipeline_Ruletype_MatchCount(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		rule    Ruletype
+		count   int
+		logic   string
+		want    bool
+		failure bool
+	}{
+		{rule: []string{}, count: 5, logic: "and", want: true},
+		{rule: []string{}, count: 5, logic: "or", want: false},
+		{rule: []string{"<10"}, count: 5, logic: "and", want: true},
+		{rule: []string{"<10"}, count: 10, logic: "and", want: false},
+		{rule: []string{"<= 10"}, count: 10, logic: "and", want: true},
+		{rule: []string{">100"}, count: 101, logic: "and", want: true},
+		{rule: []string{">=100"}, count: 99, logic: "and", want: false},
+		{rule: []string{"0"}, count: 0, logic: "and", want: true},
+		{rule: []string{"==1"}, count: 2, logic: "and", want: false},
+		{rule: []string{"!=0"}, count: 2, logic: "and", want: true},
+		{rule: []string{"<2", ">50"}, count: 60, logic: "and", want: true},
+		{rule: []string{"lots"}, count: 5, logic: "and", failure: true},
+		{rule: []string{"<-1"}, count: 5, logic: "and", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rule.MatchCount(test.count, test.logic)
+
+		if test.failure {
+			if !errors.Is(err, ErrInvalidThreshold) {
+				t.Errorf("MatchCount for %v should have returned ErrInvalidThreshold, got %v", test.rule, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("MatchCount returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("MatchCount for %v with %d is %v, want %v", test.rule, test.count, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_Rules_Match_Commit(t *testing.T) {
+	// setup types
+	data := &RuleData{
+		Branch:       "main",
+		Event:        "pull_request:opened",
+		Message:      "docs: update the readme",
+		Author:       "dependabot[bot]",
+		Sender:       "octocat",
+		BaseRef:      "main",
+		ChangedFiles: 3,
+	}
+
+	// setup tests
+	tests := []struct {
+		rules    *Rules
+		matcher  string
+		operator string
+		want     bool
+	}{
+		{rules: &Rules{Message: []string{"docs:*"}}, matcher: "filepath", operator: "and", want: true},
+		{rules: &Rules{Message: []string{"^feat"}}, matcher: "regexp", operator: "and", want: false},
+		{rules: &Rules{Author: []string{"*\\[bot\\]"}}, matcher: "filepath", operator: "and", want: true},
+		{rules: &Rules{Author: []string{".*\\[bot\\]$"}, Sender: []string{"octocat"}}, matcher: "regexp", operator: "and", want: true},
+		{rules: &Rules{Sender: []string{"octokitten"}}, matcher: "filepath", operator: "and", want: false},
+		{rules: &Rules{BaseRef: []string{"{main,release/**}"}}, matcher: "glob", operator: "and", want: true},
+		{rules: &Rules{ChangedFiles: []string{"<5"}, Message: []string{"docs:*"}}, matcher: "filepath", operator: "and", want: true},
+		{rules: &Rules{ChangedFiles: []string{">5"}, Message: []string{"docs:*"}}, matcher: "filepath", operator: "and", want: false},
+		{rules: &Rules{ChangedFiles: []string{">5"}, Message: []string{"docs:*"}}, matcher: "filepath", operator: "or", want: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rules.Match(data, test.matcher, test.operator)
+		if err != nil {
+			t.Errorf("Match returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("Match for %v is %v, want %v", test.rules, got, test.want)
+		}
+	}
+
+	// verify an invalid threshold returns an error
+	_, err := (&Rules{ChangedFiles: []string{"some"}}).Match(data, "filepath", "and")
+	if err == nil {
+		t.Errorf("Match should have returned err")
+	}
+}
+
+func TestPipeline_Rules_Match_Env(t *testing.T) {
+	// setup types
+	data := &RuleData{
+		Branch: "main",
+		Env: map[string]string{
+			"DEPLOY_ENABLED":   "true",
+			"VELA_REPO_TOPICS": "cloud,security",
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		rules    *Rules
+		matcher  string
+		operator string
+		want     bool
+	}{
+		{rules: &Rules{Env: map[string]Ruletype{"DEPLOY_ENABLED": {"true"}}}, matcher: "filepath", operator: "and", want: true},
+		{rules: &Rules{Env: map[string]Ruletype{"DEPLOY_ENABLED": {"false"}}}, matcher: "filepath", operator: "and", want: false},
+		{rules: &Rules{Env: map[string]Ruletype{"VELA_REPO_TOPICS": {"(^|,)security(,|$)"}}}, matcher: "regexp", operator: "and", want: true},
+		{rules: &Rules{Env: map[string]Ruletype{"VELA_REPO_TOPICS": {"*cloud*"}}, Branch: []string{"main"}}, matcher: "filepath", operator: "and", want: true},
+		{rules: &Rules{Env: map[string]Ruletype{"MISSING": {"?*"}}, Branch: []string{"main"}}, matcher: "filepath", operator: "and", want: false},
+		{rules: &Rules{Env: map[string]Ruletype{"MISSING": {"?*"}}, Branch: []string{"main"}}, matcher: "filepath", operator: "or", want: true},
+		{rules: &Rules{Env: map[string]Ruletype{"DEPLOY_ENABLED": {"true"}, "MISSING": {"?*"}}}, matcher: "filepath", operator: "and", want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := test.rules.Match(data, test.matcher, test.operator)
+		if err != nil {
+			t.Errorf("Match returned err: %v", err)
+		}
+
+		if got != test.want {
+			t.Errorf("Match for %v is %v, want %v", test.rules.Env, got, test.want)
+		}
+	}
+}
+
+func TestPipeline_ContainerSlice_Purge_Env(t *testing.T) {
+	// setup types
+	steps := &ContainerSlice{
+		{
+			Name:        "deploy",
+			Environment: map[string]string{"DEPLOY_ENABLED": "true"},
+			Ruleset:     Ruleset{If: Rules{Env: map[string]Ruletype{"DEPLOY_ENABLED": {"true"}}}, Operator: "and"},
+		},
+		{
+			Name:        "skip",
+			Environment: map[string]string{"DEPLOY_ENABLED": "false"},
+			Ruleset:     Ruleset{If: Rules{Env: map[string]Ruletype{"DEPLOY_ENABLED": {"true"}}}, Operator: "and"},
+		},
+	}
+
+	// run test
+	got, err := steps.Purge(&RuleData{Branch: "main"})
+	if err != nil {
+		t.Errorf("Purge returned err: %v", err)
+	}
+
+	if len(*got) != 1 || (*got)[0].Name != "deploy" {
+		t.Errorf("Purge is %v, want [deploy]", got)
+	}
+
+	// verify the environment is used on the worker
+	execute, err := (*steps)[1].Execute(&RuleData{Branch: "main", Status: "running"})
+	if err != nil {
+		t.Errorf("Execute returned err: %v", err)
+	}
+
+	if execute {
+		t.Errorf("Execute is %v, want false", execute)
+	}
+}
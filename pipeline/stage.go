@@ -3,6 +3,7 @@
 package pipeline
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/go-vela/types/constants"
@@ -29,6 +30,26 @@ type (
 	}
 )
 
+// Compile verifies and caches every pattern and expression from
+// the ruleset for every step in each stage. An error is returned,
+// with the name of the stage and step, for every invalid ruleset.
+func (s *StageSlice) Compile() error {
+	errs := []error{}
+
+	// iterate through each stage for the pipeline
+	for _, stage := range *s {
+		// iterate through each step for the stage in the pipeline
+		for _, step := range stage.Steps {
+			err := step.Ruleset.Compile()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid ruleset for step %s in stage %s: %w", step.Name, stage.Name, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // Purge removes the steps, from the stages, that have
 // a ruleset that do not match the provided ruledata.
 // If all steps from a stage are removed, then the
@@ -43,7 +64,7 @@ func (s *StageSlice) Purge(r *RuleData) (*StageSlice, error) {
 
 		// iterate through each step for the stage in the pipeline
 		for _, step := range stage.Steps {
-			match, err := step.Ruleset.Match(r)
+			match, err := step.Ruleset.Match(step.ruleData(r))
 			if err != nil {
 				return nil, fmt.Errorf("unable to process ruleset for step %s: %w", step.Name, err)
 			}
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// ExecuteTrace is the pipeline representation of the
+	// explanation for whether a container will execute.
+	//
+	// Deprecated: use ExecuteTrace from github.com/go-vela/server/compiler/types/pipeline instead.
+	ExecuteTrace struct {
+		Result      bool               `json:"result"                yaml:"result"`
+		Reason      string             `json:"reason,omitempty"      yaml:"reason,omitempty"`
+		Status      string             `json:"status,omitempty"      yaml:"status,omitempty"`
+		Ignored     []string           `json:"ignored,omitempty"     yaml:"ignored,omitempty"`
+		Evaluations []*EvaluationTrace `json:"evaluations,omitempty" yaml:"evaluations,omitempty"`
+	}
+
+	// EvaluationTrace is the pipeline representation of the
+	// explanation for a single evaluation of a ruleset while
+	// deciding whether a container will execute.
+	//
+	// Deprecated: use EvaluationTrace from github.com/go-vela/server/compiler/types/pipeline instead.
+	EvaluationTrace struct {
+		Status  string        `json:"status,omitempty"  yaml:"status,omitempty"`
+		Reason  string        `json:"reason,omitempty"  yaml:"reason,omitempty"`
+		Result  bool          `json:"result"            yaml:"result"`
+		Ruleset *RulesetTrace `json:"ruleset,omitempty" yaml:"ruleset,omitempty"`
+	}
+
+	// RulesetTrace is the pipeline representation of the
+	// explanation for matching a ruleset against ruledata.
+	//
+	// Deprecated: use RulesetTrace from github.com/go-vela/server/compiler/types/pipeline instead.
+	RulesetTrace struct {
+		Result   bool        `json:"result"             yaml:"result"`
+		Reason   string      `json:"reason,omitempty"   yaml:"reason,omitempty"`
+		Matcher  string      `json:"matcher,omitempty"  yaml:"matcher,omitempty"`
+		Operator string      `json:"operator,omitempty" yaml:"operator,omitempty"`
+		If       *RulesTrace `json:"if,omitempty"       yaml:"if,omitempty"`
+		Unless   *RulesTrace `json:"unless,omitempty"   yaml:"unless,omitempty"`
+	}
+
+	// RulesTrace is the pipeline representation of the
+	// explanation for matching rules against ruledata.
+	//
+	// Deprecated: use RulesTrace from github.com/go-vela/server/compiler/types/pipeline instead.
+	RulesTrace struct {
+		Result           bool         `json:"result"               yaml:"result"`
+		Operator         string       `json:"operator,omitempty"   yaml:"operator,omitempty"`
+		Rules            []*RuleTrace `json:"rules,omitempty"      yaml:"rules,omitempty"`
+		Expression       string       `json:"expression,omitempty" yaml:"expression,omitempty"`
+		ExpressionResult bool         `json:"expression_result"    yaml:"expression_result"`
+	}
+
+	// RuleTrace is the pipeline representation of the
+	// explanation for matching a ruletype against ruledata.
+	//
+	// Deprecated: use RuleTrace from github.com/go-vela/server/compiler/types/pipeline instead.
+	RuleTrace struct {
+		Type     string   `json:"type"               yaml:"type"`
+		Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+		Data     []string `json:"data,omitempty"     yaml:"data,omitempty"`
+		Matched  string   `json:"matched,omitempty"  yaml:"matched,omitempty"`
+		Result   bool     `json:"result"             yaml:"result"`
+		Reason   string   `json:"reason,omitempty"   yaml:"reason,omitempty"`
+	}
+)
+
+// String implements the Stringer interface for the ExecuteTrace type.
+func (t *ExecuteTrace) String() string {
+	b := new(strings.Builder)
+
+	// capture the outcome for the container
+	if t.Result {
+		fmt.Fprintf(b, "execute: %s\n", t.Reason)
+	} else {
+		fmt.Fprintf(b, "skip: %s\n", t.Reason)
+	}
+
+	// capture the rules ignored by the worker
+	if len(t.Ignored) > 0 {
+		fmt.Fprintf(b, "  ignored: %s\n", strings.Join(t.Ignored, ", "))
+	}
+
+	// iterate through each evaluation of the ruleset
+	for _, evaluation := range t.Evaluations {
+		fmt.Fprintf(b, "  evaluation with status %q (%s): %s\n", evaluation.Status, evaluation.Reason, matchString(evaluation.Result))
+
+		if evaluation.Ruleset != nil {
+			evaluation.Ruleset.write(b, "    ")
+		}
+	}
+
+	return b.String()
+}
+
+// String implements the Stringer interface for the RulesetTrace type.
+func (t *RulesetTrace) String() string {
+	b := new(strings.Builder)
+
+	t.write(b, "")
+
+	return b.String()
+}
+
+// write is a helper function to write the ruleset trace
+// to the provided builder with the provided indentation.
+func (t *RulesetTrace) write(b *strings.Builder, indent string) {
+	fmt.Fprintf(b, "%sruleset (matcher %s, operator %s): %s: %s\n", indent, t.Matcher, t.Operator, matchString(t.Result), t.Reason)
+
+	if t.Unless != nil {
+		t.Unless.write(b, "unless", indent+"  ")
+	}
+
+	if t.If != nil {
+		t.If.write(b, "if", indent+"  ")
+	}
+}
+
+// write is a helper function to write the rules trace
+// to the provided builder with the provided indentation.
+func (t *RulesTrace) write(b *strings.Builder, name, indent string) {
+	fmt.Fprintf(b, "%s%s: %s\n", indent, name, matchString(t.Result))
+
+	// capture the result for the expression
+	if len(t.Expression) > 0 {
+		fmt.Fprintf(b, "%s  expression %q: %t\n", indent, t.Expression, t.ExpressionResult)
+	}
+
+	// iterate through each ruletype
+	for _, rule := range t.Rules {
+		fmt.Fprintf(b, "%s  %s\n", indent, rule)
+	}
+}
+
+// String implements the Stringer interface for the RuleTrace type.
+func (t *RuleTrace) String() string {
+	// capture the reason when provided
+	if len(t.Reason) > 0 {
+		return fmt.Sprintf("%s %v: %s: %s", t.Type, t.Patterns, matchString(t.Result), t.Reason)
+	}
+
+	if t.Result && len(t.Matched) > 0 {
+		return fmt.Sprintf("%s %v against %q: matched %s", t.Type, t.Patterns, t.Data, t.Matched)
+	}
+
+	return fmt.Sprintf("%s %v against %q: %s", t.Type, t.Patterns, t.Data, matchString(t.Result))
+}
+
+// matchString is a helper function to
+// describe the provided match result.
+func matchString(match bool) string {
+	if match {
+		return "matched"
+	}
+
+	return "not matched"
+}
@@ -0,0 +1,279 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pipeline
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPipeline_Ruleset_MatchTrace(t *testing.T) {
+	// setup types
+	ruleset := &Ruleset{
+		If: Rules{
+			Branch: []string{"main", "release/*"},
+			Event:  []string{"push"},
+		},
+		Unless: Rules{
+			Event: []string{"pull_request"},
+		},
+		Matcher:  "filepath",
+		Operator: "and",
+	}
+
+	data := &RuleData{Branch: "release/v1", Event: "push", Repo: "github/octocat"}
+
+	want := &RulesetTrace{
+		Result:   true,
+		Reason:   "if rules matched",
+		Matcher:  "filepath",
+		Operator: "and",
+		If: &RulesTrace{
+			Result:   true,
+			Operator: "and",
+			Rules: []*RuleTrace{
+				{Type: "branch", Patterns: []string{"main", "release/*"}, Data: []string{"release/v1"}, Matched: "release/*", Result: true},
+				{Type: "event", Patterns: []string{"push"}, Data: []string{"push"}, Matched: "push", Result: true},
+			},
+		},
+		Unless: &RulesTrace{
+			Result:   false,
+			Operator: "and",
+			Rules: []*RuleTrace{
+				{Type: "event", Patterns: []string{"pull_request"}, Data: []string{"push"}, Result: false},
+			},
+		},
+	}
+
+	// run test
+	got, trace, err := ruleset.MatchTrace(data)
+	if err != nil {
+		t.Errorf("MatchTrace returned err: %v", err)
+	}
+
+	if !got {
+		t.Errorf("MatchTrace is %v, want true", got)
+	}
+
+	if !reflect.DeepEqual(trace, want) {
+		t.Errorf("MatchTrace is %v, want %v", trace, want)
+	}
+}
+
+func TestPipeline_Ruleset_MatchTrace_Consistent(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ruleset *Ruleset
+		data    *RuleData
+	}{
+		{
+			ruleset: &Ruleset{},
+			data:    &RuleData{Branch: "main"},
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Branch: []string{"main"}, Event: []string{"tag"}}, Operator: "or"},
+			data:    &RuleData{Branch: "dev", Event: "push"},
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Branch: []string{"main"}}, Operator: "or"},
+			data:    &RuleData{Branch: "dev", Event: "push", Status: "success"},
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Status: []string{"failure"}}, Operator: "and"},
+			data:    &RuleData{Branch: "dev", Status: "success"},
+		},
+		{
+			ruleset: &Ruleset{Unless: Rules{Path: []string{"docs/*"}}, Operator: "and"},
+			data:    &RuleData{Path: []string{"docs/index.md"}},
+		},
+		{
+			ruleset: &Ruleset{If: Rules{Expression: "branch == 'main'", Event: []string{"push"}}, Operator: "or"},
+			data:    &RuleData{Branch: "dev", Event: "push"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		want, err := test.ruleset.Match(test.data)
+		if err != nil {
+			t.Errorf("Match returned err: %v", err)
+		}
+
+		got, trace, err := test.ruleset.MatchTrace(test.data)
+		if err != nil {
+			t.Errorf("MatchTrace returned err: %v", err)
+		}
+
+		if got != want || trace.Result != want {
+			t.Errorf("MatchTrace is %v (trace %v), want %v", got, trace.Result, want)
+		}
+	}
+}
+
+func TestPipeline_Rules_MatchTrace_Status(t *testing.T) {
+	// setup types
+	rules := &Rules{Status: []string{"failure"}, Branch: []string{"main"}}
+
+	want := []*RuleTrace{
+		{Type: "status", Patterns: []string{"failure"}, Result: true, Reason: "no status provided"},
+		{Type: "branch", Patterns: []string{"main"}, Data: []string{"main"}, Matched: "main", Result: true},
+	}
+
+	// run test
+	got, trace, err := rules.MatchTrace(&RuleData{Branch: "main"}, "filepath", "and")
+	if err != nil {
+		t.Errorf("MatchTrace returned err: %v", err)
+	}
+
+	if !got {
+		t.Errorf("MatchTrace is %v, want true", got)
+	}
+
+	if !reflect.DeepEqual(trace.Rules, want) {
+		t.Errorf("MatchTrace rules are %v, want %v", trace.Rules, want)
+	}
+}
+
+func TestPipeline_Container_ExecuteTrace(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		container *Container
+		data      *RuleData
+		want      bool
+		reason    string
+		statuses  []string
+		ignored   []string
+	}{
+		{
+			container: &Container{
+				Name:    "notify",
+				Ruleset: Ruleset{If: Rules{Status: []string{"failure"}}, Operator: "and"},
+			},
+			data:     &RuleData{Branch: "main", Status: "success"},
+			want:     false,
+			reason:   "build is successful and the ruleset only runs on failure",
+			statuses: []string{"failure", "success"},
+			ignored:  []string{},
+		},
+		{
+			container: &Container{
+				Name:    "notify",
+				Ruleset: Ruleset{If: Rules{Status: []string{"failure"}}, Operator: "and"},
+			},
+			data:     &RuleData{Branch: "main", Status: "failure"},
+			want:     true,
+			reason:   "build is not successful and the ruleset runs on failure",
+			statuses: []string{"failure", "failure"},
+			ignored:  []string{},
+		},
+		{
+			container: &Container{
+				Name:    "test",
+				Ruleset: Ruleset{If: Rules{Branch: []string{"main"}, Path: []string{"src/*"}}, Operator: "and"},
+			},
+			data:     &RuleData{Branch: "main", Status: "running"},
+			want:     true,
+			reason:   "ruleset matched while the build is running",
+			statuses: []string{"success"},
+			ignored:  []string{"path"},
+		},
+		{
+			container: &Container{
+				Name: "test",
+				Ruleset: Ruleset{
+					If:       Rules{Expression: "contains(path, 'src')", Comment: []string{"run"}},
+					Operator: "and",
+				},
+			},
+			data:     &RuleData{Branch: "main", Status: "success"},
+			want:     true,
+			reason:   "build is successful and no status rules prevent execution",
+			statuses: []string{"failure"},
+			ignored:  []string{"comment", "if expression"},
+		},
+		{
+			container: &Container{
+				Name: "docs",
+				Ruleset: Ruleset{
+					If:       Rules{ChangedFiles: []string{">1000"}, Status: []string{"success"}},
+					Operator: "and",
+				},
+			},
+			data:     &RuleData{Branch: "main", Status: "success"},
+			want:     true,
+			reason:   "build is successful and no status rules prevent execution",
+			statuses: []string{"failure"},
+			ignored:  []string{"changed_files"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, trace, err := test.container.ExecuteTrace(&RuleData{Branch: test.data.Branch, Status: test.data.Status})
+		if err != nil {
+			t.Errorf("ExecuteTrace returned err: %v", err)
+		}
+
+		want, err := test.container.Execute(test.data)
+		if err != nil {
+			t.Errorf("Execute returned err: %v", err)
+		}
+
+		if got != want || got != test.want || trace.Result != test.want {
+			t.Errorf("ExecuteTrace for %s is %v (trace %v), want %v", test.container.Name, got, trace.Result, test.want)
+		}
+
+		if trace.Reason != test.reason {
+			t.Errorf("ExecuteTrace reason is %s, want %s", trace.Reason, test.reason)
+		}
+
+		statuses := []string{}
+		for _, evaluation := range trace.Evaluations {
+			statuses = append(statuses, evaluation.Status)
+		}
+
+		if !reflect.DeepEqual(statuses, test.statuses) {
+			t.Errorf("ExecuteTrace statuses are %v, want %v", statuses, test.statuses)
+		}
+
+		if !reflect.DeepEqual(trace.Ignored, test.ignored) {
+			t.Errorf("ExecuteTrace ignored is %v, want %v", trace.Ignored, test.ignored)
+		}
+	}
+}
+
+func TestPipeline_ExecuteTrace_String(t *testing.T) {
+	// setup types
+	c := &Container{
+		Name:    "notify",
+		Ruleset: Ruleset{If: Rules{Status: []string{"failure"}}, Matcher: "filepath", Operator: "and"},
+	}
+
+	want := `skip: build is successful and the ruleset only runs on failure
+  evaluation with status "failure" (checking for a status failure ruleset): matched
+    ruleset (matcher filepath, operator and): matched: if rules matched
+      if: matched
+        status [failure] against ["failure"]: matched failure
+  evaluation with status "success" (ruleset matches a failure, checking the ruleset with status success): not matched
+    ruleset (matcher filepath, operator and): not matched: if rules did not match
+      if: not matched
+        status [failure] against ["success"]: not matched
+`
+
+	// run test
+	_, trace, err := c.ExecuteTrace(&RuleData{Status: "success"})
+	if err != nil {
+		t.Errorf("ExecuteTrace returned err: %v", err)
+	}
+
+	if trace.String() != want {
+		t.Errorf("String is %s, want %s", trace.String(), want)
+	}
+
+	// verify the trace can be stored as json
+	_, err = json.Marshal(trace)
+	if err != nil {
+		t.Errorf("unable to marshal trace: %v", err)
+	}
+}
@@ -108,6 +108,11 @@ func TestRaw_StringSliceMap_UnmarshalYAML(t *testing.T) {
 			file:    "testdata/map.yml",
 			want:    &StringSliceMap{"foo": "bar"},
 		},
+		{
+			failure: false,
+			file:    "testdata/merge_map.yml",
+			want:    &StringSliceMap{"foo": "bar", "hello": "world"},
+		},
 		{
 			failure: true,
 			file:    "testdata/invalid.yml",
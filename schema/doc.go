@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema provides the JSON schema for Vela pipelines.
+//
+// The schema is generated from the struct tags on the types in
+// the yaml package, including the short forms accepted when
+// unmarshaling a pipeline, and is produced as JSON Schema
+// draft 2020-12. It can be provided to an editor for completion
+// or used to validate pipelines offline.
+//
+// Usage:
+//
+//	data, err := schema.JSON()
+//
+// Deprecated: use github.com/go-vela/server/schema instead.
+package schema
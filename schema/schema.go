@@ -0,0 +1,395 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/invopop/jsonschema"
+	orderedmap "github.com/pb33f/ordered-map/v2"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/raw"
+	"github.com/go-vela/types/yaml"
+)
+
+// Title defines the title for the schema of a Vela pipeline.
+const Title = "Vela Pipeline Configuration"
+
+// minLength represents the minimum length
+// for the name of a secret in the short form.
+var minLength uint64 = 1
+
+// events represents the build events suggested
+// for the event rule type of a ruleset.
+var events = []string{
+	constants.EventComment,
+	constants.EventComment + ":" + constants.ActionCreated,
+	constants.EventComment + ":" + constants.ActionEdited,
+	constants.EventDelete + ":" + constants.ActionBranch,
+	constants.EventDelete + ":" + constants.ActionTag,
+	constants.EventDeploy,
+	constants.EventDeploy + ":" + constants.ActionCreated,
+	constants.EventPull,
+	constants.EventPull + "*",
+	constants.EventPull + ":" + constants.ActionEdited,
+	constants.EventPull + ":" + constants.ActionLabeled,
+	constants.EventPull + ":" + constants.ActionOpened,
+	constants.EventPull + ":" + constants.ActionReopened,
+	constants.EventPull + ":" + constants.ActionSynchronize,
+	constants.EventPull + ":" + constants.ActionUnlabeled,
+	constants.EventPush,
+	constants.EventSchedule,
+	constants.EventTag,
+}
+
+// New returns the JSON schema for a Vela pipeline. The schema
+// is generated from the struct tags on the yaml types and is
+// adjusted for the short forms accepted by the yaml package.
+func New() *jsonschema.Schema {
+	// setup the reflector for schema translation
+	r := &jsonschema.Reflector{
+		ExpandedStruct:             true,
+		RequiredFromJSONSchemaTags: true,
+		FieldNameTag:               "yaml",
+		Mapper:                     mapper,
+	}
+
+	// build the schema with what is provided
+	// by the struct tags and the mapper
+	s := r.Reflect(&yaml.Build{})
+
+	s.Title = Title
+
+	// allow additional keys at the top level
+	// which are commonly used for anchors
+	s.AdditionalProperties = nil
+
+	// describe the extension keys which
+	// are ignored by the compiler
+	s.PatternProperties = map[string]*jsonschema.Schema{
+		"^x-": {
+			Description: "Extension ignored when compiling the pipeline, commonly used to define anchors for reusable blocks.",
+		},
+	}
+
+	// adjust the types that are unmarshaled
+	// from more than one form in a pipeline
+	rules(s)
+	ruleset(s)
+	matrix(s)
+	stages(s)
+	secret(s)
+	secrets(s)
+	pull(s)
+	ulimit(s)
+	volume(s)
+
+	return s
+}
+
+// JSON returns the JSON schema for a Vela pipeline as an indented document.
+func JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(New(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+// mapper is a helper function to provide the schema for
+// the raw types which accept a short form in a pipeline.
+func mapper(t reflect.Type) *jsonschema.Schema {
+	switch t {
+	case reflect.TypeOf(raw.StringSlice{}):
+		// handle string or slice of strings
+		return stringSlice()
+	case reflect.TypeOf(raw.StringSliceMap{}):
+		// handle map of strings or slice of key=value strings
+		return &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				{
+					Type:                 "object",
+					AdditionalProperties: scalar(),
+				},
+				{
+					Type: "array",
+					Items: &jsonschema.Schema{
+						Type:    "string",
+						Pattern: "^[^=]+=",
+					},
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+// rules is a helper function to adjust the Rules definition.
+//
+// Every rule type accepts a string or a slice of strings and
+// the rules may be provided as a string for an expression.
+func rules(s *jsonschema.Schema) {
+	rd, ok := s.Definitions["Rules"]
+	if !ok {
+		return
+	}
+
+	for pair := rd.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		property := pair.Value
+
+		switch pair.Key {
+		case "env":
+			property.AdditionalProperties = stringSlice()
+		case "event":
+			resetStringSlice(property)
+
+			property.Examples = interfaces(events)
+		case "status":
+			resetStringSlice(property, constants.StatusFailure, constants.StatusSuccess)
+		default:
+			resetStringSlice(property)
+		}
+	}
+
+	s.Definitions["Rules"] = &jsonschema.Schema{
+		Description: rd.Description,
+		OneOf: []*jsonschema.Schema{
+			{
+				Type:        "string",
+				Description: "Expression to limit the execution of the container.",
+			},
+			{
+				Type:                 "object",
+				Properties:           rd.Properties,
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+		},
+	}
+}
+
+// ruleset is a helper function to adjust the Ruleset definition.
+//
+// The rules may be provided at the ruleset level, nested within
+// `if` (default) or `unless`, or as a string for an expression.
+func ruleset(s *jsonschema.Schema) {
+	rd, ok := s.Definitions["Ruleset"]
+	if !ok {
+		return
+	}
+
+	// create a flattened copy of the ruleset
+	flattened := orderedmap.New[string, *jsonschema.Schema]()
+
+	if r, ok := s.Definitions["Rules"]; ok && len(r.OneOf) > 1 {
+		for pair := r.OneOf[1].Properties.Oldest(); pair != nil; pair = pair.Next() {
+			flattened.Set(pair.Key, pair.Value)
+		}
+	}
+
+	for pair := rd.Properties.Oldest(); pair != nil; pair = pair.Next() {
+		if pair.Key != "if" && pair.Key != "unless" {
+			flattened.Set(pair.Key, pair.Value)
+		}
+	}
+
+	s.Definitions["Ruleset"] = &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{
+				Type:                 "object",
+				Properties:           rd.Properties,
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+			{
+				Type:                 "object",
+				Properties:           flattened,
+				AdditionalProperties: jsonschema.FalseSchema,
+			},
+			{
+				Type:        "string",
+				Description: "Expression to limit the execution of the container.",
+			},
+		},
+	}
+}
+
+// matrix is a helper function to adjust the Matrix definition.
+//
+// Every key of the matrix, other than `include` and
+// `exclude`, is an axis with a string or slice of strings.
+func matrix(s *jsonschema.Schema) {
+	md, ok := s.Definitions["Matrix"]
+	if !ok {
+		return
+	}
+
+	md.AdditionalProperties = stringSlice()
+}
+
+// secret is a helper function to adjust the Secret definition
+// to require the name for an internal secret or the origin
+// for an external secret.
+func secret(s *jsonschema.Schema) {
+	sd, ok := s.Definitions["Secret"]
+	if !ok {
+		return
+	}
+
+	sd.Required = nil
+	sd.AnyOf = []*jsonschema.Schema{
+		{Required: []string{"name"}},
+		{Required: []string{"origin"}},
+	}
+}
+
+// pull is a helper function to adjust the pull property for every
+// container to accept the deprecated boolean form of the policy.
+func pull(s *jsonschema.Schema) {
+	for _, name := range []string{"Origin", "Service", "Step"} {
+		d, ok := s.Definitions[name]
+		if !ok {
+			continue
+		}
+
+		property, ok := d.Properties.Get("pull")
+		if !ok {
+			continue
+		}
+
+		d.Properties.Set("pull", &jsonschema.Schema{
+			Description: property.Description,
+			AnyOf: []*jsonschema.Schema{
+				property,
+				{
+					Type:        "boolean",
+					Description: "Deprecated: a true value equates to always and a false value equates to not_present.",
+					Deprecated:  true,
+				},
+			},
+		})
+	}
+}
+
+// stages is a helper function to adjust the StageSlice definition.
+//
+// The stages are a slice of stages in the yaml types,
+// but they are an object keyed by name in a pipeline.
+func stages(s *jsonschema.Schema) {
+	sd, ok := s.Definitions["StageSlice"]
+	if !ok {
+		return
+	}
+
+	sd.Type = "object"
+	sd.AdditionalProperties = sd.Items
+	sd.Items = nil
+}
+
+// secrets is a helper function to adjust the StepSecretSlice
+// definition to accept a slice with the names of secrets.
+func secrets(s *jsonschema.Schema) {
+	sd, ok := s.Definitions["StepSecretSlice"]
+	if !ok || sd.Items == nil {
+		return
+	}
+
+	s.Definitions["StepSecretSlice"] = &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type:  "array",
+				Items: &jsonschema.Schema{Type: "string", MinLength: &minLength},
+			},
+			sd,
+		},
+	}
+}
+
+// ulimit is a helper function to adjust the Ulimit definition
+// to accept the `name=soft[:hard]` form of a user limit.
+func ulimit(s *jsonschema.Schema) {
+	shortForm(s, "Ulimit", "^[^=:]+=[0-9]+(:[0-9]+)?$")
+}
+
+// volume is a helper function to adjust the Volume definition
+// to accept the `source[:destination[:mode]]` form of a volume.
+func volume(s *jsonschema.Schema) {
+	shortForm(s, "Volume", "^[^:]+(:[^:]+(:[^:]+)?)?$")
+}
+
+// shortForm is a helper function to adjust the provided definition
+// to accept a string matching the pattern in place of the object.
+func shortForm(s *jsonschema.Schema, name, pattern string) {
+	d, ok := s.Definitions[name]
+	if !ok {
+		return
+	}
+
+	s.Definitions[name] = &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type:    "string",
+				Pattern: pattern,
+			},
+			d,
+		},
+	}
+}
+
+// resetStringSlice is a helper function to replace the provided
+// property with a string or slice of strings, optionally
+// restricted to the enum, while keeping the description.
+func resetStringSlice(property *jsonschema.Schema, enum ...string) {
+	slice := stringSlice(enum...)
+
+	property.Type = ""
+	property.Items = nil
+	property.Enum = nil
+	property.OneOf = slice.OneOf
+}
+
+// stringSlice is a helper function to return the schema for a
+// string or slice of strings, optionally restricted to the enum.
+func stringSlice(enum ...string) *jsonschema.Schema {
+	item := scalar()
+
+	if len(enum) > 0 {
+		item = &jsonschema.Schema{Type: "string", Enum: interfaces(enum)}
+	}
+
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			item,
+			{
+				Type:  "array",
+				Items: item,
+			},
+		},
+	}
+}
+
+// scalar is a helper function to return the schema for a
+// scalar value which is unmarshaled to a string.
+func scalar() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "number"},
+			{Type: "boolean"},
+		},
+	}
+}
+
+// interfaces is a helper function to convert
+// a slice of strings to a slice of interfaces.
+func interfaces(values []string) []interface{} {
+	i := make([]interface{}, len(values))
+
+	for index, value := range values {
+		i[index] = value
+	}
+
+	return i
+}
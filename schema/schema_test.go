@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func TestSchema_JSON(t *testing.T) {
+	// setup types
+	// the schema embedded in the yaml package is the golden file
+	want, err := os.ReadFile("../yaml/schema.json")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	got, err := JSON()
+	if err != nil {
+		t.Errorf("JSON returned err: %v", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("JSON does not match yaml/schema.json, regenerate it with: make schema")
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	// setup types
+	s := compile(t)
+
+	// setup tests
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "../yaml/testdata/build_anchor_extension.yml"},
+		{file: "../yaml/testdata/build_anchor_stage.yml"},
+		{file: "../yaml/testdata/build_anchor_step.yml"},
+		{file: "../yaml/testdata/build_empty_env.yml"},
+		{file: "../yaml/testdata/encode/build.yml"},
+		{file: "../yaml/testdata/encode/build_canonical.yml"},
+		{file: "../yaml/testdata/encode/format.yml"},
+		{file: "testdata/short_forms.yml"},
+		{file: "../yaml/testdata/build.yml", want: "at '/secrets/6/origin': missing property 'name'"},
+		{file: "../yaml/testdata/build_cache.yml", want: "at '/cache/1/scope'"},
+		{file: "../yaml/testdata/build_validate.yml", want: "at '/services/0': missing property 'image'"},
+		{file: "testdata/unknown_key.yml", want: "additional properties 'enviroment' not allowed"},
+		{file: "testdata/invalid_type.yml", want: "at '/steps/0/retries': got string, want integer"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		data, err := os.ReadFile(test.file)
+		if err != nil {
+			t.Errorf("unable to read file %s: %v", test.file, err)
+
+			continue
+		}
+
+		var pipeline interface{}
+
+		err = yamlv3.Unmarshal(data, &pipeline)
+		if err != nil {
+			t.Errorf("unable to unmarshal %s: %v", test.file, err)
+
+			continue
+		}
+
+		err = s.Validate(pipeline)
+
+		if len(test.want) == 0 {
+			if err != nil {
+				t.Errorf("Validate for %s returned err: %v", test.file, err)
+			}
+
+			continue
+		}
+
+		if err == nil {
+			t.Errorf("Validate for %s should have returned err", test.file)
+
+			continue
+		}
+
+		if !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Validate for %s returned err %v, want %s", test.file, err, test.want)
+		}
+	}
+}
+
+// compile is a helper function to compile the generated schema.
+func compile(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+
+	data, err := JSON()
+	if err != nil {
+		t.Fatalf("JSON returned err: %v", err)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unable to unmarshal schema: %v", err)
+	}
+
+	c := jsonschema.NewCompiler()
+
+	err = c.AddResource("schema.json", doc)
+	if err != nil {
+		t.Fatalf("unable to add schema: %v", err)
+	}
+
+	s, err := c.Compile("schema.json")
+	if err != nil {
+		t.Fatalf("unable to compile schema: %v", err)
+	}
+
+	return s
+}
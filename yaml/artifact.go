@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+	"github.com/go-vela/types/raw"
+)
+
+type (
+	// ArtifactSlice is the yaml representation
+	// of the artifacts block for a step in a pipeline.
+	//
+	// Deprecated: use ArtifactSlice from github.com/go-vela/server/compiler/types/yaml instead.
+	ArtifactSlice []*Artifact
+
+	// Artifact is the yaml representation of an artifact
+	// from the artifacts block for a step in a pipeline.
+	//
+	// Deprecated: use Artifact from github.com/go-vela/server/compiler/types/yaml instead.
+	Artifact struct {
+		Name      string          `yaml:"name,omitempty"       json:"name,omitempty" jsonschema:"required,minLength=1,description=Unique name of the artifact in the pipeline.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-artifacts-key"`
+		Paths     raw.StringSlice `yaml:"paths,omitempty,flow" json:"paths,omitempty" jsonschema:"required,description=Paths or globs in the workspace to upload.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-artifacts-key"`
+		Retention int             `yaml:"retention,omitempty"  json:"retention,omitempty" jsonschema:"minimum=0,maximum=90,description=Number of days to retain the artifact.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-artifacts-key"`
+	}
+)
+
+// ToPipeline converts the ArtifactSlice type
+// to a pipeline ArtifactSlice type.
+func (a *ArtifactSlice) ToPipeline() *pipeline.ArtifactSlice {
+	// artifact slice we want to return
+	artifactSlice := new(pipeline.ArtifactSlice)
+
+	// iterate through each element in the artifact slice
+	for _, artifact := range *a {
+		// append the element to the pipeline artifact slice
+		*artifactSlice = append(*artifactSlice, &pipeline.Artifact{
+			Name:      artifact.Name,
+			Paths:     artifact.Paths,
+			Retention: artifact.Retention,
+		})
+	}
+
+	return artifactSlice
+}
+
+// validate is a helper function to verify the yaml for the
+// ArtifactSlice type with paths relative to the provided path.
+func (a *ArtifactSlice) validate(prefix, name string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// iterate through each artifact in the slice
+	for i, artifact := range *a {
+		artifactPath := indexPath(prefix, i)
+
+		// verify a name was provided for the artifact
+		if len(artifact.Name) == 0 {
+			diagnostics.errorf(joinPath(artifactPath, "name"), "no name provided for artifact in step %s", name)
+		}
+
+		// verify paths were provided for the artifact
+		if len(artifact.Paths) == 0 {
+			diagnostics.errorf(joinPath(artifactPath, "paths"), "no paths provided for artifact %s", artifact.Name)
+		}
+
+		// verify every path stays within the workspace
+		for j, p := range artifact.Paths {
+			if !workspacePath(p) {
+				diagnostics.errorf(indexPath(joinPath(artifactPath, "paths"), j), "artifact path %s must be relative to the workspace", p)
+			}
+		}
+
+		// verify the retention for the artifact
+		if artifact.Retention < 0 || artifact.Retention > constants.ArtifactRetentionMax {
+			diagnostics.errorf(joinPath(artifactPath, "retention"), "retention for artifact %s must be between 0 and %d days", artifact.Name, constants.ArtifactRetentionMax)
+		}
+	}
+
+	return diagnostics
+}
+
+// validateConsume is a helper function to verify every artifact
+// consumed by a step in the StepSlice type is produced by a step
+// that runs before it, according to the needs for the steps. The
+// provided external artifacts are produced by other stages, mapped
+// to the name of the stage producing them, and the provided stages
+// are the stages that run before the steps.
+func (s *StepSlice) validateConsume(prefix string, external map[string]string, stages map[string]bool) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture the step producing every artifact
+	producers := make(map[string]string)
+
+	for i, step := range *s {
+		for j, artifact := range step.Artifacts {
+			// skip artifacts without a name
+			if len(artifact.Name) == 0 {
+				continue
+			}
+
+			// verify the artifact name is unique for the steps
+			if producer, ok := producers[artifact.Name]; ok {
+				diagnostics.errorf(joinPath(indexPath(joinPath(indexPath(prefix, i), "artifacts"), j), "name"), "duplicate artifact name %s (first produced by step %s)", artifact.Name, producer)
+
+				continue
+			}
+
+			producers[artifact.Name] = step.Name
+		}
+	}
+
+	// create the dependency graph for the steps
+	//
+	// problems with the needs are reported when
+	// validating the steps so they are skipped
+	dag, err := s.dag().DAG()
+	if err != nil {
+		return diagnostics
+	}
+
+	// iterate through each step in the step slice
+	for i, step := range *s {
+		// capture the steps that run before the step
+		before := make(map[string]bool)
+
+		for _, name := range dag.Upstream(step.Name) {
+			before[name] = true
+		}
+
+		for j, name := range step.Consume {
+			consumePath := indexPath(joinPath(indexPath(prefix, i), "consume"), j)
+
+			producer, ok := producers[name]
+
+			switch {
+			case ok && before[producer]:
+				continue
+			case ok && producer == step.Name:
+				diagnostics.errorf(consumePath, "step %s consumes artifact %s it produces", step.Name, name)
+			case ok:
+				diagnostics.errorf(consumePath, "step %s consumes artifact %s produced by step %s which does not run before it", step.Name, name, producer)
+			default:
+				// check if the artifact is produced by another stage
+				stage, found := external[name]
+
+				switch {
+				case found && stages[stage]:
+					continue
+				case found:
+					diagnostics.errorf(consumePath, "step %s consumes artifact %s produced by stage %s which does not run before it", step.Name, name, stage)
+				default:
+					diagnostics.errorf(consumePath, "step %s consumes unknown artifact %s", step.Name, name)
+				}
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+// dag is a helper function to create the pipeline containers
+// with only the name and needs for every step so a dependency
+// graph can be created without expanding any matrix steps.
+func (s *StepSlice) dag() *pipeline.ContainerSlice {
+	containers := new(pipeline.ContainerSlice)
+
+	for _, step := range *s {
+		*containers = append(*containers, &pipeline.Container{
+			Name:  step.Name,
+			Needs: step.Needs,
+		})
+	}
+
+	return containers
+}
+
+// validateConsume is a helper function to verify every artifact
+// consumed by a step in the StageSlice type is produced by a step
+// that runs before it, in the same stage or in a stage that the
+// stage transitively needs.
+func (s *StageSlice) validateConsume() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture the stage producing every artifact
+	producers := make(map[string]string)
+
+	// capture every stage name
+	names := make(map[string]bool)
+
+	for _, stage := range *s {
+		names[stage.Name] = true
+	}
+
+	stages := new(pipeline.StageSlice)
+
+	for _, stage := range *s {
+		stagePath := joinPath(joinPath("stages", stage.Name), "steps")
+
+		for i, step := range stage.Steps {
+			for j, artifact := range step.Artifacts {
+				producer, ok := producers[artifact.Name]
+
+				// verify the artifact name is unique for the stages
+				//
+				// duplicates within a stage are reported for the steps
+				if ok && producer != stage.Name {
+					diagnostics.errorf(joinPath(indexPath(joinPath(indexPath(stagePath, i), "artifacts"), j), "name"), "duplicate artifact name %s (first produced by stage %s)", artifact.Name, producer)
+				}
+
+				if !ok && len(artifact.Name) > 0 {
+					producers[artifact.Name] = stage.Name
+				}
+			}
+		}
+
+		// capture the needs for the stage
+		//
+		// the implicit needs for stages that are injected
+		// when compiling the pipeline, like clone, are skipped
+		needs := []string{}
+
+		for _, need := range stage.Needs {
+			if names[need] {
+				needs = append(needs, need)
+			}
+		}
+
+		*stages = append(*stages, &pipeline.Stage{
+			Name:  stage.Name,
+			Needs: needs,
+		})
+	}
+
+	// create the dependency graph for the stages
+	//
+	// problems with the needs are reported when
+	// validating the stages so they are skipped
+	dag, err := stages.DAG()
+	if err != nil {
+		return diagnostics
+	}
+
+	// iterate through each stage in the stage slice
+	for _, stage := range *s {
+		// capture the artifacts produced by other stages
+		external := make(map[string]string)
+
+		for artifact, producer := range producers {
+			if producer != stage.Name {
+				external[artifact] = producer
+			}
+		}
+
+		// capture the stages that run before the stage
+		before := make(map[string]bool)
+
+		for _, name := range dag.Upstream(stage.Name) {
+			before[name] = true
+		}
+
+		diagnostics = append(diagnostics, stage.Steps.validateConsume(joinPath(joinPath("stages", stage.Name), "steps"), external, before)...)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/pipeline"
+)
+
+func TestYaml_ArtifactSlice_ToPipeline(t *testing.T) {
+	// setup types
+	a := &ArtifactSlice{
+		{
+			Name:      "binary",
+			Paths:     []string{"bin/app"},
+			Retention: 7,
+		},
+	}
+
+	want := &pipeline.ArtifactSlice{
+		{
+			Name:      "binary",
+			Paths:     []string{"bin/app"},
+			Retention: 7,
+		},
+	}
+
+	// run test
+	got := a.ToPipeline()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPipeline is %v, want %v", got, want)
+	}
+}
+
+func TestYaml_StepSlice_Validate_Artifacts(t *testing.T) {
+	// setup types
+	steps := new(StepSlice)
+
+	b, err := os.ReadFile("testdata/step_artifacts.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(b, steps)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	want := []string{
+		"error: steps[0].artifacts[1].paths[0]: artifact path /tmp/coverage.out must be relative to the workspace",
+		"error: steps[0].artifacts[1].retention: retention for artifact coverage must be between 0 and 90 days",
+		"error: steps[1].artifacts[0].name: duplicate artifact name binary (first produced by step build)",
+		"error: steps[1].consume[1]: step lint consumes artifact report produced by step test which does not run before it",
+		"error: steps[3].consume[0]: step publish consumes artifact report produced by step test which does not run before it",
+		"error: steps[3].consume[1]: step publish consumes artifact publish it produces",
+	}
+
+	// run test
+	got := []string{}
+
+	for _, diagnostic := range steps.Validate() {
+		got = append(got, diagnostic.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate is %v, want %v", got, want)
+	}
+
+	containers := *steps.ToPipeline()
+
+	if !reflect.DeepEqual(containers[1].Consume, []string{"binary", "report"}) {
+		t.Errorf("ToPipeline consume is %v, want %v", containers[1].Consume, []string{"binary", "report"})
+	}
+}
+
+func TestYaml_StageSlice_Validate_Artifacts(t *testing.T) {
+	// setup types
+	stages := new(StageSlice)
+
+	b, err := os.ReadFile("testdata/stage_artifacts.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(b, stages)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	want := []string{
+		"error: stages.docs.steps[0].consume[0]: step render consumes artifact report produced by stage test which does not run before it",
+		"error: stages.docs.steps[0].consume[1]: step render consumes artifact binary produced by stage build which does not run before it",
+	}
+
+	// run test
+	got := []string{}
+
+	for _, diagnostic := range stages.Validate() {
+		got = append(got, diagnostic.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate is %v, want %v", got, want)
+	}
+}
@@ -3,15 +3,25 @@
 package yaml
 
 import (
+	"strings"
+
+	"github.com/buildkite/yaml"
+
 	"github.com/go-vela/types/library"
 	"github.com/go-vela/types/raw"
 )
 
+// extensionPrefix is the prefix for the top-level keys of a
+// pipeline that are ignored when compiling the pipeline. These
+// keys are commonly used to define anchors for reusable blocks.
+const extensionPrefix = "x-"
+
 // Build is the yaml representation of a build for a pipeline.
 //
 // Deprecated: use Build from github.com/go-vela/server/compiler/types/yaml instead.
 type Build struct {
 	Version     string             `yaml:"version,omitempty"   json:"version,omitempty"  jsonschema:"required,minLength=1,description=Provide syntax version used to evaluate the pipeline.\nReference: https://go-vela.github.io/docs/reference/yaml/version/"`
+	Include     raw.StringSlice    `yaml:"include,omitempty"   json:"include,omitempty" jsonschema:"description=Provide the paths of local files to merge into the pipeline.\nReference: https://go-vela.github.io/docs/reference/yaml/include/"`
 	Metadata    Metadata           `yaml:"metadata,omitempty"  json:"metadata,omitempty" jsonschema:"description=Pass extra information.\nReference: https://go-vela.github.io/docs/reference/yaml/metadata/"`
 	Environment raw.StringSliceMap `yaml:"environment,omitempty" json:"environment,omitempty" jsonschema:"description=Provide global environment variables injected into the container environment.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-environment-key"`
 	Worker      Worker             `yaml:"worker,omitempty"    json:"worker,omitempty" jsonschema:"description=Limit the pipeline to certain types of workers.\nReference: https://go-vela.github.io/docs/reference/yaml/worker/"`
@@ -20,6 +30,10 @@ type Build struct {
 	Stages      StageSlice         `yaml:"stages,omitempty"    json:"stages,omitempty" jsonschema:"oneof_required=stages,description=Provide parallel execution instructions.\nReference: https://go-vela.github.io/docs/reference/yaml/stages/"`
 	Steps       StepSlice          `yaml:"steps,omitempty"     json:"steps,omitempty" jsonschema:"oneof_required=steps,description=Provide sequential execution instructions.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/"`
 	Templates   TemplateSlice      `yaml:"templates,omitempty" json:"templates,omitempty" jsonschema:"description=Provide the name of templates to expand.\nReference: https://go-vela.github.io/docs/reference/yaml/templates/"`
+	Cache       CacheSlice         `yaml:"cache,omitempty"     json:"cache,omitempty" jsonschema:"description=Provide caches restored before and saved after the build.\nReference: https://go-vela.github.io/docs/reference/yaml/cache/"`
+	// Extensions are the top-level keys prefixed with `x-`
+	// which are ignored when compiling the pipeline.
+	Extensions map[string]interface{} `yaml:"-" json:"-"`
 }
 
 // ToPipelineLibrary converts the Build type to a library Pipeline type.
@@ -67,6 +81,7 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	// build we try unmarshalling to
 	build := new(struct {
 		Version     string
+		Include     raw.StringSlice
 		Metadata    Metadata
 		Environment raw.StringSliceMap
 		Worker      Worker
@@ -75,6 +90,7 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		Stages      StageSlice
 		Steps       StepSlice
 		Templates   TemplateSlice
+		Cache       CacheSlice
 	})
 
 	// attempt to unmarshal as a build type
@@ -83,6 +99,31 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		return err
 	}
 
+	// map we capture the extensions from
+	keys := make(map[interface{}]interface{})
+
+	// attempt to unmarshal as a map type
+	err = unmarshal(&keys)
+	if err != nil {
+		return err
+	}
+
+	var extensions map[string]interface{}
+
+	// iterate through each top-level key in the map
+	for k, value := range keys {
+		key, ok := k.(string)
+		if !ok || !strings.HasPrefix(key, extensionPrefix) {
+			continue
+		}
+
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+
+		extensions[key] = value
+	}
+
 	// give the documented default value to metadata environment
 	if build.Metadata.Environment == nil {
 		build.Metadata.Environment = []string{"steps", "services", "secrets"}
@@ -90,6 +131,7 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	// override the values
 	b.Version = build.Version
+	b.Include = build.Include
 	b.Metadata = build.Metadata
 	b.Environment = build.Environment
 	b.Worker = build.Worker
@@ -98,6 +140,70 @@ func (b *Build) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	b.Stages = build.Stages
 	b.Steps = build.Steps
 	b.Templates = build.Templates
+	b.Cache = build.Cache
+	b.Extensions = extensions
 
 	return nil
 }
+
+// Validate verifies the yaml for the Build type and returns
+// every problem found instead of stopping at the first one.
+func (b *Build) Validate() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// verify a syntax version was provided
+	if len(b.Version) == 0 {
+		diagnostics.warnf("version", "no version provided")
+	}
+
+	// verify the syntax version is supported
+	err := CheckVersion(b.Version)
+	if err != nil {
+		diagnostics.errorf("version", "%v", err)
+	}
+
+	// verify stages and steps are not both provided
+	if len(b.Stages) > 0 && len(b.Steps) > 0 {
+		diagnostics.errorf("steps", "cannot have both stages and steps at the top level of pipeline")
+	}
+
+	// verify either stages or steps were provided
+	if len(b.Stages) == 0 && len(b.Steps) == 0 {
+		diagnostics.errorf("", "no stages or steps provided")
+	}
+
+	diagnostics = append(diagnostics, b.Cache.validate("cache")...)
+	diagnostics = append(diagnostics, b.Secrets.Validate()...)
+	diagnostics = append(diagnostics, b.Services.Validate()...)
+	diagnostics = append(diagnostics, b.Stages.Validate()...)
+	diagnostics = append(diagnostics, b.Steps.Validate()...)
+
+	return diagnostics
+}
+
+// ValidateBytes unmarshals the provided raw YAML document to
+// a Build type and validates it. The line and column for every
+// diagnostic are resolved from the provided document. An error
+// is only returned when the document can not be unmarshaled.
+func ValidateBytes(data []byte) (*Build, Diagnostics, error) {
+	b := new(Build)
+
+	// attempt to unmarshal the document as a build type
+	err := yaml.Unmarshal(data, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// attempt to create a source map for the document
+	source, err := NewSourceMap(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	diagnostics := b.Validate()
+
+	// resolve the position for every diagnostic
+	diagnostics.Locate(source)
+
+	return b, diagnostics, nil
+}
@@ -662,6 +662,104 @@ func TestYaml_Build_UnmarshalYAML(t *testing.T) {
 				},
 			},
 		},
+		{
+			file: "testdata/build_anchor_extension.yml",
+			want: &Build{
+				Version: "1",
+				Metadata: Metadata{
+					Environment: []string{"steps", "services", "secrets"},
+				},
+				Environment: raw.StringSliceMap{
+					"GOOS":   "linux",
+					"GOARCH": "arm64",
+				},
+				Services: ServiceSlice{
+					{
+						Name:  "postgres",
+						Image: "postgres:16",
+						Pull:  "not_present",
+						Environment: raw.StringSliceMap{
+							"POSTGRES_DB": "vela",
+						},
+					},
+					{
+						Name:  "replica",
+						Image: "postgres:16",
+						Pull:  "not_present",
+						Environment: raw.StringSliceMap{
+							"GOOS":        "linux",
+							"GOARCH":      "amd64",
+							"POSTGRES_DB": "replica",
+						},
+					},
+				},
+				Steps: StepSlice{
+					{
+						Commands: raw.StringSlice{"go test ./..."},
+						Name:     "test",
+						Image:    "golang:1.23",
+						Pull:     "always",
+						Environment: raw.StringSliceMap{
+							"GOOS":   "linux",
+							"GOARCH": "amd64",
+						},
+						Ruleset: Ruleset{
+							If: Rules{
+								Branch: []string{"main"},
+								Event:  []string{"push", "tag"},
+							},
+							Matcher:  "filepath",
+							Operator: "and",
+						},
+					},
+					{
+						Commands: raw.StringSlice{"go build ./..."},
+						Name:     "build",
+						Image:    "golang:1.23",
+						Pull:     "not_present",
+						Environment: raw.StringSliceMap{
+							"CGO_ENABLED": "0",
+							"GOFLAGS":     "-mod=vendor",
+						},
+						Ruleset: Ruleset{
+							If: Rules{
+								Branch: []string{"release"},
+								Event:  []string{"push", "tag"},
+							},
+							Matcher:  "filepath",
+							Operator: "and",
+						},
+					},
+				},
+				Extensions: map[string]interface{}{
+					"x-images": map[interface{}]interface{}{
+						"go": map[interface{}]interface{}{
+							"image": "golang:1.23",
+							"pull":  "always",
+						},
+					},
+					"x-environment": map[interface{}]interface{}{
+						"GOOS":   "linux",
+						"GOARCH": "amd64",
+					},
+					"x-list-environment": map[interface{}]interface{}{
+						"environment": []interface{}{"CGO_ENABLED=0", "GOFLAGS=-mod=vendor"},
+					},
+					"x-ruleset": map[interface{}]interface{}{
+						"push": map[interface{}]interface{}{
+							"event":  []interface{}{"push", "tag"},
+							"branch": "main",
+						},
+					},
+					"x-service": map[interface{}]interface{}{
+						"image": "postgres:16",
+						"environment": map[interface{}]interface{}{
+							"POSTGRES_DB": "vela",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	// run tests
@@ -684,3 +782,83 @@ func TestYaml_Build_UnmarshalYAML(t *testing.T) {
 		}
 	}
 }
+
+func TestYaml_Build_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{
+			name: "build",
+			file: "testdata/build.yml",
+			want: []string{
+				"139:5: error: secrets[6].origin.name: no name provided for secret origin",
+			},
+		},
+		{
+			name: "stages",
+			file: "testdata/build_anchor_stage.yml",
+			want: []string{},
+		},
+		{
+			name: "invalid",
+			file: "testdata/build_validate.yml",
+			want: []string{
+				"25:5: error: secrets[0].engine: invalid engine cloud for secret docker_username",
+				"30:7: error: secrets[1].origin.pull: invalid pull policy sometimes for secret origin vault",
+				"5:5: error: services[0].image: no image provided for service postgres",
+				"6:5: error: services[0].pull: invalid pull policy sometimes for service postgres",
+				"12:5: error: steps[1].name: duplicate step name install (first declared at steps[0])",
+				"12:5: error: steps[1].image: no image provided for step install",
+				"18:5: error: steps[2].pull: invalid pull policy maybe for step build",
+				"20:7: error: steps[2].ruleset.matcher: invalid matcher fuzzy",
+			},
+		},
+		{
+			name: "stages and steps",
+			file: "testdata/build_validate_stages.yml",
+			want: []string{
+				"15:1: error: steps: cannot have both stages and steps at the top level of pipeline",
+				"13:9: error: stages.test.steps[1].pull: invalid pull policy later for step lint",
+			},
+		},
+		{
+			name: "unknown version",
+			file: "testdata/version/unknown.yml",
+			want: []string{
+				"1:1: error: version: unknown pipeline version: 3 (supported versions: 1, 2)",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Errorf("unable to read file %s: %v", test.file, err)
+			}
+
+			_, diagnostics, err := ValidateBytes(data)
+			if err != nil {
+				t.Errorf("ValidateBytes returned err: %v", err)
+			}
+
+			got := []string{}
+
+			for _, diagnostic := range diagnostics {
+				got = append(got, diagnostic.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ValidateBytes is %v, want %v", got, test.want)
+			}
+
+			if diagnostics.HasErrors() != (diagnostics.Err() != nil) {
+				t.Errorf("HasErrors is %v, want %v", diagnostics.HasErrors(), diagnostics.Err() != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+	"github.com/go-vela/types/raw"
+)
+
+type (
+	// CacheSlice is the yaml representation
+	// of the cache block for a pipeline.
+	//
+	// Deprecated: use CacheSlice from github.com/go-vela/server/compiler/types/yaml instead.
+	CacheSlice []*Cache
+
+	// Cache is the yaml representation of a
+	// cache from the cache block for a pipeline.
+	//
+	// Deprecated: use Cache from github.com/go-vela/server/compiler/types/yaml instead.
+	Cache struct {
+		Key         string          `yaml:"key,omitempty"          json:"key,omitempty" jsonschema:"required,minLength=1,example=go-${VELA_BUILD_BRANCH},description=Key used to save and restore the cache.\nReference: https://go-vela.github.io/docs/reference/yaml/cache/#the-key-key"`
+		Paths       raw.StringSlice `yaml:"paths,omitempty,flow"   json:"paths,omitempty" jsonschema:"required,description=Paths in the workspace to save and restore.\nReference: https://go-vela.github.io/docs/reference/yaml/cache/#the-paths-key"`
+		RestoreKeys raw.StringSlice `yaml:"restore_keys,omitempty" json:"restore_keys,omitempty" jsonschema:"description=Fallback keys used to restore the cache when the key is not found.\nReference: https://go-vela.github.io/docs/reference/yaml/cache/#the-restore_keys-key"`
+		Scope       string          `yaml:"scope,omitempty"        json:"scope,omitempty" jsonschema:"enum=repo,enum=org,default=repo,description=Scope the cache is shared with.\nReference: https://go-vela.github.io/docs/reference/yaml/cache/#the-scope-key"`
+	}
+)
+
+// ToPipeline converts the CacheSlice type
+// to a pipeline CacheSlice type.
+func (c *CacheSlice) ToPipeline() *pipeline.CacheSlice {
+	// cache slice we want to return
+	cacheSlice := new(pipeline.CacheSlice)
+
+	// iterate through each element in the cache slice
+	for _, cache := range *c {
+		// implicitly set `scope` field if empty
+		scope := cache.Scope
+		if len(scope) == 0 {
+			scope = constants.CacheScopeRepo
+		}
+
+		// append the element to the pipeline cache slice
+		*cacheSlice = append(*cacheSlice, &pipeline.Cache{
+			Key:         cache.Key,
+			Paths:       cache.Paths,
+			RestoreKeys: cache.RestoreKeys,
+			Scope:       scope,
+		})
+	}
+
+	return cacheSlice
+}
+
+// validate is a helper function to verify the yaml for the
+// CacheSlice type with paths relative to the provided path.
+func (c *CacheSlice) validate(prefix string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	keys := make(map[string]bool)
+
+	// iterate through each cache in the slice
+	for i, cache := range *c {
+		cachePath := indexPath(prefix, i)
+
+		// verify a key was provided for the cache
+		if len(cache.Key) == 0 {
+			diagnostics.errorf(joinPath(cachePath, "key"), "no key provided for cache")
+		}
+
+		// verify the key is unique for the slice
+		if len(cache.Key) > 0 {
+			if keys[cache.Key] {
+				diagnostics.errorf(joinPath(cachePath, "key"), "duplicate cache key %s", cache.Key)
+			}
+
+			keys[cache.Key] = true
+		}
+
+		// verify paths were provided for the cache
+		if len(cache.Paths) == 0 {
+			diagnostics.errorf(joinPath(cachePath, "paths"), "no paths provided for cache %s", cache.Key)
+		}
+
+		// verify every path stays within the workspace
+		for j, p := range cache.Paths {
+			if !workspacePath(p) {
+				diagnostics.errorf(indexPath(joinPath(cachePath, "paths"), j), "cache path %s must be relative to the workspace", p)
+			}
+		}
+
+		// verify the scope for the cache
+		switch cache.Scope {
+		case "", constants.CacheScopeRepo, constants.CacheScopeOrg:
+		default:
+			diagnostics.errorf(joinPath(cachePath, "scope"), "invalid scope %s for cache %s", cache.Scope, cache.Key)
+		}
+	}
+
+	return diagnostics
+}
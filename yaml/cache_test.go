@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+)
+
+func TestYaml_CacheSlice_ToPipeline(t *testing.T) {
+	// setup types
+	c := &CacheSlice{
+		{
+			Key:         "go-${VELA_BUILD_BRANCH}",
+			Paths:       []string{".cache/go-build"},
+			RestoreKeys: []string{"go-"},
+		},
+		{
+			Key:   "node",
+			Paths: []string{"node_modules"},
+			Scope: constants.CacheScopeOrg,
+		},
+	}
+
+	want := &pipeline.CacheSlice{
+		{
+			Key:         "go-${VELA_BUILD_BRANCH}",
+			Paths:       []string{".cache/go-build"},
+			RestoreKeys: []string{"go-"},
+			Scope:       constants.CacheScopeRepo,
+		},
+		{
+			Key:   "node",
+			Paths: []string{"node_modules"},
+			Scope: constants.CacheScopeOrg,
+		},
+	}
+
+	// run test
+	got := c.ToPipeline()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPipeline is %v, want %v", got, want)
+	}
+}
+
+func TestYaml_Build_Validate_Cache(t *testing.T) {
+	// setup types
+	b := new(Build)
+
+	data, err := os.ReadFile("testdata/build_cache.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	want := []string{
+		"error: cache[0].paths[1]: cache path /root/go/pkg/mod must be relative to the workspace",
+		"error: cache[1].key: duplicate cache key go-${VELA_BUILD_BRANCH}",
+		"error: cache[1].scope: invalid scope global for cache go-${VELA_BUILD_BRANCH}",
+		"error: steps[0].cache[1].key: no key provided for cache",
+		"error: steps[0].cache[1].paths[0]: cache path ../outside must be relative to the workspace",
+	}
+
+	// run test
+	got := []string{}
+
+	for _, diagnostic := range b.Validate() {
+		got = append(got, diagnostic.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate is %v, want %v", got, want)
+	}
+
+	if len(b.Steps[0].Cache) != 2 || b.Steps[0].Cache[0].Scope != constants.CacheScopeOrg {
+		t.Errorf("UnmarshalYAML for step cache is %v", b.Steps[0].Cache)
+	}
+}
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-vela/types/constants"
+)
+
+type (
+	// Diagnostics is a list of problems found
+	// while validating the yaml for a pipeline.
+	Diagnostics []*Diagnostic
+
+	// Diagnostic is a single problem found while
+	// validating the yaml for a pipeline.
+	Diagnostic struct {
+		Severity string `json:"severity"`
+		Path     string `json:"path"`
+		Message  string `json:"message"`
+		Line     int    `json:"line,omitempty"`
+		Column   int    `json:"column,omitempty"`
+	}
+)
+
+// String implements the Stringer interface for the Diagnostic type.
+func (d *Diagnostic) String() string {
+	// check if a position was resolved for the diagnostic
+	if d.Line > 0 {
+		return fmt.Sprintf("%d:%d: %s: %s: %s", d.Line, d.Column, d.Severity, d.Path, d.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", d.Severity, d.Path, d.Message)
+}
+
+// HasErrors returns true if any of the diagnostics
+// has the error severity.
+func (d Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d {
+		if diagnostic.Severity == constants.SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns an error combining every diagnostic with
+// the error severity. When none of the diagnostics has
+// the error severity, the function returns nil.
+func (d Diagnostics) Err() error {
+	errs := []error{}
+
+	for _, diagnostic := range d {
+		if diagnostic.Severity == constants.SeverityError {
+			errs = append(errs, errors.New(diagnostic.String()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Locate sets the line and column for every diagnostic
+// by resolving its path in the provided source map.
+func (d Diagnostics) Locate(source *SourceMap) {
+	for _, diagnostic := range d {
+		diagnostic.Line, diagnostic.Column = source.Locate(diagnostic.Path)
+	}
+}
+
+// String implements the Stringer interface for the Diagnostics type.
+func (d Diagnostics) String() string {
+	lines := []string{}
+
+	for _, diagnostic := range d {
+		lines = append(lines, diagnostic.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// errorf is a helper function to append a diagnostic
+// with the error severity to the list of diagnostics.
+func (d *Diagnostics) errorf(path, format string, args ...interface{}) {
+	*d = append(*d, &Diagnostic{
+		Severity: constants.SeverityError,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// warnf is a helper function to append a diagnostic
+// with the warning severity to the list of diagnostics.
+func (d *Diagnostics) warnf(path, format string, args ...interface{}) {
+	*d = append(*d, &Diagnostic{
+		Severity: constants.SeverityWarning,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// joinPath is a helper function to append a key to a path.
+// A key containing a dot, bracket or quote, i.e. the name of
+// a stage like `s.x`, is quoted so the path can be resolved.
+func joinPath(path, key string) string {
+	if strings.ContainsAny(key, `.[]"`) {
+		key = strconv.Quote(key)
+	}
+
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// indexPath is a helper function to append an index to a path.
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// validPull is a helper function to verify the provided
+// value is one of the supported image pull policies.
+func validPull(pull string) bool {
+	switch pull {
+	case constants.PullAlways, constants.PullNotPresent, constants.PullOnStart, constants.PullNever:
+		return true
+	default:
+		return false
+	}
+}
+
+// workspacePath is a helper function to verify the provided
+// path is relative and does not leave the workspace.
+func workspacePath(p string) bool {
+	if path.IsAbs(p) {
+		return false
+	}
+
+	clean := path.Clean(p)
+
+	return clean != ".." && !strings.HasPrefix(clean, "../")
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import "testing"
+
+func TestYaml_workspacePath(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "vendor", want: true},
+		{path: "./.cache/go-build", want: true},
+		{path: "..cache", want: true},
+		{path: "dist/../bin", want: true},
+		{path: "..", want: false},
+		{path: "../outside", want: false},
+		{path: "dist/../../outside", want: false},
+		{path: "/root/go/pkg/mod", want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := workspacePath(test.path)
+
+		if got != test.want {
+			t.Errorf("workspacePath for %s is %v, want %v", test.path, got, test.want)
+		}
+	}
+}
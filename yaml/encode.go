@@ -0,0 +1,1678 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/buildkite/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/go-vela/types/constants"
+)
+
+// ErrFormatAlias defines the error type when formatting a document
+// that uses anchors and aliases since formatting expands them.
+var ErrFormatAlias = errors.New("unable to format a document using aliases")
+
+var (
+	// defaultMetadataEnvironment is the documented default for the
+	// environment key of the metadata block for a pipeline.
+	defaultMetadataEnvironment = []string{"steps", "services", "secrets"}
+
+	// defaultValues are the values set for a key when parsing
+	// that are kept when they already exist in a document.
+	defaultValues = map[string][]string{
+		"continue": {"false"},
+		"engine":   {constants.DriverNative},
+		"matcher":  {constants.MatcherFilepath},
+		"operator": {constants.OperatorAnd},
+		"pull":     {constants.PullNotPresent, constants.SecretPullBuild},
+		"type":     {constants.SecretRepo},
+	}
+
+	// rulesetKeys are the keys for the advanced form of a ruleset.
+	rulesetKeys = map[string]bool{"if": true, "unless": true, "matcher": true, "operator": true, "continue": true}
+)
+
+// Encode returns the canonical YAML document for the provided
+// Build type. The compact forms of the syntax are used where
+// possible and values matching the defaults set when parsing
+// are omitted, so parsing the returned document produces a
+// Build type identical to the provided one.
+func Encode(b *Build) ([]byte, error) {
+	node, err := encodeBuild(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeDocument(&yamlv3.Node{
+		Kind:    yamlv3.DocumentNode,
+		Content: []*yamlv3.Node{node},
+	})
+}
+
+// Edit returns the provided raw YAML document updated to match
+// the provided Build type. The key order and comments of the
+// existing document are preserved, keys added to the Build type
+// are appended in the canonical order and keys removed from it
+// are dropped. Keys explicitly set to their default value are
+// kept so editing a document only changes what was modified.
+func Edit(src []byte, b *Build) ([]byte, error) {
+	doc := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(src, doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse source document: %w", err)
+	}
+
+	node, err := encodeBuild(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// return the canonical document when the source is empty
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return encodeDocument(&yamlv3.Node{
+			Kind:    yamlv3.DocumentNode,
+			Content: []*yamlv3.Node{node},
+		})
+	}
+
+	doc.Content[0] = mergeNode(doc.Content[0], node, "")
+
+	return encodeDocument(doc)
+}
+
+// Format returns the canonical form of the provided raw YAML
+// document. Every value is written as Encode would write it while
+// the comments from the document are kept with the keys and
+// items they were written for.
+func Format(src []byte) ([]byte, error) {
+	doc := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(src, doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse source document: %w", err)
+	}
+
+	// verify the document does not use aliases
+	if hasAlias(doc) {
+		return nil, ErrFormatAlias
+	}
+
+	b := new(Build)
+
+	// attempt to unmarshal the document as a build
+	err = yaml.Unmarshal(src, b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal source document: %w", err)
+	}
+
+	node, err := encodeBuild(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// return the canonical document when the source is empty
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		doc = &yamlv3.Node{Kind: yamlv3.DocumentNode}
+	} else {
+		transferComments(doc.Content[0], node)
+	}
+
+	doc.Content = []*yamlv3.Node{node}
+
+	return encodeDocument(doc)
+}
+
+// encodeDocument is a helper function to write the
+// provided document node with the indentation and
+// spacing used by pipelines.
+func encodeDocument(doc *yamlv3.Node) ([]byte, error) {
+	out, err := encodeNode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return spaceSections(out), nil
+}
+
+// encodeNode is a helper function to write the provided
+// document node with the indentation used by pipelines.
+// Merge keys are written without an explicit tag.
+func encodeNode(doc *yamlv3.Node) ([]byte, error) {
+	untagMerge(doc)
+
+	buffer := new(bytes.Buffer)
+
+	encoder := yamlv3.NewEncoder(buffer)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode document: %w", err)
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode document: %w", err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// untagMerge is a helper function to clear the tag for every
+// merge key in the provided node, since the encoder otherwise
+// writes the key as `!!merge <<`.
+func untagMerge(node *yamlv3.Node) {
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!merge" {
+		node.Tag = ""
+	}
+
+	for _, item := range node.Content {
+		untagMerge(item)
+	}
+}
+
+// keepEmptyLines is a helper function to insert the empty lines
+// from the provided source document into the provided output for
+// the same document, so rewriting a document keeps its spacing.
+// The lines are matched ignoring whitespace, and the empty lines
+// above a source line are inserted above the output line matching
+// it or, when the source line was rewritten, above the output line
+// following the output for the previous matching line.
+func keepEmptyLines(src, out []byte) []byte {
+	source := strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+
+	// normalize is a helper function to remove the whitespace from a line
+	normalize := func(line string) string {
+		return strings.Join(strings.Fields(line), "")
+	}
+
+	// capture the content of the source with the empty lines above it
+	content := []string{}
+	empty := []int{}
+	count := 0
+
+	for _, line := range source {
+		if len(strings.TrimSpace(line)) == 0 {
+			count++
+
+			continue
+		}
+
+		content = append(content, normalize(line))
+		empty = append(empty, count)
+		count = 0
+	}
+
+	// compute the longest common subsequence of the lines
+	lcs := make([][]int, len(content)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(lines)+1)
+	}
+
+	for i := len(content) - 1; i >= 0; i-- {
+		for j := len(lines) - 1; j >= 0; j-- {
+			if content[i] == normalize(lines[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// capture the empty lines to insert above every output line
+	insert := make(map[int]int)
+	next := 0
+
+	for i, j := 0, 0; i < len(content); {
+		switch {
+		case j < len(lines) && content[i] == normalize(lines[j]):
+			insert[j] = max(insert[j], empty[i])
+			i++
+			j++
+			next = j
+		case j < len(lines) && lcs[i][j+1] >= lcs[i+1][j]:
+			j++
+		default:
+			// the source line was rewritten
+			insert[next] = max(insert[next], empty[i])
+			i++
+		}
+	}
+
+	result := make([]string, 0, len(lines))
+
+	for j, line := range lines {
+		// skip the first line and the lines the output already separates
+		if j > 0 && len(strings.TrimSpace(lines[j-1])) > 0 {
+			for k := 0; k < insert[j]; k++ {
+				result = append(result, "")
+			}
+		}
+
+		result = append(result, line)
+	}
+
+	return []byte(strings.Join(result, "\n") + "\n")
+}
+
+// spaceSections is a helper function to separate the top level
+// keys of the document, and every service, secret, stage and
+// step in it, with an empty line. Comments directly above an
+// element stay attached to the element.
+func spaceSections(out []byte) []byte {
+	lines := strings.Split(string(out), "\n")
+	result := make([]string, 0, len(lines))
+
+	// capture the top level key for the current line
+	section := ""
+	// capture the last line that was not a comment
+	previous := ""
+
+	// iterate through each line of the document
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		// skip comments and empty lines
+		if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+			result = append(result, line)
+
+			continue
+		}
+
+		separate := false
+
+		switch {
+		case indent == 0:
+			section = strings.SplitN(trimmed, ":", 2)[0]
+
+			separate = len(previous) > 0
+		case indent == 2 && section == "stages" && !strings.HasPrefix(trimmed, "- "):
+			separate = !strings.HasPrefix(previous, "stages:")
+		case indent == 2 && strings.HasPrefix(trimmed, "- "):
+			switch section {
+			case "secrets", "services", "steps":
+				separate = strings.TrimSpace(previous) != section+":"
+			}
+		case indent == 6 && section == "stages" && strings.HasPrefix(trimmed, "- "):
+			separate = strings.TrimSpace(previous) != "steps:"
+		}
+
+		previous = line
+
+		if !separate {
+			result = append(result, line)
+
+			continue
+		}
+
+		// move the comments directly above the line after the empty line
+		start := len(result)
+		for start > 0 && strings.HasPrefix(strings.TrimSpace(result[start-1]), "#") {
+			start--
+		}
+
+		// skip adding an empty line when one already exists
+		if start > 0 && len(strings.TrimSpace(result[start-1])) == 0 {
+			result = append(result, line)
+
+			continue
+		}
+
+		result = append(result[:start], append([]string{""}, result[start:]...)...)
+		result = append(result, line)
+	}
+
+	return []byte(strings.Join(result, "\n"))
+}
+
+// encodeBuild is a helper function to create
+// the canonical node for the Build type.
+func encodeBuild(b *Build) (*yamlv3.Node, error) {
+	node := mappingNode()
+
+	addNode(node, "version", stringNode(b.Version))
+	addNode(node, "include", sliceNode(b.Include, false))
+
+	// iterate through each extension in sorted order
+	for _, key := range sortedKeys(b.Extensions) {
+		extension, err := valueNode(b.Extensions[key])
+		if err != nil {
+			return nil, err
+		}
+
+		addNode(node, key, extension)
+	}
+
+	addNode(node, "metadata", encodeMetadata(&b.Metadata))
+	addNode(node, "worker", encodeWorker(&b.Worker))
+	addNode(node, "environment", mapNode(b.Environment))
+
+	templates, err := encodeTemplates(b.Templates)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "templates", templates)
+	addNode(node, "cache", encodeCaches(b.Cache))
+
+	services, err := encodeServices(b.Services)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "services", services)
+
+	stages, err := encodeStages(b.Stages)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "stages", stages)
+
+	steps, err := encodeSteps(b.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "steps", steps)
+
+	secrets, err := encodeSecrets(b.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "secrets", secrets)
+
+	// rewrite the node to the syntax for the version of the pipeline
+	err = upgradeNode(node, b.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// encodeMetadata is a helper function to create
+// the canonical node for the Metadata type.
+func encodeMetadata(m *Metadata) *yamlv3.Node {
+	node := mappingNode()
+
+	addNode(node, "template", boolNode(m.Template))
+	addNode(node, "render_inline", boolNode(m.RenderInline))
+	addNode(node, "clone", boolPtrNode(m.Clone))
+
+	// the documented default is set when the environment is not provided
+	switch {
+	case m.Environment == nil, reflect.DeepEqual(m.Environment, defaultMetadataEnvironment):
+	case len(m.Environment) == 0:
+		addNode(node, "environment", flow(sequenceNode()))
+	default:
+		addNode(node, "environment", sliceNode(m.Environment, true))
+	}
+
+	if m.AutoCancel != nil {
+		cancel := mappingNode()
+
+		addNode(cancel, "running", boolPtrNode(m.AutoCancel.Running))
+		addNode(cancel, "pending", boolPtrNode(m.AutoCancel.Pending))
+		addNode(cancel, "default_branch", boolPtrNode(m.AutoCancel.DefaultBranch))
+
+		addNode(node, "auto_cancel", flowIfEmpty(cancel))
+	}
+
+	return emptyNode(node)
+}
+
+// encodeWorker is a helper function to create
+// the canonical node for the Worker type.
+func encodeWorker(w *Worker) *yamlv3.Node {
+	node := mappingNode()
+
+	addNode(node, "flavor", stringNode(w.Flavor))
+	addNode(node, "platform", stringNode(w.Platform))
+
+	return emptyNode(node)
+}
+
+// encodeTemplates is a helper function to create
+// the canonical node for the TemplateSlice type.
+func encodeTemplates(t TemplateSlice) (*yamlv3.Node, error) {
+	if len(t) == 0 {
+		return nil, nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each template in the slice
+	for _, template := range t {
+		item := mappingNode()
+
+		addNode(item, "name", stringNode(template.Name))
+		addNode(item, "source", stringNode(template.Source))
+		addNode(item, "format", stringNode(template.Format))
+		addNode(item, "type", stringNode(template.Type))
+
+		vars, err := valueNode(template.Variables)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode vars for template %s: %w", template.Name, err)
+		}
+
+		addNode(item, "vars", vars)
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node, nil
+}
+
+// encodeCaches is a helper function to create
+// the canonical node for the CacheSlice type.
+func encodeCaches(c CacheSlice) *yamlv3.Node {
+	if len(c) == 0 {
+		return nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each cache in the slice
+	for _, cache := range c {
+		item := mappingNode()
+
+		addNode(item, "key", stringNode(cache.Key))
+		addNode(item, "paths", sliceNode(cache.Paths, true))
+		addNode(item, "restore_keys", sliceNode(cache.RestoreKeys, false))
+		addNode(item, "scope", stringNode(cache.Scope))
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node
+}
+
+// encodeServices is a helper function to create
+// the canonical node for the ServiceSlice type.
+func encodeServices(s ServiceSlice) (*yamlv3.Node, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each service in the slice
+	for _, service := range s {
+		item := mappingNode()
+
+		addNode(item, "name", stringNode(service.Name))
+		addNode(item, "image", stringNode(service.Image))
+		addNode(item, "pull", pullNode(service.Pull))
+		addNode(item, "environment", mapNode(service.Environment))
+		addNode(item, "entrypoint", sliceNode(service.Entrypoint, false))
+		addNode(item, "ports", sliceNode(service.Ports, false))
+		addNode(item, "ulimits", encodeUlimits(service.Ulimits))
+		addNode(item, "user", stringNode(service.User))
+		addNode(item, "timeout", stringNode(service.Timeout))
+		addNode(item, "retries", intNode(service.Retries))
+		addNode(item, "retry_backoff", encodeRetryBackoff(&service.RetryBackoff))
+		addNode(item, "resources", encodeResources(&service.Resources))
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node, nil
+}
+
+// encodeStages is a helper function to create
+// the canonical node for the StageSlice type.
+func encodeStages(s StageSlice) (*yamlv3.Node, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	node := mappingNode()
+
+	// iterate through each stage in the slice
+	for _, stage := range s {
+		item := mappingNode()
+
+		needs := stage.Needs
+
+		// the clone stage is implicitly added to the end of the needs
+		if stage.Name != "clone" && stage.Name != "init" &&
+			len(needs) > 0 && needs[len(needs)-1] == "clone" && count(needs, "clone") == 1 {
+			needs = needs[:len(needs)-1]
+		}
+
+		addNode(item, "needs", sliceNode(needs, true))
+		addNode(item, "independent", boolNode(stage.Independent))
+		addNode(item, "matrix", encodeMatrix(&stage.Matrix))
+		addNode(item, "environment", mapNode(stage.Environment))
+
+		steps, err := encodeSteps(stage.Steps)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode stage %s: %w", stage.Name, err)
+		}
+
+		addNode(item, "steps", steps)
+
+		addNode(node, stage.Name, item)
+	}
+
+	return node, nil
+}
+
+// encodeSteps is a helper function to create
+// the canonical node for the StepSlice type.
+func encodeSteps(s StepSlice) (*yamlv3.Node, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each step in the slice
+	for _, step := range s {
+		item, err := encodeStep(step)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode step %s: %w", step.Name, err)
+		}
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node, nil
+}
+
+// encodeStep is a helper function to create
+// the canonical node for the Step type.
+func encodeStep(s *Step) (*yamlv3.Node, error) {
+	node := mappingNode()
+
+	addNode(node, "name", stringNode(s.Name))
+	addNode(node, "image", stringNode(s.Image))
+	addNode(node, "pull", pullNode(s.Pull))
+
+	// capture the template for the step
+	if len(s.Template.Name) > 0 || s.Template.Variables != nil {
+		template := mappingNode()
+
+		addNode(template, "name", stringNode(s.Template.Name))
+
+		vars, err := valueNode(s.Template.Variables)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode template vars: %w", err)
+		}
+
+		addNode(template, "vars", vars)
+		addNode(node, "template", template)
+	}
+
+	ruleset, err := encodeRuleset(&s.Ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "ruleset", ruleset)
+	addNode(node, "needs", sliceNode(s.Needs, true))
+	addNode(node, "matrix", encodeMatrix(&s.Matrix))
+	addNode(node, "environment", mapNode(s.Environment))
+	addNode(node, "secrets", encodeStepSecrets(s.Secrets))
+
+	parameters, err := valueNode(s.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode parameters: %w", err)
+	}
+
+	addNode(node, "parameters", parameters)
+	commands := sliceNode(s.Commands, false)
+
+	// an empty list of commands is kept so it is not parsed as nil
+	if commands == nil && s.Commands != nil {
+		commands = flow(sequenceNode())
+	}
+
+	addNode(node, "commands", commands)
+	addNode(node, "entrypoint", sliceNode(s.Entrypoint, false))
+	addNode(node, "detach", boolNode(s.Detach))
+	addNode(node, "privileged", boolNode(s.Privileged))
+	addNode(node, "user", stringNode(s.User))
+	addNode(node, "report_as", stringNode(s.ReportAs))
+	addNode(node, "id_request", stringNode(s.IDRequest))
+	addNode(node, "ulimits", encodeUlimits(s.Ulimits))
+	addNode(node, "volumes", encodeVolumes(s.Volumes))
+	addNode(node, "timeout", stringNode(s.Timeout))
+	addNode(node, "retries", intNode(s.Retries))
+	addNode(node, "retry_backoff", encodeRetryBackoff(&s.RetryBackoff))
+	addNode(node, "resources", encodeResources(&s.Resources))
+	addNode(node, "cache", encodeCaches(s.Cache))
+	addNode(node, "artifacts", encodeArtifacts(s.Artifacts))
+	addNode(node, "consume", sliceNode(s.Consume, true))
+
+	return node, nil
+}
+
+// encodeSecrets is a helper function to create
+// the canonical node for the SecretSlice type.
+func encodeSecrets(s SecretSlice) (*yamlv3.Node, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each secret in the slice
+	for _, secret := range s {
+		item := mappingNode()
+
+		addNode(item, "name", stringNode(secret.Name))
+
+		// defaults are only set for secrets without an origin
+		if !secret.Origin.Empty() {
+			addNode(item, "key", stringNode(secret.Key))
+			addNode(item, "engine", stringNode(secret.Engine))
+			addNode(item, "type", stringNode(secret.Type))
+			addNode(item, "pull", stringNode(secret.Pull))
+
+			origin, err := encodeOrigin(&secret.Origin)
+			if err != nil {
+				return nil, fmt.Errorf("unable to encode secret %s: %w", secret.Name, err)
+			}
+
+			addNode(item, "origin", origin)
+
+			node.Content = append(node.Content, item)
+
+			continue
+		}
+
+		if secret.Key != secret.Name {
+			addNode(item, "key", stringNode(secret.Key))
+		}
+
+		if secret.Engine != constants.DriverNative {
+			addNode(item, "engine", stringNode(secret.Engine))
+		}
+
+		if secret.Type != constants.SecretRepo {
+			addNode(item, "type", stringNode(secret.Type))
+		}
+
+		if secret.Pull != constants.SecretPullBuild {
+			addNode(item, "pull", stringNode(secret.Pull))
+		}
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node, nil
+}
+
+// encodeOrigin is a helper function to create
+// the canonical node for the Origin type.
+func encodeOrigin(o *Origin) (*yamlv3.Node, error) {
+	node := mappingNode()
+
+	addNode(node, "name", stringNode(o.Name))
+	addNode(node, "image", stringNode(o.Image))
+	addNode(node, "pull", pullNode(o.Pull))
+
+	ruleset, err := encodeRuleset(&o.Ruleset)
+	if err != nil {
+		return nil, err
+	}
+
+	addNode(node, "ruleset", ruleset)
+	addNode(node, "environment", mapNode(o.Environment))
+	addNode(node, "secrets", encodeStepSecrets(o.Secrets))
+
+	parameters, err := valueNode(o.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode parameters: %w", err)
+	}
+
+	addNode(node, "parameters", parameters)
+
+	return node, nil
+}
+
+// encodeStepSecrets is a helper function to create
+// the canonical node for the StepSecretSlice type.
+// The short form is used when every secret is
+// injected with the upper case of its name.
+func encodeStepSecrets(s StepSecretSlice) *yamlv3.Node {
+	if len(s) == 0 {
+		return nil
+	}
+
+	short := true
+
+	// verify every secret can be written in the short form
+	for _, secret := range s {
+		if len(secret.Source) == 0 || secret.Target != strings.ToUpper(secret.Source) {
+			short = false
+
+			break
+		}
+	}
+
+	node := sequenceNode()
+
+	// iterate through each secret in the slice
+	for _, secret := range s {
+		if short {
+			node.Content = append(node.Content, scalarNode(secret.Source))
+
+			continue
+		}
+
+		item := mappingNode()
+
+		addNode(item, "source", stringNode(secret.Source))
+		addNode(item, "target", stringNode(secret.Target))
+
+		node.Content = append(node.Content, item)
+	}
+
+	// write the short form on a single line
+	if short {
+		return flow(node)
+	}
+
+	return node
+}
+
+// encodeUlimits is a helper function to create the canonical
+// node for the UlimitSlice type. The short form, i.e.
+// `nofile=1024:2048`, is used when every name allows it.
+func encodeUlimits(u UlimitSlice) *yamlv3.Node {
+	if len(u) == 0 {
+		return nil
+	}
+
+	short := true
+
+	// verify every ulimit can be written in the short form
+	for _, ulimit := range u {
+		if len(ulimit.Name) == 0 || strings.ContainsAny(ulimit.Name, "=:") {
+			short = false
+
+			break
+		}
+	}
+
+	node := sequenceNode()
+
+	// iterate through each ulimit in the slice
+	for _, ulimit := range u {
+		if !short {
+			item := mappingNode()
+
+			addNode(item, "name", stringNode(ulimit.Name))
+			addNode(item, "soft", int64Node(ulimit.Soft))
+			addNode(item, "hard", int64Node(ulimit.Hard))
+
+			node.Content = append(node.Content, item)
+
+			continue
+		}
+
+		value := fmt.Sprintf("%s=%d", ulimit.Name, ulimit.Soft)
+		if ulimit.Hard != ulimit.Soft {
+			value = fmt.Sprintf("%s:%d", value, ulimit.Hard)
+		}
+
+		node.Content = append(node.Content, scalarNode(value))
+	}
+
+	// write the short form on a single line
+	if short {
+		return flow(node)
+	}
+
+	return node
+}
+
+// encodeVolumes is a helper function to create the canonical
+// node for the VolumeSlice type. The short form, i.e.
+// `/foo:/bar:ro`, is used when every volume allows it.
+func encodeVolumes(v VolumeSlice) *yamlv3.Node {
+	if len(v) == 0 {
+		return nil
+	}
+
+	short := true
+
+	// verify every volume can be written in the short form
+	for _, volume := range v {
+		if len(volume.Source) == 0 || len(volume.Destination) == 0 || len(volume.AccessMode) == 0 ||
+			strings.Contains(volume.Source+volume.Destination+volume.AccessMode, ":") {
+			short = false
+
+			break
+		}
+	}
+
+	node := sequenceNode()
+
+	// iterate through each volume in the slice
+	for _, volume := range v {
+		if !short {
+			item := mappingNode()
+
+			addNode(item, "source", stringNode(volume.Source))
+			addNode(item, "destination", stringNode(volume.Destination))
+			addNode(item, "access_mode", stringNode(volume.AccessMode))
+
+			node.Content = append(node.Content, item)
+
+			continue
+		}
+
+		parts := []string{volume.Source, volume.Destination, volume.AccessMode}
+
+		// the access mode defaults to read only and the destination to the source
+		switch {
+		case volume.AccessMode == "ro" && volume.Destination == volume.Source:
+			parts = parts[:1]
+		case volume.AccessMode == "ro":
+			parts = parts[:2]
+		}
+
+		node.Content = append(node.Content, scalarNode(strings.Join(parts, ":")))
+	}
+
+	// write the short form on a single line
+	if short {
+		return flow(node)
+	}
+
+	return node
+}
+
+// encodeMatrix is a helper function to create
+// the canonical node for the Matrix type.
+func encodeMatrix(m *Matrix) *yamlv3.Node {
+	if m.Empty() {
+		return nil
+	}
+
+	node := mappingNode()
+
+	// iterate through each axis in the order they were declared
+	for _, axis := range m.Axes {
+		values := sliceNode(axis.Values, true)
+		if values == nil {
+			values = flow(sequenceNode())
+		}
+
+		addNode(node, axis.Name, values)
+	}
+
+	addNode(node, "include", combinationsNode(m.Include))
+	addNode(node, "exclude", combinationsNode(m.Exclude))
+
+	return node
+}
+
+// encodeRetryBackoff is a helper function to create
+// the canonical node for the RetryBackoff type.
+func encodeRetryBackoff(r *RetryBackoff) *yamlv3.Node {
+	if r.Empty() {
+		return nil
+	}
+
+	node := mappingNode()
+
+	addNode(node, "type", stringNode(r.Type))
+	addNode(node, "delay", stringNode(r.Delay))
+	addNode(node, "max", stringNode(r.Max))
+
+	return node
+}
+
+// encodeResources is a helper function to create
+// the canonical node for the Resources type.
+func encodeResources(r *Resources) *yamlv3.Node {
+	node := mappingNode()
+
+	// iterate through each resource list
+	for _, list := range []struct {
+		key  string
+		list ResourceList
+	}{
+		{key: "requests", list: r.Requests},
+		{key: "limits", list: r.Limits},
+	} {
+		item := mappingNode()
+
+		addNode(item, "cpu", stringNode(list.list.CPU))
+		addNode(item, "memory", stringNode(list.list.Memory))
+
+		addNode(node, list.key, emptyNode(item))
+	}
+
+	return emptyNode(node)
+}
+
+// encodeArtifacts is a helper function to create
+// the canonical node for the ArtifactSlice type.
+func encodeArtifacts(a ArtifactSlice) *yamlv3.Node {
+	if len(a) == 0 {
+		return nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each artifact in the slice
+	for _, artifact := range a {
+		item := mappingNode()
+
+		addNode(item, "name", stringNode(artifact.Name))
+		addNode(item, "paths", sliceNode(artifact.Paths, true))
+		addNode(item, "retention", intNode(artifact.Retention))
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node
+}
+
+// encodeRuleset is a helper function to create the canonical
+// node for the Ruleset type. The `if` rules are written as the
+// simple form of the ruleset when nothing else is provided.
+func encodeRuleset(r *Ruleset) (*yamlv3.Node, error) {
+	node := mappingNode()
+
+	unless := r.Unless.Event != nil || !new(Rules).equal(&r.Unless)
+	matcher := len(r.Matcher) > 0 && r.Matcher != constants.MatcherFilepath
+	operator := len(r.Operator) > 0 && r.Operator != constants.OperatorAnd
+
+	// parsing the simple form leaves the events empty instead of
+	// creating an empty list so the form has to be preserved
+	simple := r.If.Event == nil || len(r.If.Expression) > 0 ||
+		(len(r.If.Event) > 0 && !unless && !matcher && !operator && !r.Continue)
+
+	if simple {
+		encodeRules(node, &r.If)
+
+		addNode(node, "if", stringNode(r.If.Expression))
+	} else {
+		rules := mappingNode()
+
+		encodeRules(rules, &r.If)
+
+		addNode(node, "if", rules)
+	}
+
+	// capture the unless rules for the ruleset
+	if unless {
+		if len(r.Unless.Expression) > 0 {
+			// verify the expression is the only rule
+			if !(&Rules{Expression: r.Unless.Expression}).equal(&r.Unless) {
+				return nil, fmt.Errorf("unable to encode ruleset: unless rules with an expression can not contain other rules")
+			}
+
+			addNode(node, "unless", stringNode(r.Unless.Expression))
+		} else {
+			rules := mappingNode()
+
+			encodeRules(rules, &r.Unless)
+
+			addNode(node, "unless", rules)
+		}
+	}
+
+	if matcher {
+		addNode(node, "matcher", stringNode(r.Matcher))
+	}
+
+	if operator {
+		addNode(node, "operator", stringNode(r.Operator))
+	}
+
+	addNode(node, "continue", boolNode(r.Continue))
+
+	// parsing any ruleset sets the default matcher and operator
+	if len(node.Content) == 0 && len(r.Matcher) == 0 && len(r.Operator) == 0 {
+		return nil, nil
+	}
+
+	return flowIfEmpty(node), nil
+}
+
+// encodeRules is a helper function to add the
+// ruletypes for the Rules type to the provided node.
+func encodeRules(node *yamlv3.Node, r *Rules) {
+	addNode(node, "branch", sliceNode(r.Branch, true))
+	addNode(node, "comment", sliceNode(r.Comment, true))
+	addNode(node, "event", sliceNode(collapseEvents(r.Event), true))
+	addNode(node, "path", sliceNode(r.Path, true))
+	addNode(node, "repo", sliceNode(r.Repo, true))
+	addNode(node, "status", sliceNode(r.Status, true))
+	addNode(node, "tag", sliceNode(r.Tag, true))
+	addNode(node, "target", sliceNode(r.Target, true))
+	addNode(node, "label", sliceNode(r.Label, true))
+	addNode(node, "instance", sliceNode(r.Instance, true))
+	addNode(node, "message", sliceNode(r.Message, true))
+	addNode(node, "author", sliceNode(r.Author, true))
+	addNode(node, "sender", sliceNode(r.Sender, true))
+	addNode(node, "base_ref", sliceNode(r.BaseRef, true))
+	addNode(node, "changed_files", sliceNode(r.ChangedFiles, true))
+
+	if len(r.Env) > 0 {
+		env := mappingNode()
+
+		// iterate through each environment variable in sorted order
+		for _, name := range sortedKeys(r.Env) {
+			patterns := sliceNode(r.Env[name], true)
+			if patterns == nil {
+				patterns = flow(sequenceNode())
+			}
+
+			addNode(env, name, patterns)
+		}
+
+		addNode(node, "env", env)
+	}
+}
+
+// equal is a helper function to verify
+// the provided rules are identical.
+func (r *Rules) equal(other *Rules) bool {
+	a, b := *r, *other
+
+	// events are compared separately since parsing creates an empty list
+	a.Event, b.Event = nil, nil
+
+	return reflect.DeepEqual(a, b) && len(r.Event) == 0 && len(other.Event) == 0
+}
+
+// collapseEvents is a helper function to replace the scoped
+// events that are created when parsing an event, i.e.
+// `pull_request`, with the event they were created from.
+func collapseEvents(events []string) []string {
+	collapsed := []string{}
+
+	// iterate through each event
+	for i := 0; i < len(events); i++ {
+		found := false
+
+		for _, shorthand := range eventShorthands {
+			end := i + len(shorthand.scoped)
+
+			if end <= len(events) && reflect.DeepEqual(events[i:end], shorthand.scoped) {
+				collapsed = append(collapsed, shorthand.event)
+
+				i = end - 1
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			collapsed = append(collapsed, events[i])
+		}
+	}
+
+	return collapsed
+}
+
+// combinationsNode is a helper function to create
+// the node for the include or exclude combinations
+// of a matrix with every combination on a single line.
+func combinationsNode(combinations []map[string]string) *yamlv3.Node {
+	if len(combinations) == 0 {
+		return nil
+	}
+
+	node := sequenceNode()
+
+	// iterate through each combination
+	for _, combination := range combinations {
+		item := flow(mapNode(combination))
+		if item == nil {
+			item = flow(mappingNode())
+		}
+
+		node.Content = append(node.Content, item)
+	}
+
+	return node
+}
+
+// mergeNode is a helper function to merge the canonical node
+// into the provided node from an existing document. The order,
+// comments and style of the existing node are kept while the
+// values are taken from the canonical node.
+func mergeNode(existing, canonical *yamlv3.Node, key string) *yamlv3.Node {
+	// follow aliases and documents to the node with the values
+	existing = resolveNode(existing)
+
+	if existing == nil {
+		return canonical
+	}
+
+	// keep the rules for a ruleset written in the advanced form
+	if key == "ruleset" && hasKey(existing, "if") && !hasKey(canonical, "if") && hasKey(canonical, "event") {
+		canonical = advancedRuleset(canonical)
+	}
+
+	switch {
+	case existing.Kind == yamlv3.ScalarNode && canonical.Kind == yamlv3.ScalarNode:
+		// keep the existing scalar, and the quoting for it, when unchanged
+		if existing.Value == canonical.Value && existing.ShortTag() == canonical.ShortTag() {
+			return existing
+		}
+	case existing.Kind == yamlv3.ScalarNode && canonical.Kind == yamlv3.SequenceNode:
+		// keep a single value written without a list
+		if len(canonical.Content) == 1 && canonical.Content[0].Kind == yamlv3.ScalarNode &&
+			existing.Value == canonical.Content[0].Value && existing.ShortTag() == canonical.Content[0].ShortTag() {
+			return existing
+		}
+	case existing.Kind == yamlv3.SequenceNode && canonical.Kind == yamlv3.MappingNode && key == "environment":
+		// keep environment variables written as a list of `KEY=VALUE`
+		list := sequenceNode()
+
+		for i := 0; i+1 < len(canonical.Content); i += 2 {
+			list.Content = append(list.Content, scalarNode(canonical.Content[i].Value+"="+canonical.Content[i+1].Value))
+		}
+
+		return mergeSequence(existing, list)
+	case existing.Kind == yamlv3.MappingNode && canonical.Kind == yamlv3.MappingNode:
+		return mergeMapping(existing, canonical)
+	case existing.Kind == yamlv3.SequenceNode && canonical.Kind == yamlv3.SequenceNode:
+		return mergeSequence(existing, canonical)
+	}
+
+	copyComments(canonical, existing)
+
+	return canonical
+}
+
+// mergeMapping is a helper function to merge the canonical
+// mapping into the mapping from an existing document.
+func mergeMapping(existing, canonical *yamlv3.Node) *yamlv3.Node {
+	merged := *existing
+	merged.Content = nil
+
+	// capture the index of every canonical key
+	keys := make(map[string]int)
+	for i := 0; i+1 < len(canonical.Content); i += 2 {
+		keys[canonical.Content[i].Value] = i
+	}
+
+	used := make(map[string]bool)
+
+	// iterate through each existing key in order
+	for i := 0; i+1 < len(existing.Content); i += 2 {
+		name := existing.Content[i].Value
+
+		index, ok := keys[name]
+		if !ok || used[name] {
+			// keep a key explicitly set to the default value
+			if !used[name] && isDefault(name, resolveNode(existing.Content[i+1])) {
+				used[name] = true
+
+				merged.Content = append(merged.Content, existing.Content[i], existing.Content[i+1])
+			}
+
+			continue
+		}
+
+		used[name] = true
+
+		merged.Content = append(merged.Content,
+			existing.Content[i],
+			mergeNode(existing.Content[i+1], canonical.Content[index+1], name),
+		)
+	}
+
+	// append the new keys in the canonical order
+	for i := 0; i+1 < len(canonical.Content); i += 2 {
+		if used[canonical.Content[i].Value] {
+			continue
+		}
+
+		merged.Content = append(merged.Content, canonical.Content[i], canonical.Content[i+1])
+	}
+
+	// use the block style when the values no longer fit on a single line
+	if merged.Style&yamlv3.FlowStyle != 0 && !scalars(&merged) {
+		merged.Style &^= yamlv3.FlowStyle
+	}
+
+	return &merged
+}
+
+// mergeSequence is a helper function to merge the canonical
+// sequence into the sequence from an existing document. Items
+// with a name are matched by the name and the remaining items
+// are matched by their position.
+func mergeSequence(existing, canonical *yamlv3.Node) *yamlv3.Node {
+	merged := *existing
+	merged.Content = nil
+
+	used := make([]bool, len(existing.Content))
+
+	// iterate through each canonical item
+	for i, item := range canonical.Content {
+		match := -1
+
+		if name := nodeName(item); len(name) > 0 {
+			for j, candidate := range existing.Content {
+				if !used[j] && nodeName(resolveNode(candidate)) == name {
+					match = j
+
+					break
+				}
+			}
+		} else if i < len(existing.Content) && !used[i] && len(nodeName(resolveNode(existing.Content[i]))) == 0 {
+			match = i
+		}
+
+		if match < 0 {
+			merged.Content = append(merged.Content, item)
+
+			continue
+		}
+
+		used[match] = true
+
+		merged.Content = append(merged.Content, mergeNode(existing.Content[match], item, ""))
+	}
+
+	// use the block style when the items no longer fit on a single line
+	if merged.Style&yamlv3.FlowStyle != 0 && !scalars(&merged) {
+		merged.Style &^= yamlv3.FlowStyle
+	}
+
+	return &merged
+}
+
+// transferComments is a helper function to copy the comments
+// from the node of an existing document to the matching keys
+// and items of the canonical node.
+func transferComments(existing, canonical *yamlv3.Node) {
+	if existing == nil {
+		return
+	}
+
+	copyComments(canonical, existing)
+
+	switch {
+	case existing.Kind == yamlv3.MappingNode && canonical.Kind == yamlv3.MappingNode:
+		// iterate through each canonical key
+		for i := 0; i+1 < len(canonical.Content); i += 2 {
+			for j := 0; j+1 < len(existing.Content); j += 2 {
+				if existing.Content[j].Value != canonical.Content[i].Value {
+					continue
+				}
+
+				copyComments(canonical.Content[i], existing.Content[j])
+				transferComments(existing.Content[j+1], canonical.Content[i+1])
+
+				break
+			}
+		}
+	case existing.Kind == yamlv3.SequenceNode && canonical.Kind == yamlv3.SequenceNode:
+		used := make([]bool, len(existing.Content))
+
+		// iterate through each canonical item
+		for i, item := range canonical.Content {
+			match := -1
+
+			if name := nodeName(item); len(name) > 0 {
+				for j, candidate := range existing.Content {
+					if !used[j] && nodeName(candidate) == name {
+						match = j
+
+						break
+					}
+				}
+			} else if i < len(existing.Content) && !used[i] {
+				match = i
+			}
+
+			if match < 0 {
+				continue
+			}
+
+			used[match] = true
+
+			transferComments(existing.Content[match], item)
+		}
+	}
+}
+
+// hasAlias is a helper function to verify whether the
+// provided node, or any node within it, is an alias.
+func hasAlias(node *yamlv3.Node) bool {
+	if node.Kind == yamlv3.AliasNode {
+		return true
+	}
+
+	for _, item := range node.Content {
+		if hasAlias(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// advancedRuleset is a helper function to move the rules
+// from the simple form of a ruleset to the `if` rules.
+func advancedRuleset(node *yamlv3.Node) *yamlv3.Node {
+	rules := mappingNode()
+	ruleset := mappingNode()
+
+	// iterate through each key of the ruleset
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if rulesetKeys[node.Content[i].Value] {
+			ruleset.Content = append(ruleset.Content, node.Content[i], node.Content[i+1])
+
+			continue
+		}
+
+		rules.Content = append(rules.Content, node.Content[i], node.Content[i+1])
+	}
+
+	ruleset.Content = append([]*yamlv3.Node{scalarNode("if"), rules}, ruleset.Content...)
+
+	return ruleset
+}
+
+// hasKey is a helper function to verify the
+// provided mapping contains the provided key.
+func hasKey(node *yamlv3.Node, key string) bool {
+	node = resolveNode(node)
+
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return false
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDefault is a helper function to verify the provided
+// node contains the value set by default for the key.
+func isDefault(key string, node *yamlv3.Node) bool {
+	if node == nil || node.Kind != yamlv3.ScalarNode {
+		return false
+	}
+
+	for _, value := range defaultValues[key] {
+		if node.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolveNode is a helper function to follow
+// aliases to the node they are referencing.
+func resolveNode(node *yamlv3.Node) *yamlv3.Node {
+	for node != nil && node.Kind == yamlv3.AliasNode {
+		node = node.Alias
+	}
+
+	return node
+}
+
+// nodeName is a helper function to return
+// the value for the name key of a mapping.
+func nodeName(node *yamlv3.Node) string {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return ""
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "name" {
+			return node.Content[i+1].Value
+		}
+	}
+
+	return ""
+}
+
+// copyComments is a helper function to copy the
+// comments from the existing node to the new node.
+func copyComments(node, existing *yamlv3.Node) {
+	node.HeadComment = existing.HeadComment
+	node.LineComment = existing.LineComment
+	node.FootComment = existing.FootComment
+}
+
+// scalars is a helper function to verify every
+// value of the provided node is a scalar.
+func scalars(node *yamlv3.Node) bool {
+	for _, item := range node.Content {
+		if item.Kind != yamlv3.ScalarNode {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flowIfEmpty is a helper function to write an empty
+// mapping as `{}` so it is not parsed as an empty value.
+func flowIfEmpty(node *yamlv3.Node) *yamlv3.Node {
+	if len(node.Content) == 0 {
+		return flow(node)
+	}
+
+	return node
+}
+
+// emptyNode is a helper function to return
+// nothing when the provided node has no values.
+func emptyNode(node *yamlv3.Node) *yamlv3.Node {
+	if len(node.Content) == 0 {
+		return nil
+	}
+
+	return node
+}
+
+// addNode is a helper function to add the key and
+// value to the provided mapping when a value exists.
+func addNode(node *yamlv3.Node, key string, value *yamlv3.Node) {
+	if value == nil {
+		return
+	}
+
+	node.Content = append(node.Content, scalarNode(key), value)
+}
+
+// mappingNode is a helper function to create an empty mapping.
+func mappingNode() *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+}
+
+// sequenceNode is a helper function to create an empty sequence.
+func sequenceNode() *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+}
+
+// flow is a helper function to write the
+// provided node on a single line.
+func flow(node *yamlv3.Node) *yamlv3.Node {
+	if node != nil {
+		node.Style = yamlv3.FlowStyle
+	}
+
+	return node
+}
+
+// scalarNode is a helper function to create a string scalar.
+func scalarNode(value string) *yamlv3.Node {
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// stringNode is a helper function to create a
+// string scalar when the value is not empty.
+func stringNode(value string) *yamlv3.Node {
+	if len(value) == 0 {
+		return nil
+	}
+
+	return scalarNode(value)
+}
+
+// pullNode is a helper function to create the node for
+// a pull policy that is not the default set when parsing.
+func pullNode(pull string) *yamlv3.Node {
+	if pull == constants.PullNotPresent {
+		return nil
+	}
+
+	return stringNode(pull)
+}
+
+// boolNode is a helper function to create
+// a boolean scalar when the value is true.
+func boolNode(value bool) *yamlv3.Node {
+	if !value {
+		return nil
+	}
+
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!bool", Value: "true"}
+}
+
+// boolPtrNode is a helper function to create
+// a boolean scalar when the value is provided.
+func boolPtrNode(value *bool) *yamlv3.Node {
+	if value == nil {
+		return nil
+	}
+
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(*value)}
+}
+
+// intNode is a helper function to create an
+// integer scalar when the value is not zero.
+func intNode(value int) *yamlv3.Node {
+	return int64Node(int64(value))
+}
+
+// int64Node is a helper function to create an
+// integer scalar when the value is not zero.
+func int64Node(value int64) *yamlv3.Node {
+	if value == 0 {
+		return nil
+	}
+
+	return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!int", Value: strconv.FormatInt(value, 10)}
+}
+
+// sliceNode is a helper function to create a
+// sequence of strings when the slice is not empty.
+func sliceNode(values []string, short bool) *yamlv3.Node {
+	if len(values) == 0 {
+		return nil
+	}
+
+	node := sequenceNode()
+
+	for _, value := range values {
+		node.Content = append(node.Content, scalarNode(value))
+	}
+
+	if short {
+		return flow(node)
+	}
+
+	return node
+}
+
+// mapNode is a helper function to create a mapping
+// of strings, in sorted order, when the map exists.
+// An empty map is kept so it is not parsed as nil.
+func mapNode(values map[string]string) *yamlv3.Node {
+	if values == nil {
+		return nil
+	}
+
+	node := mappingNode()
+
+	for _, key := range sortedKeys(values) {
+		addNode(node, key, scalarNode(values[key]))
+	}
+
+	return flowIfEmpty(node)
+}
+
+// valueNode is a helper function to create the node
+// for an arbitrary value when the value exists.
+func valueNode(value interface{}) (*yamlv3.Node, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	// verify the value is not a nil map or slice
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+
+	node := new(yamlv3.Node)
+
+	err := node.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return flowIfEmpty(node), nil
+}
+
+// sortedKeys is a helper function to return
+// the keys for the provided map in sorted order.
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// count is a helper function to return the number
+// of times the value exists in the provided slice.
+func count(values []string, value string) int {
+	total := 0
+
+	for _, v := range values {
+		if v == value {
+			total++
+		}
+	}
+
+	return total
+}
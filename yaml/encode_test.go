@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/buildkite/yaml"
+)
+
+func TestYaml_Encode(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/encode/build.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/encode/build_canonical.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	b := new(Build)
+
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		t.Fatalf("unable to unmarshal yaml: %v", err)
+	}
+
+	// run test
+	got, err := Encode(b)
+	if err != nil {
+		t.Errorf("Encode returned err: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Encode is %s, want %s", got, want)
+	}
+}
+
+func TestYaml_Encode_RoundTrip(t *testing.T) {
+	// setup tests
+	tests := []string{
+		"testdata/build.yml",
+		"testdata/build_anchor_extension.yml",
+		"testdata/build_anchor_stage.yml",
+		"testdata/build_anchor_step.yml",
+		"testdata/build_cache.yml",
+		"testdata/build_empty_env.yml",
+		"testdata/encode/build.yml",
+		"testdata/merge_anchor.yml",
+		"testdata/ruleset_expression.yml",
+	}
+
+	// run tests
+	for _, test := range tests {
+		data, err := os.ReadFile(test)
+		if err != nil {
+			t.Errorf("unable to read file %s: %v", test, err)
+
+			continue
+		}
+
+		want := new(Build)
+
+		err = yaml.Unmarshal(data, want)
+		if err != nil {
+			t.Errorf("unable to unmarshal %s: %v", test, err)
+
+			continue
+		}
+
+		encoded, err := Encode(want)
+		if err != nil {
+			t.Errorf("Encode for %s returned err: %v", test, err)
+
+			continue
+		}
+
+		got := new(Build)
+
+		err = yaml.Unmarshal(encoded, got)
+		if err != nil {
+			t.Errorf("unable to unmarshal encoded %s: %v", test, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Encode for %s is not an identity: %s", test, encoded)
+		}
+
+		// verify encoding the parsed document again produces the same document
+		again, err := Encode(got)
+		if err != nil {
+			t.Errorf("Encode for %s returned err: %v", test, err)
+		}
+
+		if string(again) != string(encoded) {
+			t.Errorf("Encode for %s is %s, want %s", test, again, encoded)
+		}
+	}
+}
+
+func TestYaml_Edit(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/encode/edit.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/encode/edit_want.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	b := new(Build)
+
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		t.Fatalf("unable to unmarshal yaml: %v", err)
+	}
+
+	// update, remove and add steps
+	b.Steps[0].Image = "golang:1.23"
+	b.Steps[0].Environment["GOFLAGS"] = "-mod=vendor"
+	b.Steps[2].Parameters["tags"] = []interface{}{"latest"}
+	b.Steps = append(StepSlice{b.Steps[0], b.Steps[2]}, &Step{
+		Name:    "notify",
+		Image:   "target/vela-slack:latest",
+		Pull:    "not_present",
+		Ruleset: Ruleset{If: Rules{Status: []string{"failure"}}, Matcher: "filepath", Operator: "and"},
+	})
+
+	// run test
+	got, err := Edit(data, b)
+	if err != nil {
+		t.Errorf("Edit returned err: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Edit is %s, want %s", got, want)
+	}
+
+	// verify the edited document produces the build
+	edited := new(Build)
+
+	err = yaml.Unmarshal(got, edited)
+	if err != nil {
+		t.Errorf("unable to unmarshal edited yaml: %v", err)
+	}
+
+	if !reflect.DeepEqual(edited.Steps.ToPipeline(), b.Steps.ToPipeline()) {
+		t.Errorf("Edit steps are %v, want %v", edited.Steps, b.Steps)
+	}
+}
+
+func TestYaml_Edit_Invalid(t *testing.T) {
+	// run test
+	_, err := Edit([]byte("steps: [\n"), new(Build))
+	if err == nil {
+		t.Errorf("Edit should have returned err")
+	}
+}
+
+func TestYaml_Encode_Ruleset(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ruleset Ruleset
+		want    string
+	}{
+		{
+			ruleset: Ruleset{Matcher: "filepath", Operator: "and"},
+			want:    "steps:\n  - name: test\n    ruleset: {}\n",
+		},
+		{
+			ruleset: Ruleset{
+				If:       Rules{Event: []string{"pull_request:opened", "pull_request:synchronize", "pull_request:reopened", "tag"}},
+				Matcher:  "filepath",
+				Operator: "and",
+			},
+			want: "steps:\n  - name: test\n    ruleset:\n      event: [pull_request, tag]\n",
+		},
+		{
+			ruleset: Ruleset{
+				If:       Rules{Event: []string{}},
+				Unless:   Rules{Expression: "branch == 'main'"},
+				Matcher:  "regexp",
+				Operator: "or",
+			},
+			want: "steps:\n  - name: test\n    ruleset:\n      if: {}\n      unless: branch == 'main'\n      matcher: regexp\n      operator: or\n",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		b := &Build{Steps: StepSlice{{Name: "test", Pull: "not_present", Ruleset: test.ruleset}}}
+
+		got, err := Encode(b)
+		if err != nil {
+			t.Errorf("Encode returned err: %v", err)
+		}
+
+		if string(got) != test.want {
+			t.Errorf("Encode is %s, want %s", got, test.want)
+		}
+
+		// verify the ruleset is unchanged when parsing the document
+		parsed := new(Build)
+
+		err = yaml.Unmarshal(got, parsed)
+		if err != nil {
+			t.Errorf("unable to unmarshal yaml: %v", err)
+		}
+
+		if !reflect.DeepEqual(parsed.Steps[0].Ruleset, test.ruleset) {
+			t.Errorf("Ruleset is %v, want %v", parsed.Steps[0].Ruleset, test.ruleset)
+		}
+	}
+
+	// verify unless rules with an expression and ruletypes return an error
+	_, err := Encode(&Build{Steps: StepSlice{{
+		Name:    "test",
+		Ruleset: Ruleset{Unless: Rules{Expression: "branch == 'main'", Branch: []string{"dev"}}},
+	}}})
+	if err == nil {
+		t.Errorf("Encode should have returned err")
+	}
+}
+
+func TestYaml_Format(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/encode/format.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/encode/format_want.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	got, err := Format(data)
+	if err != nil {
+		t.Errorf("Format returned err: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Format is %s, want %s", got, want)
+	}
+
+	// verify formatting is idempotent
+	again, err := Format(got)
+	if err != nil {
+		t.Errorf("Format returned err: %v", err)
+	}
+
+	if string(again) != string(want) {
+		t.Errorf("Format is %s, want %s", again, want)
+	}
+}
+
+func TestYaml_Format_Invalid(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		data string
+		want error
+	}{
+		{data: "steps: [\n"},
+		{data: "steps: foo\n"},
+		{data: "base: &base\n  image: alpine\nsteps:\n  - <<: *base\n    name: test\n", want: ErrFormatAlias},
+	}
+
+	// run tests
+	for _, test := range tests {
+		_, err := Format([]byte(test.data))
+		if err == nil {
+			t.Errorf("Format for %q should have returned err", test.data)
+		}
+
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("Format for %q returned err %v, want %v", test.data, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,563 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/buildkite/yaml"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/go-vela/types/constants"
+)
+
+// funcs returns the functions available to a template. The
+// functions mirror the commonly used functions from the sprig
+// library, with the arguments in the same order, without any
+// access to the environment or file system of the host. The
+// provided limit is the maximum size in bytes for a string
+// produced by a function.
+func funcs(limit int) template.FuncMap {
+	return template.FuncMap{
+		// string functions
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"title":      func(s string) string { return cases.Title(language.English).String(s) },
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, replacement, s string) (string, error) { return fnReplace(old, replacement, s, limit) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"trunc":      fnTrunc,
+		"repeat":     func(count int, s string) (string, error) { return fnRepeat(count, s, limit) },
+		"quote":      fnQuote,
+		"squote":     fnSquote,
+		"indent":     func(spaces int, s string) (string, error) { return fnIndent(spaces, s, limit) },
+		"nindent":    fnNindent(limit),
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       fnJoin,
+		"toString":   fnToString,
+
+		// default functions
+		"default":  fnDefaultValue,
+		"empty":    fnEmpty,
+		"coalesce": fnCoalesce,
+		"ternary":  fnTernary,
+
+		// list and dictionary functions
+		"list":   func(values ...interface{}) []interface{} { return values },
+		"first":  fnFirst,
+		"last":   fnLast,
+		"has":    fnHas,
+		"dict":   fnDict,
+		"keys":   fnKeys,
+		"hasKey": fnHasKey,
+		"get":    fnGet,
+
+		// encoding functions
+		"toJson":       fnToJSON,
+		"toPrettyJson": fnToPrettyJSON,
+		"toYaml":       fnToYAML,
+		"b64enc":       func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec":       fnB64dec,
+
+		// math functions
+		"add": func(a, b interface{}) int64 { return fnToInt64(a) + fnToInt64(b) },
+		"sub": func(a, b interface{}) int64 { return fnToInt64(a) - fnToInt64(b) },
+		"mul": func(a, b interface{}) int64 { return fnToInt64(a) * fnToInt64(b) },
+		"div": fnDiv,
+		"mod": fnMod,
+		"max": func(a interface{}, b ...interface{}) int64 {
+			return fnReduce(func(x, y int64) int64 { return max(x, y) }, a, b...)
+		},
+		"min": func(a interface{}, b ...interface{}) int64 {
+			return fnReduce(func(x, y int64) int64 { return min(x, y) }, a, b...)
+		},
+		"until": fnUntil,
+	}
+}
+
+// fnQuote is a helper function to wrap every
+// provided value in double quotes.
+func fnQuote(values ...interface{}) string {
+	quoted := []string{}
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+
+		quoted = append(quoted, strconv.Quote(fnToString(value)))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// fnSquote is a helper function to wrap every
+// provided value in single quotes.
+func fnSquote(values ...interface{}) string {
+	quoted := []string{}
+
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+
+		quoted = append(quoted, "'"+fnToString(value)+"'")
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// fnTrunc is a helper function to truncate the provided string
+// to the length, or from the end when the length is negative.
+func fnTrunc(length int, s string) string {
+	switch {
+	case length < 0 && -length < len(s):
+		return s[len(s)+length:]
+	case length >= 0 && length < len(s):
+		return s[:length]
+	default:
+		return s
+	}
+}
+
+// fnIndent is a helper function to indent every line
+// of the provided string with the number of spaces. An
+// error is returned when the indentation would exceed
+// the provided size in bytes.
+func fnIndent(spaces int, s string, limit int) (string, error) {
+	spaces = max(spaces, 0)
+	lines := strings.Count(s, "\n")
+
+	if spaces > 0 && lines+1 > limit/spaces {
+		return "", fmt.Errorf("%w: indent of %d lines by %d spaces with a limit of %d", ErrTemplateOutputLimit, lines+1, spaces, limit)
+	}
+
+	pad := strings.Repeat(" ", spaces)
+
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+// fnNindent is a helper function to return a function that
+// indents every line of the provided string with the number
+// of spaces after prepending a newline.
+func fnNindent(limit int) func(int, string) (string, error) {
+	return func(spaces int, s string) (string, error) {
+		indented, err := fnIndent(spaces, s, limit)
+		if err != nil {
+			return "", err
+		}
+
+		return "\n" + indented, nil
+	}
+}
+
+// fnReplace is a helper function to replace every instance of
+// the old string with the replacement in the provided string.
+// An error is returned when the result would exceed the provided
+// size in bytes.
+func fnReplace(old, replacement, s string, limit int) (string, error) {
+	if growth := len(replacement) - len(old); growth > 0 {
+		count := strings.Count(s, old)
+
+		if count > 0 && count > (limit-len(s))/growth {
+			return "", fmt.Errorf("%w: replace of %d instances with %d bytes with a limit of %d", ErrTemplateOutputLimit, count, len(replacement), limit)
+		}
+	}
+
+	return strings.ReplaceAll(s, old, replacement), nil
+}
+
+// fnJoin is a helper function to join the
+// provided list with the separator.
+func fnJoin(sep string, list interface{}) string {
+	values := []string{}
+
+	for _, value := range fnToList(list) {
+		values = append(values, fnToString(value))
+	}
+
+	return strings.Join(values, sep)
+}
+
+// fnToString is a helper function to convert
+// the provided value to a string.
+func fnToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// fnDefaultValue is a helper function to return the given
+// value, or the default value when the given one is empty.
+func fnDefaultValue(d interface{}, given ...interface{}) interface{} {
+	if len(given) == 0 || fnEmpty(given[0]) {
+		return d
+	}
+
+	return given[0]
+}
+
+// fnEmpty is a helper function to return true
+// if the provided value is the zero value.
+func fnEmpty(value interface{}) bool {
+	v := reflect.ValueOf(value)
+
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// fnCoalesce is a helper function to return
+// the first value that is not empty.
+func fnCoalesce(values ...interface{}) interface{} {
+	for _, value := range values {
+		if !fnEmpty(value) {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// fnTernary is a helper function to return the first value
+// when the condition is true or the second one otherwise.
+func fnTernary(vt, vf interface{}, condition bool) interface{} {
+	if condition {
+		return vt
+	}
+
+	return vf
+}
+
+// fnFirst is a helper function to return
+// the first item of the provided list.
+func fnFirst(list interface{}) interface{} {
+	values := fnToList(list)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values[0]
+}
+
+// fnLast is a helper function to return
+// the last item of the provided list.
+func fnLast(list interface{}) interface{} {
+	values := fnToList(list)
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values[len(values)-1]
+}
+
+// fnHas is a helper function to return true if
+// the provided list contains the needle.
+func fnHas(needle, list interface{}) bool {
+	for _, value := range fnToList(list) {
+		if reflect.DeepEqual(value, needle) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fnDict is a helper function to create a dictionary
+// from the provided list of keys and values.
+func fnDict(values ...interface{}) map[string]interface{} {
+	d := make(map[string]interface{})
+
+	for i := 0; i < len(values); i += 2 {
+		var value interface{}
+
+		if i+1 < len(values) {
+			value = values[i+1]
+		}
+
+		d[fnToString(values[i])] = value
+	}
+
+	return d
+}
+
+// fnKeys is a helper function to return the
+// sorted keys of the provided dictionaries.
+func fnKeys(dicts ...interface{}) []string {
+	k := []string{}
+
+	for _, d := range dicts {
+		v := reflect.ValueOf(d)
+
+		if v.Kind() != reflect.Map {
+			continue
+		}
+
+		for _, key := range v.MapKeys() {
+			k = append(k, fnToString(key.Interface()))
+		}
+	}
+
+	sort.Strings(k)
+
+	return k
+}
+
+// fnHasKey is a helper function to return true if
+// the provided dictionary contains the key.
+func fnHasKey(d interface{}, key string) bool {
+	_, ok := fnLookupKey(d, key)
+
+	return ok
+}
+
+// fnGet is a helper function to return the value for the
+// key in the provided dictionary or an empty string.
+func fnGet(d interface{}, key string) interface{} {
+	value, ok := fnLookupKey(d, key)
+	if !ok {
+		return ""
+	}
+
+	return value
+}
+
+// fnLookupKey is a helper function to return the value
+// for the key in the provided dictionary.
+func fnLookupKey(d interface{}, key string) (interface{}, bool) {
+	v := reflect.ValueOf(d)
+
+	if v.Kind() != reflect.Map {
+		return nil, false
+	}
+
+	for _, k := range v.MapKeys() {
+		if fnToString(k.Interface()) == key {
+			return v.MapIndex(k).Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// fnToJSON is a helper function to encode
+// the provided value as a JSON document.
+func fnToJSON(value interface{}) (string, error) {
+	data, err := json.Marshal(fnJSONValue(value))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// fnToPrettyJSON is a helper function to encode the
+// provided value as an indented JSON document.
+func fnToPrettyJSON(value interface{}) (string, error) {
+	data, err := json.MarshalIndent(fnJSONValue(value), "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// fnToYAML is a helper function to encode the provided
+// value as a YAML document without a trailing newline.
+func fnToYAML(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// fnB64dec is a helper function to decode
+// the provided base64 encoded string.
+func fnB64dec(s string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// fnDiv is a helper function to divide the provided values.
+func fnDiv(a, b interface{}) (int64, error) {
+	if fnToInt64(b) == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+
+	return fnToInt64(a) / fnToInt64(b), nil
+}
+
+// fnMod is a helper function to return the
+// remainder from dividing the provided values.
+func fnMod(a, b interface{}) (int64, error) {
+	if fnToInt64(b) == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+
+	return fnToInt64(a) % fnToInt64(b), nil
+}
+
+// fnReduce is a helper function to apply the provided
+// function to the values converted to integers.
+func fnReduce(fn func(a, b int64) int64, a interface{}, b ...interface{}) int64 {
+	result := fnToInt64(a)
+
+	for _, value := range b {
+		result = fn(result, fnToInt64(value))
+	}
+
+	return result
+}
+
+// fnRepeat is a helper function to return the provided string
+// repeated the provided number of times. An error is returned
+// when the result would exceed the provided size in bytes.
+func fnRepeat(count int, s string, limit int) (string, error) {
+	count = max(count, 0)
+
+	if len(s) > 0 && count > limit/len(s) {
+		return "", fmt.Errorf("%w: repeat of %d bytes %d times with a limit of %d", ErrTemplateOutputLimit, len(s), count, limit)
+	}
+
+	return strings.Repeat(s, count), nil
+}
+
+// fnUntil is a helper function to return a list
+// of integers from zero up to the provided count.
+// An error is returned when the count exceeds the
+// maximum for the function.
+func fnUntil(count int) ([]int, error) {
+	if count > constants.TemplateUntilMax {
+		return nil, fmt.Errorf("%w: until %d with a limit of %d", ErrTemplateUntilLimit, count, constants.TemplateUntilMax)
+	}
+
+	list := []int{}
+
+	for i := 0; i < count; i++ {
+		list = append(list, i)
+	}
+
+	return list, nil
+}
+
+// fnToInt64 is a helper function to convert the
+// provided value to an integer, or zero when
+// the value can not be converted.
+func fnToInt64(value interface{}) int64 {
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(v.Float())
+	case reflect.Bool:
+		if v.Bool() {
+			return 1
+		}
+
+		return 0
+	case reflect.String:
+		i, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return 0
+		}
+
+		return i
+	default:
+		return 0
+	}
+}
+
+// fnToList is a helper function to convert the
+// provided value to a list of interfaces.
+func fnToList(value interface{}) []interface{} {
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+
+	list := make([]interface{}, v.Len())
+
+	for i := range list {
+		list[i] = v.Index(i).Interface()
+	}
+
+	return list
+}
+
+// fnJSONValue is a helper function to convert the maps
+// unmarshaled from a YAML document, which may have fnKeys
+// that are not strings, to values that encode as JSON.
+func fnJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			m[fnToString(key)] = fnJSONValue(item)
+		}
+
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+
+		for key, item := range v {
+			m[key] = fnJSONValue(item)
+		}
+
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+
+		for i, item := range v {
+			list[i] = fnJSONValue(item)
+		}
+
+		return list
+	default:
+		return value
+	}
+}
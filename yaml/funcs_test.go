@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/go-vela/types/constants"
+)
+
+func TestYaml_funcs(t *testing.T) {
+	// setup types
+	data := map[string]interface{}{
+		"name":  "vela",
+		"empty": "",
+		"list":  []interface{}{"a", "b", "c"},
+		"map": map[interface{}]interface{}{
+			"foo": "bar",
+			"baz": []interface{}{1, 2},
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{template: `{{ upper .name }}`, want: "VELA"},
+		{template: `{{ "Vela" | lower }}`, want: "vela"},
+		{template: `{{ title "hello world" }}`, want: "Hello World"},
+		{template: `{{ trim "  vela  " }}`, want: "vela"},
+		{template: `{{ trimAll "-" "--vela--" }}`, want: "vela"},
+		{template: `{{ trimPrefix "go-" "go-vela" }}`, want: "vela"},
+		{template: `{{ trimSuffix ".yml" "vela.yml" }}`, want: "vela"},
+		{template: `{{ replace "-" "_" "go-vela-types" }}`, want: "go_vela_types"},
+		{template: `{{ contains "el" .name }}`, want: "true"},
+		{template: `{{ hasPrefix "ve" .name }}`, want: "true"},
+		{template: `{{ hasSuffix "x" .name }}`, want: "false"},
+		{template: `{{ trunc 3 "abcdef" }}`, want: "abc"},
+		{template: `{{ trunc -2 "abcdef" }}`, want: "ef"},
+		{template: `{{ repeat 3 "ab" }}`, want: "ababab"},
+		{template: `{{ quote .name 1 }}`, want: `"vela" "1"`},
+		{template: `{{ squote .name }}`, want: "'vela'"},
+		{template: `{{ indent 2 "a\nb" }}`, want: "  a\n  b"},
+		{template: `{{ nindent 2 "a" }}`, want: "\n  a"},
+		{template: `{{ splitList "," "a,b" | join "-" }}`, want: "a-b"},
+		{template: `{{ join "," .list }}`, want: "a,b,c"},
+		{template: `{{ toString 1 }}`, want: "1"},
+		{template: `{{ .empty | default "fallback" }}`, want: "fallback"},
+		{template: `{{ .name | default "fallback" }}`, want: "vela"},
+		{template: `{{ .missing | default "fallback" }}`, want: "fallback"},
+		{template: `{{ empty .empty }}`, want: "true"},
+		{template: `{{ coalesce .empty .missing .name }}`, want: "vela"},
+		{template: `{{ ternary "yes" "no" true }}`, want: "yes"},
+		{template: `{{ list 1 2 | last }}`, want: "2"},
+		{template: `{{ first .list }}`, want: "a"},
+		{template: `{{ has "b" .list }}`, want: "true"},
+		{template: `{{ get (dict "a" 1 "b" 2) "b" }}`, want: "2"},
+		{template: `{{ keys .map | join "," }}`, want: "baz,foo"},
+		{template: `{{ hasKey .map "foo" }}`, want: "true"},
+		{template: `{{ get .map "missing" }}`, want: ""},
+		{template: `{{ toJson .map }}`, want: `{"baz":[1,2],"foo":"bar"}`},
+		{template: `{{ toPrettyJson .list }}`, want: "[\n  \"a\",\n  \"b\",\n  \"c\"\n]"},
+		{template: `{{ toYaml .list }}`, want: "- a\n- b\n- c"},
+		{template: `{{ b64enc .name }}`, want: "dmVsYQ=="},
+		{template: `{{ b64dec "dmVsYQ==" }}`, want: "vela"},
+		{template: `{{ add 1 "2" }}`, want: "3"},
+		{template: `{{ sub 5 2 }}`, want: "3"},
+		{template: `{{ mul 2 3 }}`, want: "6"},
+		{template: `{{ div 7 2 }}`, want: "3"},
+		{template: `{{ mod 7 2 }}`, want: "1"},
+		{template: `{{ max 1 5 3 }}`, want: "5"},
+		{template: `{{ min 4 2 3 }}`, want: "2"},
+		{template: `{{ range until 3 }}{{ . }}{{ end }}`, want: "012"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		tmpl, err := template.New("test").Funcs(funcs(constants.TemplateOutputLimitDefault)).Parse(test.template)
+		if err != nil {
+			t.Errorf("unable to parse %s: %v", test.template, err)
+
+			continue
+		}
+
+		got := new(strings.Builder)
+
+		err = tmpl.Execute(got, data)
+		if err != nil {
+			t.Errorf("unable to execute %s: %v", test.template, err)
+
+			continue
+		}
+
+		if got.String() != test.want {
+			t.Errorf("%s is %q, want %q", test.template, got.String(), test.want)
+		}
+	}
+}
+
+func TestYaml_funcs_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		template string
+		want     error
+	}{
+		{template: `{{ div 1 0 }}`},
+		{template: `{{ mod 1 0 }}`},
+		{template: `{{ b64dec "!" }}`},
+		{template: `{{ repeat 1048577 "a" }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ repeat 9223372036854775807 "ab" }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ indent 1048577 "a" }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ repeat 1024 "a\n" | indent 1024 }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ nindent 9223372036854775807 "a" }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ repeat 1025 "a" | replace "a" (repeat 1024 "b") }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ replace "" (repeat 1024 "b") (repeat 1024 "a") }}`, want: ErrTemplateOutputLimit},
+		{template: `{{ range until 10001 }}{{ end }}`, want: ErrTemplateUntilLimit},
+	}
+
+	// run tests
+	for _, test := range tests {
+		tmpl, err := template.New("test").Funcs(funcs(constants.TemplateOutputLimitDefault)).Parse(test.template)
+		if err != nil {
+			t.Errorf("unable to parse %s: %v", test.template, err)
+
+			continue
+		}
+
+		err = tmpl.Execute(new(strings.Builder), nil)
+		if err == nil {
+			t.Errorf("%s should have returned err", test.template)
+
+			continue
+		}
+
+		if test.want != nil && !errors.Is(err, test.want) {
+			t.Errorf("%s returned err %v, want %v", test.template, err, test.want)
+		}
+	}
+}
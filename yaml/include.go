@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/raw"
+)
+
+var (
+	// ErrIncludeCycle defines the error type when
+	// a document includes itself, directly or
+	// through another included document.
+	ErrIncludeCycle = errors.New("include cycle detected")
+
+	// ErrIncludeConflict defines the error type when the
+	// included documents can not be merged into a pipeline.
+	ErrIncludeConflict = errors.New("include conflict")
+)
+
+// Loader is the interface for reading the
+// documents included by a pipeline.
+type Loader interface {
+	// Load returns the raw YAML document for the provided
+	// slash-separated path relative to the repository root.
+	Load(name string) ([]byte, error)
+}
+
+// fsLoader is a Loader reading documents from a file system.
+type fsLoader struct {
+	fsys fs.FS
+}
+
+// FSLoader returns a Loader reading the
+// documents from the provided file system.
+func FSLoader(fsys fs.FS) Loader {
+	return &fsLoader{fsys: fsys}
+}
+
+// Load returns the raw YAML document for the provided path.
+func (l *fsLoader) Load(name string) ([]byte, error) {
+	return fs.ReadFile(l.fsys, name)
+}
+
+// includer represents the state for merging
+// the documents included by a pipeline.
+type includer struct {
+	loader Loader
+	build  *Build
+	// documents already merged into the build
+	seen map[string]bool
+	// document declaring every step and stage name
+	steps  map[string]string
+	stages map[string]string
+}
+
+// LoadBuild reads the document for the provided path with the
+// loader, unmarshals it to a Build type and merges the documents
+// it includes into the build.
+func LoadBuild(loader Loader, name string) (*Build, error) {
+	data, err := loader.Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load %s: %w", name, err)
+	}
+
+	b := new(Build)
+
+	// attempt to unmarshal the document as a build type
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal %s: %w", name, err)
+	}
+
+	err = b.ResolveIncludes(loader, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ResolveIncludes merges the documents included by the Build type,
+// read from the provided path, into the build. Paths in the include
+// block are relative to the directory of the document including them
+// or, when they start with a slash, relative to the repository root.
+//
+// The environment, services, secrets, stages and steps are merged
+// from every included document, in order, before the ones from the
+// document including them. An environment variable, service or secret
+// with the same name replaces the one merged before it. Steps or stages
+// with the same name in different documents, or a mix of stages and
+// steps, are reported as an error wrapping ErrIncludeConflict. Every
+// other key is only read from the document including the others.
+func (b *Build) ResolveIncludes(loader Loader, name string) error {
+	// skip the build when it does not include any documents
+	if len(b.Include) == 0 {
+		return nil
+	}
+
+	name = path.Clean(name)
+
+	i := &includer{
+		loader: loader,
+		build:  new(Build),
+		seen:   map[string]bool{name: true},
+		steps:  make(map[string]string),
+		stages: make(map[string]string),
+	}
+
+	err := i.resolve(b, name, []string{name})
+	if err != nil {
+		return err
+	}
+
+	// override the values with the merged documents
+	b.Include = nil
+	b.Environment = i.build.Environment
+	b.Secrets = i.build.Secrets
+	b.Services = i.build.Services
+	b.Stages = i.build.Stages
+	b.Steps = i.build.Steps
+
+	return nil
+}
+
+// resolve is a helper function to merge the documents included by
+// the provided build, followed by the build itself, into the result.
+func (i *includer) resolve(b *Build, name string, stack []string) error {
+	// iterate through each document included by the build
+	for _, include := range b.Include {
+		file, err := includePath(name, include)
+		if err != nil {
+			return err
+		}
+
+		// verify the document is not including itself
+		if slices.Contains(stack, file) {
+			return fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, file), " -> "))
+		}
+
+		// skip documents included more than once
+		if i.seen[file] {
+			continue
+		}
+
+		i.seen[file] = true
+
+		data, err := i.loader.Load(file)
+		if err != nil {
+			return fmt.Errorf("unable to load include %s from %s: %w", file, name, err)
+		}
+
+		included := new(Build)
+
+		// attempt to unmarshal the document as a build type
+		err = yaml.Unmarshal(data, included)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal include %s: %w", file, err)
+		}
+
+		err = i.resolve(included, file, append(slices.Clone(stack), file))
+		if err != nil {
+			return err
+		}
+	}
+
+	return i.merge(b, name)
+}
+
+// merge is a helper function to merge the provided
+// build, read from the provided path, into the result.
+func (i *includer) merge(b *Build, name string) error {
+	result := i.build
+
+	// merge the environment variables
+	if b.Environment != nil {
+		if result.Environment == nil {
+			result.Environment = make(raw.StringSliceMap)
+		}
+
+		for key, value := range b.Environment {
+			result.Environment[key] = value
+		}
+	}
+
+	// merge the services replacing those with the same name
+	for _, service := range b.Services {
+		index := slices.IndexFunc(result.Services, func(s *Service) bool {
+			return s.Name == service.Name
+		})
+
+		if index < 0 {
+			result.Services = append(result.Services, service)
+
+			continue
+		}
+
+		result.Services[index] = service
+	}
+
+	// merge the secrets replacing those with the same name
+	for _, secret := range b.Secrets {
+		index := slices.IndexFunc(result.Secrets, func(s *Secret) bool {
+			return secretName(s) == secretName(secret)
+		})
+
+		if index < 0 {
+			result.Secrets = append(result.Secrets, secret)
+
+			continue
+		}
+
+		result.Secrets[index] = secret
+	}
+
+	// verify stages and steps are not both provided
+	if len(b.Stages) > 0 && len(result.Steps) > 0 || len(b.Steps) > 0 && len(result.Stages) > 0 {
+		return fmt.Errorf("%w: cannot have both stages and steps at the top level of pipeline (found in %s)", ErrIncludeConflict, name)
+	}
+
+	// merge the stages verifying the names are unique
+	for _, stage := range b.Stages {
+		if first, ok := i.stages[stage.Name]; ok && first != name {
+			return fmt.Errorf("%w: stage %s is declared in %s and %s", ErrIncludeConflict, stage.Name, first, name)
+		}
+
+		i.stages[stage.Name] = name
+
+		result.Stages = append(result.Stages, stage)
+	}
+
+	// merge the steps verifying the names are unique
+	for _, step := range b.Steps {
+		if first, ok := i.steps[step.Name]; ok && first != name {
+			return fmt.Errorf("%w: step %s is declared in %s and %s", ErrIncludeConflict, step.Name, first, name)
+		}
+
+		i.steps[step.Name] = name
+
+		result.Steps = append(result.Steps, step)
+	}
+
+	return nil
+}
+
+// includePath is a helper function to return the path for the
+// provided include relative to the repository root.
+func includePath(name, include string) (string, error) {
+	file := path.Join(path.Dir(name), include)
+
+	// paths starting with a slash are relative to the repository root
+	if strings.HasPrefix(include, "/") {
+		file = path.Clean(strings.TrimLeft(include, "/"))
+	}
+
+	// verify the path does not leave the repository
+	if len(include) == 0 || !fs.ValidPath(file) {
+		return "", fmt.Errorf("invalid include %q in %s", include, name)
+	}
+
+	return file, nil
+}
+
+// secretName is a helper function to return the name identifying
+// the provided secret, which is the name of the origin for a
+// secret provided by an origin without a name.
+func secretName(s *Secret) string {
+	if len(s.Name) == 0 && !s.Origin.Empty() {
+		return "origin:" + s.Origin.Name
+	}
+
+	return s.Name
+}
@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-vela/types/raw"
+)
+
+func TestYaml_LoadBuild(t *testing.T) {
+	// setup types
+	fsys := fstest.MapFS{
+		".vela.yml": {Data: []byte(`
+version: "1"
+
+include:
+  - .vela/test.yml
+  - /.vela/services.yml
+
+environment:
+  GOOS: linux
+
+secrets:
+  - name: docker_password
+    key: org/repo/docker/password
+
+steps:
+  - name: publish
+    image: target/vela-docker:latest
+    secrets: [ docker_password ]
+`)},
+		".vela/test.yml": {Data: []byte(`
+version: "1"
+
+include: common.yml
+
+environment:
+  CGO_ENABLED: "1"
+
+steps:
+  - name: test
+    image: golang:1.23
+    commands:
+      - go test ./...
+`)},
+		".vela/services.yml": {Data: []byte(`
+version: "1"
+
+include: [ common.yml ]
+
+services:
+  - name: redis
+    image: redis:7
+`)},
+		".vela/common.yml": {Data: []byte(`
+version: "1"
+
+environment:
+  GOOS: darwin
+  GOARCH: amd64
+
+services:
+  - name: redis
+    image: redis:6
+
+secrets:
+  - name: docker_password
+    key: org/docker/password
+    type: org
+`)},
+	}
+
+	want := raw.StringSliceMap{
+		"CGO_ENABLED": "1",
+		"GOARCH":      "amd64",
+		"GOOS":        "linux",
+	}
+
+	// run test
+	got, err := LoadBuild(FSLoader(fsys), ".vela.yml")
+	if err != nil {
+		t.Fatalf("LoadBuild returned err: %v", err)
+	}
+
+	if len(got.Include) > 0 {
+		t.Errorf("LoadBuild include is %v, want empty", got.Include)
+	}
+
+	if !reflect.DeepEqual(got.Environment, want) {
+		t.Errorf("LoadBuild environment is %v, want %v", got.Environment, want)
+	}
+
+	steps := []string{}
+	for _, step := range got.Steps {
+		steps = append(steps, step.Name)
+	}
+
+	if !reflect.DeepEqual(steps, []string{"test", "publish"}) {
+		t.Errorf("LoadBuild steps are %v, want %v", steps, []string{"test", "publish"})
+	}
+
+	if len(got.Services) != 1 || got.Services[0].Image != "redis:7" {
+		t.Errorf("LoadBuild services are %v, want redis:7 only", got.Services)
+	}
+
+	if len(got.Secrets) != 1 || got.Secrets[0].Key != "org/repo/docker/password" {
+		t.Errorf("LoadBuild secrets are %v, want org/repo/docker/password only", got.Secrets)
+	}
+}
+
+func TestYaml_LoadBuild_Stages(t *testing.T) {
+	// setup types
+	fsys := fstest.MapFS{
+		".vela.yml": {Data: []byte(`
+version: "1"
+
+include: [ .vela/build.yml ]
+
+stages:
+  test:
+    steps:
+      - name: test
+        image: golang:1.23
+`)},
+		".vela/build.yml": {Data: []byte(`
+version: "1"
+
+stages:
+  build:
+    steps:
+      - name: test
+        image: golang:1.23
+`)},
+	}
+
+	// run test
+	got, err := LoadBuild(FSLoader(fsys), ".vela.yml")
+	if err != nil {
+		t.Fatalf("LoadBuild returned err: %v", err)
+	}
+
+	stages := []string{}
+	for _, stage := range got.Stages {
+		stages = append(stages, stage.Name)
+	}
+
+	if !reflect.DeepEqual(stages, []string{"build", "test"}) {
+		t.Errorf("LoadBuild stages are %v, want %v", stages, []string{"build", "test"})
+	}
+}
+
+func TestYaml_LoadBuild_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want error
+	}{
+		{
+			name: "duplicate step",
+			fsys: fstest.MapFS{
+				".vela.yml":      {Data: []byte("include: [ .vela/test.yml ]\nsteps:\n  - name: test\n    image: alpine\n")},
+				".vela/test.yml": {Data: []byte("steps:\n  - name: test\n    image: golang\n")},
+			},
+			want: ErrIncludeConflict,
+		},
+		{
+			name: "duplicate stage",
+			fsys: fstest.MapFS{
+				".vela.yml":      {Data: []byte("include: [ .vela/test.yml ]\nstages:\n  test:\n    steps:\n      - name: test\n        image: alpine\n")},
+				".vela/test.yml": {Data: []byte("stages:\n  test:\n    steps:\n      - name: test\n        image: golang\n")},
+			},
+			want: ErrIncludeConflict,
+		},
+		{
+			name: "mixed steps and stages",
+			fsys: fstest.MapFS{
+				".vela.yml":      {Data: []byte("include: [ .vela/test.yml ]\nsteps:\n  - name: test\n    image: alpine\n")},
+				".vela/test.yml": {Data: []byte("stages:\n  test:\n    steps:\n      - name: test\n        image: golang\n")},
+			},
+			want: ErrIncludeConflict,
+		},
+		{
+			name: "cycle",
+			fsys: fstest.MapFS{
+				".vela.yml":   {Data: []byte("include: [ .vela/a.yml ]\n")},
+				".vela/a.yml": {Data: []byte("include: [ b.yml ]\n")},
+				".vela/b.yml": {Data: []byte("include: [ /.vela.yml ]\n")},
+			},
+			want: ErrIncludeCycle,
+		},
+		{
+			name: "missing",
+			fsys: fstest.MapFS{
+				".vela.yml": {Data: []byte("include: [ .vela/missing.yml ]\n")},
+			},
+			want: fs.ErrNotExist,
+		},
+		{
+			name: "outside repository",
+			fsys: fstest.MapFS{
+				".vela.yml": {Data: []byte("include: [ ../secrets.yml ]\n")},
+			},
+		},
+		{
+			name: "invalid",
+			fsys: fstest.MapFS{
+				".vela.yml":      {Data: []byte("include: [ .vela/test.yml ]\n")},
+				".vela/test.yml": {Data: []byte("steps: [ foo")},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadBuild(FSLoader(test.fsys), ".vela.yml")
+			if err == nil {
+				t.Errorf("LoadBuild should have returned err")
+			}
+
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("LoadBuild returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestYaml_Build_ResolveIncludes_Empty(t *testing.T) {
+	// setup types
+	b := &Build{Steps: StepSlice{{Name: "test", Image: "alpine"}}}
+
+	// run test
+	err := b.ResolveIncludes(FSLoader(fstest.MapFS{}), ".vela.yml")
+	if err != nil {
+		t.Errorf("ResolveIncludes returned err: %v", err)
+	}
+
+	if len(b.Steps) != 1 {
+		t.Errorf("ResolveIncludes steps are %v, want 1 step", b.Steps)
+	}
+}
@@ -0,0 +1,440 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+	"github.com/go-vela/types/raw"
+)
+
+type (
+	// Matrix is the yaml representation of the matrix
+	// block for a step or stage in a pipeline.
+	//
+	// Deprecated: use Matrix from github.com/go-vela/server/compiler/types/yaml instead.
+	Matrix struct {
+		Axes    MatrixAxisSlice     `yaml:"-"                 json:"axes,omitempty" jsonschema:"description=Values for every axis of the matrix.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-matrix-key"`
+		Include []map[string]string `yaml:"include,omitempty" json:"include,omitempty" jsonschema:"description=Extra combinations to add to the matrix.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-matrix-key"`
+		Exclude []map[string]string `yaml:"exclude,omitempty" json:"exclude,omitempty" jsonschema:"description=Combinations to remove from the matrix.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-matrix-key"`
+	}
+
+	// MatrixAxisSlice is the yaml representation
+	// of the axes for a matrix in a pipeline.
+	//
+	// Deprecated: use MatrixAxisSlice from github.com/go-vela/server/compiler/types/yaml instead.
+	MatrixAxisSlice []*MatrixAxis
+
+	// MatrixAxis is the yaml representation of
+	// an axis for a matrix in a pipeline.
+	//
+	// Deprecated: use MatrixAxis from github.com/go-vela/server/compiler/types/yaml instead.
+	MatrixAxis struct {
+		Name   string          `json:"name,omitempty"`
+		Values raw.StringSlice `json:"values,omitempty"`
+	}
+)
+
+// Empty returns true if the provided matrix is empty.
+func (m *Matrix) Empty() bool {
+	// return true if the matrix is nil
+	if m == nil {
+		return true
+	}
+
+	// return true if every matrix field is empty
+	if len(m.Axes) == 0 &&
+		len(m.Include) == 0 &&
+		len(m.Exclude) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// Combinations returns every combination of values for the matrix.
+// The combinations are created in the order the axes were declared,
+// with the values of the last axis changing the fastest. Any
+// combination matching an exclude entry is removed. An include
+// entry matching every axis of a combination adds its extra values
+// to the combination, otherwise it is added as a new combination.
+// An include entry without any axis adds its values to every
+// combination. At most MatrixCombinationsMax combinations are
+// returned, and a matrix exceeding the limit is reported when
+// validating the pipeline.
+func (m *Matrix) Combinations() []map[string]string {
+	combinations, _ := m.expand()
+
+	return combinations
+}
+
+// expand is a helper function to create the combinations for the
+// matrix, stopping at the limit for the combinations of a matrix,
+// and report whether the matrix exceeds the limit.
+func (m *Matrix) expand() ([]map[string]string, bool) {
+	combinations := []map[string]string{}
+
+	// return no combinations if the matrix is empty
+	if m.Empty() {
+		return combinations, false
+	}
+
+	size := m.size()
+	exceeded := size > constants.MatrixCombinationsMax
+
+	// create the product of every axis up to the limit
+	for i := 0; i < min(size, constants.MatrixCombinationsMax); i++ {
+		combination := make(map[string]string)
+
+		// the values of the last axis change the fastest
+		index := i
+
+		for j := len(m.Axes) - 1; j >= 0; j-- {
+			axis := m.Axes[j]
+
+			combination[axis.Name] = axis.Values[index%len(axis.Values)]
+			index /= len(axis.Values)
+		}
+
+		combinations = append(combinations, combination)
+	}
+
+	// remove every combination matching an exclude entry
+	filtered := []map[string]string{}
+
+	for _, combination := range combinations {
+		excluded := false
+
+		for _, exclude := range m.Exclude {
+			if matchCombination(combination, exclude) {
+				excluded = true
+
+				break
+			}
+		}
+
+		if !excluded {
+			filtered = append(filtered, combination)
+		}
+	}
+
+	combinations = filtered
+
+	// add the values from every include entry
+	for _, include := range m.Include {
+		merged := false
+
+		// capture the values for every axis in the include
+		values := m.axisValues(include)
+
+		for _, combination := range combinations {
+			// only merge into combinations matching every axis in the include
+			if !matchCombination(combination, values) {
+				continue
+			}
+
+			for k, v := range include {
+				combination[k] = v
+			}
+
+			merged = true
+		}
+
+		// an include without any axis only adds values to the combinations
+		if !merged && (len(values) > 0 || len(m.Axes) == 0) {
+			combination := make(map[string]string)
+
+			for k, v := range include {
+				combination[k] = v
+			}
+
+			combinations = append(combinations, combination)
+		}
+	}
+
+	// verify the included combinations are within the limit
+	if len(combinations) > constants.MatrixCombinationsMax {
+		return combinations[:constants.MatrixCombinationsMax], true
+	}
+
+	return combinations, exceeded
+}
+
+// Name returns the unique name for the provided
+// combination by appending the values for every
+// axis, in the order they were declared, to the
+// provided name. Values for keys that are not an
+// axis of the matrix are appended in sorted order.
+func (m *Matrix) Name(name string, combination map[string]string) string {
+	parts := []string{name}
+
+	// capture the value for every axis
+	for _, key := range m.keys(combination) {
+		parts = append(parts, combination[key])
+	}
+
+	return strings.Join(parts, "_")
+}
+
+// Environment returns the environment variables
+// to inject for the provided combination.
+func (m *Matrix) Environment(combination map[string]string) map[string]string {
+	env := make(map[string]string)
+
+	for key, value := range combination {
+		env[pipeline.MatrixEnv(key)] = value
+	}
+
+	return env
+}
+
+// UnmarshalYAML implements the Unmarshaler interface for the Matrix type.
+func (m *Matrix) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// map slice we try unmarshalling to
+	mapSlice := new(yaml.MapSlice)
+
+	// attempt to unmarshal as a map slice type
+	err := unmarshal(mapSlice)
+	if err != nil {
+		return err
+	}
+
+	// iterate through each element in the map slice
+	for _, item := range *mapSlice {
+		key := fmt.Sprintf("%v", item.Key)
+
+		// marshal interface value from ordered map
+		out, _ := yaml.Marshal(item.Value)
+
+		switch key {
+		case "include":
+			err = yaml.Unmarshal(out, &m.Include)
+		case "exclude":
+			err = yaml.Unmarshal(out, &m.Exclude)
+		default:
+			axis := &MatrixAxis{Name: key}
+
+			err = yaml.Unmarshal(out, &axis.Values)
+
+			m.Axes = append(m.Axes, axis)
+		}
+
+		if err != nil {
+			return fmt.Errorf("invalid matrix key %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// MarshalYAML implements the marshaler interface for the Matrix type.
+func (m Matrix) MarshalYAML() (interface{}, error) {
+	// map slice to return as marshaled output
+	output := yaml.MapSlice{}
+
+	for _, axis := range m.Axes {
+		output = append(output, yaml.MapItem{Key: axis.Name, Value: []string(axis.Values)})
+	}
+
+	if len(m.Include) > 0 {
+		output = append(output, yaml.MapItem{Key: "include", Value: m.Include})
+	}
+
+	if len(m.Exclude) > 0 {
+		output = append(output, yaml.MapItem{Key: "exclude", Value: m.Exclude})
+	}
+
+	return output, nil
+}
+
+// validate is a helper function to verify the yaml for the Matrix
+// type, for the step or stage with the provided name, with paths
+// relative to the provided path.
+func (m *Matrix) validate(path, name string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// iterate through each axis of the matrix
+	for _, axis := range m.Axes {
+		// verify values were provided for the axis
+		if len(axis.Values) == 0 {
+			diagnostics.errorf(joinPath(path, axis.Name), "no values provided for matrix axis %s", axis.Name)
+		}
+	}
+
+	// iterate through each exclude entry of the matrix
+	for i, exclude := range m.Exclude {
+		for _, key := range m.keys(exclude) {
+			// verify the exclude entry only references axes
+			if !m.isAxis(key) {
+				diagnostics.errorf(indexPath(joinPath(path, "exclude"), i), "unknown matrix axis %s", key)
+			}
+		}
+	}
+
+	// verify the number of combinations is within the limit
+	combinations, exceeded := m.expand()
+	if exceeded {
+		diagnostics.errorf(path, "matrix combinations exceed the limit of %d", constants.MatrixCombinationsMax)
+
+		return diagnostics
+	}
+
+	names := make(map[string]bool)
+
+	// verify every combination produces a unique name
+	for _, combination := range combinations {
+		expanded := m.Name(name, combination)
+
+		if names[expanded] {
+			diagnostics.errorf(path, "duplicate name %s produced by matrix combinations", expanded)
+		}
+
+		names[expanded] = true
+	}
+
+	return diagnostics
+}
+
+// size is a helper function to return the number of combinations
+// for the product of the axes without creating them. Counting stops
+// once the number exceeds the limit for the combinations of a matrix.
+func (m *Matrix) size() int {
+	if len(m.Axes) == 0 {
+		return 0
+	}
+
+	// the product is empty when any axis has no values
+	for _, axis := range m.Axes {
+		if len(axis.Values) == 0 {
+			return 0
+		}
+	}
+
+	size := 1
+
+	for _, axis := range m.Axes {
+		size *= len(axis.Values)
+
+		if size > constants.MatrixCombinationsMax {
+			return size
+		}
+	}
+
+	return size
+}
+
+// axisValues is a helper function to return the
+// values from the provided entry for every axis.
+func (m *Matrix) axisValues(entry map[string]string) map[string]string {
+	values := make(map[string]string)
+
+	for key, value := range entry {
+		if m.isAxis(key) {
+			values[key] = value
+		}
+	}
+
+	return values
+}
+
+// isAxis is a helper function to check if the
+// provided key is an axis of the matrix.
+func (m *Matrix) isAxis(key string) bool {
+	for _, axis := range m.Axes {
+		if axis.Name == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// keys is a helper function to return the keys for the provided
+// combination with the axes first, in the order they were declared,
+// followed by any other keys in sorted order.
+func (m *Matrix) keys(combination map[string]string) []string {
+	keys := []string{}
+
+	for _, axis := range m.Axes {
+		if _, ok := combination[axis.Name]; ok {
+			keys = append(keys, axis.Name)
+		}
+	}
+
+	extra := []string{}
+
+	for key := range combination {
+		if !m.isAxis(key) {
+			extra = append(extra, key)
+		}
+	}
+
+	sort.Strings(extra)
+
+	return append(keys, extra...)
+}
+
+// matchCombination is a helper function to check if every
+// value in the provided entry matches the combination.
+func matchCombination(combination, entry map[string]string) bool {
+	for key, value := range entry {
+		if combination[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// expandNeeds is a helper function to replace every need in
+// the provided list with the names it was expanded to.
+func expandNeeds(needs []string, expanded map[string][]string) []string {
+	// return the needs unchanged when nothing was expanded
+	if len(needs) == 0 || len(expanded) == 0 {
+		return needs
+	}
+
+	result := []string{}
+
+	for _, need := range needs {
+		if names, ok := expanded[need]; ok {
+			result = append(result, names...)
+
+			continue
+		}
+
+		result = append(result, need)
+	}
+
+	return result
+}
+
+// mergeEnvironment is a helper function to create a new
+// environment from the provided environments, where the
+// values from the later environments take precedence.
+func mergeEnvironment(environments ...map[string]string) map[string]string {
+	var env map[string]string
+
+	for _, environment := range environments {
+		// skip empty environments
+		if environment == nil {
+			continue
+		}
+
+		if env == nil {
+			env = make(map[string]string)
+		}
+
+		for key, value := range environment {
+			env[key] = value
+		}
+	}
+
+	return env
+}
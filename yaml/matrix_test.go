@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+)
+
+func TestYaml_Matrix_Combinations(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		matrix *Matrix
+		want   []map[string]string
+	}{
+		{
+			name:   "empty",
+			matrix: new(Matrix),
+			want:   []map[string]string{},
+		},
+		{
+			name: "product",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "go", Values: []string{"1.21", "1.22"}},
+					{Name: "os", Values: []string{"linux", "darwin"}},
+				},
+			},
+			want: []map[string]string{
+				{"go": "1.21", "os": "linux"},
+				{"go": "1.21", "os": "darwin"},
+				{"go": "1.22", "os": "linux"},
+				{"go": "1.22", "os": "darwin"},
+			},
+		},
+		{
+			name: "include without axis",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "go", Values: []string{"1.21", "1.22"}},
+				},
+				Include: []map[string]string{
+					{"experimental": "true"},
+				},
+			},
+			want: []map[string]string{
+				{"go": "1.21", "experimental": "true"},
+				{"go": "1.22", "experimental": "true"},
+			},
+		},
+		{
+			name: "include only",
+			matrix: &Matrix{
+				Include: []map[string]string{
+					{"go": "1.23"},
+				},
+			},
+			want: []map[string]string{
+				{"go": "1.23"},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.matrix.Combinations()
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Combinations for %s is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestYaml_Matrix_Combinations_Limit(t *testing.T) {
+	// setup types
+	values := []string{}
+
+	for i := 0; i < 16; i++ {
+		values = append(values, strconv.Itoa(i))
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		matrix *Matrix
+	}{
+		{
+			name: "axes",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "a", Values: values},
+					{Name: "b", Values: values},
+					{Name: "c", Values: values},
+				},
+			},
+		},
+		{
+			name: "include",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "a", Values: values},
+					{Name: "b", Values: values},
+				},
+				Include: []map[string]string{
+					{"a": "16", "b": "16"},
+				},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.matrix.Combinations()
+		if len(got) != constants.MatrixCombinationsMax {
+			t.Errorf("Combinations for %s returned %d combinations, want %d", test.name, len(got), constants.MatrixCombinationsMax)
+		}
+
+		steps := &StepSlice{{Name: "test", Image: "alpine", Matrix: *test.matrix}}
+
+		containers := steps.ToPipeline()
+		if len(*containers) != constants.MatrixCombinationsMax {
+			t.Errorf("ToPipeline for %s returned %d steps, want %d", test.name, len(*containers), constants.MatrixCombinationsMax)
+		}
+
+		stages := &StageSlice{{Name: "test", Matrix: *test.matrix, Steps: StepSlice{{Name: "test", Image: "alpine"}}}}
+
+		expanded := stages.ToPipeline()
+		if len(*expanded) != constants.MatrixCombinationsMax {
+			t.Errorf("ToPipeline for %s returned %d stages, want %d", test.name, len(*expanded), constants.MatrixCombinationsMax)
+		}
+
+		diagnostics := steps.Validate()
+		if len(diagnostics) == 0 {
+			t.Errorf("Validate for %s returned no diagnostics", test.name)
+		}
+	}
+}
+
+func TestYaml_Matrix_UnmarshalYAML(t *testing.T) {
+	// setup types
+	want := Matrix{
+		Axes: MatrixAxisSlice{
+			{Name: "go", Values: []string{"1.21", "1.22"}},
+			{Name: "os", Values: []string{"linux", "darwin"}},
+		},
+		Exclude: []map[string]string{
+			{"go": "1.21", "os": "darwin"},
+		},
+		Include: []map[string]string{
+			{"go": "1.22", "os": "linux", "experimental": "true"},
+			{"go": "1.23", "os": "linux"},
+		},
+	}
+
+	steps := new(StepSlice)
+
+	b, err := os.ReadFile("testdata/step_matrix.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	// run test
+	err = yaml.Unmarshal(b, steps)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual((*steps)[0].Matrix, want) {
+		t.Errorf("UnmarshalYAML is %v, want %v", (*steps)[0].Matrix, want)
+	}
+
+	// marshal and unmarshal the matrix again
+	out, err := yaml.Marshal((*steps)[0].Matrix)
+	if err != nil {
+		t.Errorf("MarshalYAML returned err: %v", err)
+	}
+
+	got := Matrix{}
+
+	err = yaml.Unmarshal(out, &got)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalYAML is %v, want %v", got, want)
+	}
+}
+
+func TestYaml_StepSlice_ToPipeline_Matrix(t *testing.T) {
+	// setup types
+	steps := new(StepSlice)
+
+	b, err := os.ReadFile("testdata/step_matrix.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(b, steps)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	wantNames := []string{"test_1.21_linux", "test_1.22_linux_true", "test_1.22_darwin", "test_1.23_linux", "publish"}
+	wantEnv := map[string]string{
+		"CGO_ENABLED":              "0",
+		"VELA_MATRIX_GO":           "1.22",
+		"VELA_MATRIX_OS":           "linux",
+		"VELA_MATRIX_EXPERIMENTAL": "true",
+	}
+
+	// run test
+	got := steps.ToPipeline()
+
+	names := []string{}
+	for _, container := range *got {
+		names = append(names, container.Name)
+	}
+
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("ToPipeline names are %v, want %v", names, wantNames)
+	}
+
+	if !reflect.DeepEqual((*got)[1].Environment, wantEnv) {
+		t.Errorf("ToPipeline environment is %v, want %v", (*got)[1].Environment, wantEnv)
+	}
+
+	if !reflect.DeepEqual((*got)[4].Needs, wantNames[:4]) {
+		t.Errorf("ToPipeline needs are %v, want %v", (*got)[4].Needs, wantNames[:4])
+	}
+
+	// the step environment must not be shared between combinations
+	if (*got)[0].Environment["VELA_MATRIX_GO"] != "1.21" {
+		t.Errorf("ToPipeline environment is %v", (*got)[0].Environment)
+	}
+
+	err = (*got)[2].Substitute()
+	if err != nil {
+		t.Errorf("Substitute returned err: %v", err)
+	}
+
+	if (*got)[2].Image != "golang:1.22" {
+		t.Errorf("Substitute image is %s, want golang:1.22", (*got)[2].Image)
+	}
+
+	if len(steps.Validate()) != 0 {
+		t.Errorf("Validate returned %v", steps.Validate())
+	}
+}
+
+func TestYaml_StageSlice_ToPipeline_Matrix(t *testing.T) {
+	// setup types
+	stages := &StageSlice{
+		{
+			Name: "test",
+			Matrix: Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "node", Values: []string{"20", "22"}},
+				},
+			},
+			Steps: StepSlice{
+				{Name: "test", Image: "node:${matrix.node}"},
+			},
+		},
+		{
+			Name:  "deploy",
+			Needs: []string{"test"},
+			Steps: StepSlice{
+				{Name: "deploy", Image: "alpine:latest"},
+			},
+		},
+	}
+
+	// run test
+	got := stages.ToPipeline()
+
+	if len(*got) != 3 {
+		t.Fatalf("ToPipeline returned %d stages, want 3", len(*got))
+	}
+
+	if (*got)[1].Name != "test_22" {
+		t.Errorf("ToPipeline name is %s, want test_22", (*got)[1].Name)
+	}
+
+	want := map[string]string{"VELA_MATRIX_NODE": "22"}
+
+	if !reflect.DeepEqual((*got)[1].Environment, want) {
+		t.Errorf("ToPipeline environment is %v, want %v", (*got)[1].Environment, want)
+	}
+
+	if !reflect.DeepEqual((*got)[1].Steps[0].Environment, want) {
+		t.Errorf("ToPipeline step environment is %v, want %v", (*got)[1].Steps[0].Environment, want)
+	}
+
+	if !reflect.DeepEqual((*got)[2].Needs, []string{"test_20", "test_22"}) {
+		t.Errorf("ToPipeline needs are %v, want [test_20 test_22]", (*got)[2].Needs)
+	}
+
+	dag, err := got.DAG()
+	if err != nil {
+		t.Errorf("DAG returned err: %v", err)
+	}
+
+	waves := [][]string{{"test_20", "test_22"}, {"deploy"}}
+
+	if !reflect.DeepEqual(dag.Waves(), waves) {
+		t.Errorf("Waves is %v, want %v", dag.Waves(), waves)
+	}
+}
+
+func TestYaml_Matrix_Validate(t *testing.T) {
+	// setup types
+	matrix := &Matrix{
+		Axes: MatrixAxisSlice{
+			{Name: "go", Values: []string{}},
+			{Name: "os", Values: []string{"linux"}},
+		},
+		Exclude: []map[string]string{
+			{"arch": "arm64"},
+		},
+	}
+
+	want := []string{
+		"error: steps[0].matrix.go: no values provided for matrix axis go",
+		"error: steps[0].matrix.exclude[0]: unknown matrix axis arch",
+	}
+
+	// run test
+	got := []string{}
+
+	for _, diagnostic := range matrix.validate("steps[0].matrix", "test") {
+		got = append(got, diagnostic.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("validate is %v, want %v", got, want)
+	}
+}
+
+func TestYaml_Matrix_Validate_Combinations(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		matrix *Matrix
+		want   []string
+	}{
+		{
+			name: "duplicate names",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "a", Values: []string{"x_y", "x"}},
+					{Name: "b", Values: []string{"z", "y_z"}},
+				},
+			},
+			want: []string{
+				"error: steps[0].matrix: duplicate name test_x_y_z produced by matrix combinations",
+			},
+		},
+		{
+			name: "limit",
+			matrix: &Matrix{
+				Axes: MatrixAxisSlice{
+					{Name: "a", Values: make([]string, 100)},
+					{Name: "b", Values: make([]string, 100)},
+				},
+			},
+			want: []string{
+				"error: steps[0].matrix: matrix combinations exceed the limit of 256",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := []string{}
+
+		for _, diagnostic := range test.matrix.validate("steps[0].matrix", "test") {
+			got = append(got, diagnostic.String())
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("validate for %s is %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,331 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/raw"
+)
+
+var (
+	// ErrTemplateFormat defines the error type when
+	// the format of a template is not supported.
+	ErrTemplateFormat = errors.New("unsupported template format")
+
+	// ErrTemplateNotFound defines the error type when a step
+	// references a template not declared by the pipeline.
+	ErrTemplateNotFound = errors.New("template not found")
+
+	// ErrTemplateDepth defines the error type when the templates
+	// nested in rendered steps exceed the maximum depth.
+	ErrTemplateDepth = errors.New("template depth exceeded")
+
+	// ErrTemplateOutputLimit defines the error type when the
+	// pipeline rendered from a Go template exceeds the size limit.
+	ErrTemplateOutputLimit = errors.New("template output size limit exceeded")
+
+	// ErrTemplateUntilLimit defines the error type when the until
+	// function for a Go template is called with a count exceeding the limit.
+	ErrTemplateUntilLimit = errors.New("template until count limit exceeded")
+)
+
+// Renderer renders the templates declared by a pipeline
+// with the source for every template read from a Loader.
+type Renderer struct {
+	loader Loader
+	// environment for the build provided to the `vela` function
+	environment map[string]string
+	// evaluator for templates in the Starlark format
+	starlark *Starlark
+	// maximum size in bytes for the pipeline rendered from a Go template
+	outputLimit int
+}
+
+// NewRenderer returns a Renderer reading the source for templates
+// with the provided loader. The environment, like the one returned
+// by Environment for the library Build and Repo types, provides the
+// values for the `vela` function available to a Go template.
+func NewRenderer(loader Loader, environment map[string]string) *Renderer {
+	return &Renderer{
+		loader:      loader,
+		environment: environment,
+		starlark:    NewStarlark(nil, nil),
+		outputLimit: constants.TemplateOutputLimitDefault,
+	}
+}
+
+// SetStarlark sets the evaluator for templates in the Starlark
+// format, which provides the build and repo to the template.
+func (r *Renderer) SetStarlark(s *Starlark) {
+	r.starlark = s
+}
+
+// SetOutputLimit sets the maximum size in bytes for the pipeline rendered from a Go template.
+func (r *Renderer) SetOutputLimit(limit int) {
+	r.outputLimit = limit
+}
+
+// Render renders the provided template for the step with the provided
+// name and variables to a Build type. The source for the template is
+// read with the loader using the source declared by the template and
+// every rendered step has its name prefixed with the name of the step.
+//
+// A Go template has access to the variables with `.vars`, to the
+// environment for the build with the `vela` function and to a
+// set of functions mirroring the commonly used sprig functions.
+// Rendering fails once the output exceeds the limit set with
+// SetOutputLimit. A Starlark template is evaluated with the Starlark evaluator
+// for the renderer and has access to the variables with `ctx`.
+func (r *Renderer) Render(t *Template, name string, variables map[string]interface{}) (*Build, error) {
+	source, err := r.loader.Load(t.Source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load template %s: %w", t.Name, err)
+	}
+
+	var b *Build
+
+	switch t.Format {
+	case "", constants.TemplateFormatGo, constants.TemplateFormatGolang:
+		b, err = r.renderGo(string(source), name, variables)
+	case constants.TemplateFormatStarlark:
+		b, err = r.starlark.Evaluate(t.Source, source, variables)
+	default:
+		return nil, fmt.Errorf("%w: %s for template %s", ErrTemplateFormat, t.Format, t.Name)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to render template %s for step %s: %w", t.Name, name, err)
+	}
+
+	// ensure every rendered step has the template prefix
+	for _, step := range b.Steps {
+		step.Name = fmt.Sprintf("%s_%s", name, step.Name)
+	}
+
+	return b, nil
+}
+
+// Expand returns a copy of the provided Build type with every
+// step referencing a template, including the steps in stages,
+// replaced by the steps rendered from the template. The secrets,
+// services and environment from every rendered template are
+// merged into the pipeline, where the values declared by the
+// pipeline take precedence over the ones from a template.
+func (r *Renderer) Expand(b *Build) (*Build, error) {
+	expanded := *b
+
+	expanded.Environment = maps.Clone(b.Environment)
+	expanded.Secrets = slices.Clone(b.Secrets)
+	expanded.Services = slices.Clone(b.Services)
+
+	templates := b.Templates.Map()
+
+	steps, err := r.expandSteps(&expanded, b.Steps, templates, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded.Steps = steps
+
+	if b.Stages != nil {
+		expanded.Stages = StageSlice{}
+
+		// iterate through each stage in the pipeline
+		for _, stage := range b.Stages {
+			s := *stage
+
+			s.Steps, err = r.expandSteps(&expanded, stage.Steps, templates, 0)
+			if err != nil {
+				return nil, err
+			}
+
+			expanded.Stages = append(expanded.Stages, &s)
+		}
+	}
+
+	return &expanded, nil
+}
+
+// expandSteps is a helper function to replace every step
+// referencing a template with the rendered steps and to merge
+// the rendered blocks into the provided pipeline.
+func (r *Renderer) expandSteps(b *Build, steps StepSlice, templates map[string]*Template, depth int) (StepSlice, error) {
+	if steps == nil {
+		return nil, nil
+	}
+
+	expanded := StepSlice{}
+
+	// iterate through each step
+	for _, step := range steps {
+		// keep steps that do not reference a template
+		if len(step.Template.Name) == 0 {
+			expanded = append(expanded, step)
+
+			continue
+		}
+
+		// verify the nested templates are within the limit
+		if depth >= constants.TemplateDepthMax {
+			return nil, fmt.Errorf("%w: step %s is nested more than %d templates deep", ErrTemplateDepth, step.Name, constants.TemplateDepthMax)
+		}
+
+		t, ok := templates[step.Template.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s for step %s", ErrTemplateNotFound, step.Template.Name, step.Name)
+		}
+
+		rendered, err := r.Render(t, step.Name, step.Template.Variables)
+		if err != nil {
+			return nil, err
+		}
+
+		// verify the template only provides steps
+		if len(rendered.Stages) > 0 {
+			return nil, fmt.Errorf("unable to expand template %s for step %s: templates cannot provide stages", t.Name, step.Name)
+		}
+
+		mergeRendered(b, rendered)
+
+		// templates declared by the rendered template are available to its steps
+		nested := templates
+
+		if len(rendered.Templates) > 0 {
+			nested = maps.Clone(templates)
+			maps.Copy(nested, rendered.Templates.Map())
+		}
+
+		s, err := r.expandSteps(b, rendered.Steps, nested, depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded = append(expanded, s...)
+	}
+
+	return expanded, nil
+}
+
+// renderGo is a helper function to render the provided
+// source as a Go template and unmarshal the result.
+func (r *Renderer) renderGo(source, name string, variables map[string]interface{}) (*Build, error) {
+	buffer := &limitedBuffer{limit: r.outputLimit, err: ErrTemplateOutputLimit}
+
+	// parse the template with the functions for a template
+	t, err := template.New(name).Funcs(funcs(r.outputLimit)).Funcs(template.FuncMap{
+		"vela": r.vela(name),
+	}).Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply the variables to the parsed template
+	err = t.Execute(buffer, map[string]interface{}{
+		"vars": variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b := new(Build)
+
+	// unmarshal the rendered template as a build type
+	err = yaml.Unmarshal(buffer.Bytes(), b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// limitedBuffer is a buffer failing every write that
+// would exceed the provided size in bytes with the
+// provided error.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+	err   error
+}
+
+// Write implements the io.Writer interface for the limitedBuffer type.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	err := b.check(len(p))
+	if err != nil {
+		return 0, err
+	}
+
+	return b.Buffer.Write(p)
+}
+
+// check is a helper function to return an error when
+// writing the provided number of bytes to the buffer
+// would exceed the size limit.
+func (b *limitedBuffer) check(n int) error {
+	if n > b.limit-b.Len() {
+		return fmt.Errorf("%w: wrote more than %d bytes", b.err, b.limit)
+	}
+
+	return nil
+}
+
+// vela is a helper function to return the `vela` function for
+// a template rendered for the step with the provided name. The
+// function returns the value of the environment variable for
+// the build matching the provided name, with or without the
+// `VELA_` prefix, in any case. The name of the step rendering
+// the template is available with `template_name`.
+func (r *Renderer) vela(name string) func(string) string {
+	return func(key string) string {
+		key = strings.ToUpper(key)
+
+		if key == "TEMPLATE_NAME" || key == "VELA_TEMPLATE_NAME" {
+			return name
+		}
+
+		if value, ok := r.environment["VELA_"+strings.TrimPrefix(key, "VELA_")]; ok {
+			return value
+		}
+
+		return r.environment[key]
+	}
+}
+
+// mergeRendered is a helper function to merge the secrets,
+// services and environment from a rendered template into
+// the provided pipeline without replacing existing values.
+func mergeRendered(b *Build, rendered *Build) {
+	if len(rendered.Environment) > 0 && b.Environment == nil {
+		b.Environment = make(raw.StringSliceMap)
+	}
+
+	// merge the environment without replacing existing keys
+	for key, value := range rendered.Environment {
+		if _, ok := b.Environment[key]; !ok {
+			b.Environment[key] = value
+		}
+	}
+
+	// merge the secrets without replacing existing names
+	for _, secret := range rendered.Secrets {
+		if !slices.ContainsFunc(b.Secrets, func(s *Secret) bool { return secretName(s) == secretName(secret) }) {
+			b.Secrets = append(b.Secrets, secret)
+		}
+	}
+
+	// merge the services without replacing existing names
+	for _, service := range rendered.Services {
+		if !slices.ContainsFunc(b.Services, func(s *Service) bool { return s.Name == service.Name }) {
+			b.Services = append(b.Services, service)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/library"
+	"github.com/go-vela/types/raw"
+)
+
+func TestYaml_Renderer_Render(t *testing.T) {
+	// setup types
+	r := NewRenderer(FSLoader(os.DirFS("testdata/template")), map[string]string{
+		"VELA_REPO_FULL_NAME": "github/octocat",
+		"VELA_REPO_NAME":      "Octocat",
+	})
+
+	tmpl := &Template{Name: "go", Source: "go.yml", Format: "go"}
+
+	variables := map[string]interface{}{
+		"image":       "golang:1.23",
+		"pull_policy": "always",
+		"tags":        []interface{}{"unit"},
+	}
+
+	want := &Build{
+		Version: "1",
+		Metadata: Metadata{
+			Environment: []string{"steps", "services", "secrets"},
+		},
+		Environment: raw.StringSliceMap{
+			"GOOS":        "darwin",
+			"CGO_ENABLED": "0",
+		},
+		Services: ServiceSlice{
+			{
+				Name:  "redis",
+				Image: "redis:7",
+				Pull:  "not_present",
+			},
+		},
+		Steps: StepSlice{
+			{
+				Name:     "golang_test_unit",
+				Image:    "golang:1.23",
+				Pull:     "always",
+				Commands: raw.StringSlice{"go test -tags unit ./..."},
+				Environment: raw.StringSliceMap{
+					"REPO":     "github/octocat",
+					"TEMPLATE": "golang",
+				},
+			},
+			{
+				Name:     "golang_build",
+				Image:    "golang:1.23",
+				Pull:     "not_present",
+				Commands: raw.StringSlice{"go build -o octocat ."},
+			},
+		},
+	}
+
+	// run test
+	got, err := r.Render(tmpl, "golang", variables)
+	if err != nil {
+		t.Fatalf("Render returned err: %v", err)
+	}
+
+	// ignore the defaults set for the ruleset and parameters
+	for _, step := range got.Steps {
+		step.Ruleset = Ruleset{}
+		step.Parameters = nil
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Render is %v, want %v", got, want)
+	}
+}
+
+func TestYaml_Renderer_Render_Starlark(t *testing.T) {
+	// setup types
+	repo := new(library.Repo)
+	repo.SetFullName("github/octocat")
+
+	r := NewRenderer(FSLoader(os.DirFS("testdata/template")), nil)
+	r.SetStarlark(NewStarlark(nil, repo))
+
+	tmpl := &Template{Name: "starlark", Source: "starlark.star", Format: "starlark"}
+
+	want := StepSlice{
+		{
+			Name:     "sample_echo",
+			Image:    "alpine:latest",
+			Pull:     "not_present",
+			Commands: raw.StringSlice{"echo github/octocat"},
+		},
+	}
+
+	// run test
+	got, err := r.Render(tmpl, "sample", map[string]interface{}{"image": "alpine:latest"})
+	if err != nil {
+		t.Fatalf("Render returned err: %v", err)
+	}
+
+	// ignore the defaults set for the ruleset
+	for _, step := range got.Steps {
+		step.Ruleset = Ruleset{}
+	}
+
+	if !reflect.DeepEqual(got.Steps, want) {
+		t.Errorf("Render is %v, want %v", got.Steps, want)
+	}
+}
+
+func TestYaml_Renderer_Render_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		template *Template
+		output   int
+		want     error
+	}{
+		{
+			name:     "missing source",
+			template: &Template{Name: "missing", Source: "missing.yml"},
+			want:     os.ErrNotExist,
+		},
+		{
+			name:     "unsupported format",
+			template: &Template{Name: "starlark", Source: "starlark.star", Format: "python"},
+			want:     ErrTemplateFormat,
+		},
+		{
+			name:     "invalid template",
+			template: &Template{Name: "invalid", Source: "invalid.yml"},
+		},
+		{
+			name:     "output limit",
+			template: &Template{Name: "go", Source: "go.yml"},
+			output:   16,
+			want:     ErrTemplateOutputLimit,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := NewRenderer(FSLoader(os.DirFS("testdata/template")), nil)
+
+			if test.output > 0 {
+				r.SetOutputLimit(test.output)
+			}
+
+			_, err := r.Render(test.template, "step", map[string]interface{}{"image": "golang:1.23"})
+			if err == nil {
+				t.Errorf("Render should have returned err")
+			}
+
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("Render returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestYaml_Renderer_Expand(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/template/pipeline.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	b := new(Build)
+
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		t.Fatalf("unable to unmarshal pipeline: %v", err)
+	}
+
+	r := NewRenderer(FSLoader(os.DirFS("testdata/template")), map[string]string{
+		"VELA_BUILD_COMMIT":   "a1b2c3d4e5f6",
+		"VELA_REPO_FULL_NAME": "github/octocat",
+		"VELA_REPO_NAME":      "octocat",
+		"VELA_REPO_ORG":       "github",
+	})
+
+	wantSteps := []string{
+		"lint",
+		"golang_test_unit",
+		"golang_test_integration",
+		"golang_build",
+		"release_docker_publish",
+	}
+
+	wantEnvironment := raw.StringSliceMap{
+		"GOOS":        "linux",
+		"CGO_ENABLED": "0",
+	}
+
+	// run test
+	got, err := r.Expand(b)
+	if err != nil {
+		t.Fatalf("Expand returned err: %v", err)
+	}
+
+	steps := []string{}
+	for _, step := range got.Steps {
+		steps = append(steps, step.Name)
+	}
+
+	if !reflect.DeepEqual(steps, wantSteps) {
+		t.Errorf("Expand steps are %v, want %v", steps, wantSteps)
+	}
+
+	if !reflect.DeepEqual(got.Environment, wantEnvironment) {
+		t.Errorf("Expand environment is %v, want %v", got.Environment, wantEnvironment)
+	}
+
+	if len(got.Services) != 1 || got.Services[0].Name != "redis" {
+		t.Errorf("Expand services are %v, want redis", got.Services)
+	}
+
+	if len(got.Secrets) != 1 || got.Secrets[0].Key != "github/docker/password" {
+		t.Errorf("Expand secrets are %v, want github/docker/password", got.Secrets)
+	}
+
+	publish := got.Steps[len(got.Steps)-1]
+
+	if !reflect.DeepEqual(publish.Parameters["tags"], []interface{}{"latest", "a1b2c3d"}) {
+		t.Errorf("Expand tags are %v, want [latest a1b2c3d]", publish.Parameters["tags"])
+	}
+
+	// verify the provided pipeline is not modified
+	if len(b.Steps) != 3 || b.Environment["CGO_ENABLED"] != "" || len(b.Services) != 0 {
+		t.Errorf("Expand modified the provided pipeline: %v", b)
+	}
+}
+
+func TestYaml_Renderer_Expand_Stages(t *testing.T) {
+	// setup types
+	b := &Build{
+		Templates: TemplateSlice{{Name: "go", Source: "go.yml"}},
+		Stages: StageSlice{
+			{
+				Name: "test",
+				Steps: StepSlice{
+					{
+						Name: "golang",
+						Template: StepTemplate{
+							Name:      "go",
+							Variables: map[string]interface{}{"image": "golang:1.23"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	r := NewRenderer(FSLoader(os.DirFS("testdata/template")), nil)
+
+	// run test
+	got, err := r.Expand(b)
+	if err != nil {
+		t.Fatalf("Expand returned err: %v", err)
+	}
+
+	if len(got.Stages) != 1 || len(got.Stages[0].Steps) != 1 || got.Stages[0].Steps[0].Name != "golang_build" {
+		t.Errorf("Expand stages are %v, want golang_build step", got.Stages)
+	}
+
+	if b.Stages[0].Steps[0].Name != "golang" {
+		t.Errorf("Expand modified the provided stages: %v", b.Stages)
+	}
+}
+
+func TestYaml_Renderer_Expand_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		step *Step
+		want error
+	}{
+		{
+			name: "template not found",
+			step: &Step{Name: "test", Template: StepTemplate{Name: "missing"}},
+			want: ErrTemplateNotFound,
+		},
+		{
+			name: "nested too deep",
+			step: &Step{Name: "test", Template: StepTemplate{Name: "nested"}},
+			want: ErrTemplateDepth,
+		},
+	}
+
+	r := NewRenderer(FSLoader(os.DirFS("testdata/template")), nil)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Build{
+				Templates: TemplateSlice{{Name: "nested", Source: "nested.yml"}},
+				Steps:     StepSlice{test.step},
+			}
+
+			_, err := r.Expand(b)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Expand returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"github.com/go-vela/types/pipeline"
+)
+
+type (
+	// Resources is the yaml representation of the compute
+	// resources block for a step or service in a pipeline.
+	//
+	// Deprecated: use Resources from github.com/go-vela/server/compiler/types/yaml instead.
+	Resources struct {
+		Requests ResourceList `yaml:"requests,omitempty" json:"requests,omitempty" jsonschema:"description=Minimum compute resources reserved for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-resources-key"`
+		Limits   ResourceList `yaml:"limits,omitempty"   json:"limits,omitempty" jsonschema:"description=Maximum compute resources allowed for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-resources-key"`
+	}
+
+	// ResourceList is the yaml representation of the
+	// amount of compute resources for a container.
+	//
+	// Deprecated: use ResourceList from github.com/go-vela/server/compiler/types/yaml instead.
+	ResourceList struct {
+		CPU    string `yaml:"cpu,omitempty"    json:"cpu,omitempty" jsonschema:"example=500m,description=Amount of CPU in cores or millicores.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-resources-key"`
+		Memory string `yaml:"memory,omitempty" json:"memory,omitempty" jsonschema:"example=2Gi,description=Amount of memory in bytes with an optional suffix.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-resources-key"`
+	}
+)
+
+// Empty returns true if the provided resources are empty.
+func (r *Resources) Empty() bool {
+	// return true if the resources are nil
+	if r == nil {
+		return true
+	}
+
+	// return true if every resources field is empty
+	if len(r.Requests.CPU) == 0 &&
+		len(r.Requests.Memory) == 0 &&
+		len(r.Limits.CPU) == 0 &&
+		len(r.Limits.Memory) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// ToPipeline converts the Resources type to a pipeline
+// Resources type. When the resources are empty, nil is
+// returned.
+func (r *Resources) ToPipeline() *pipeline.Resources {
+	// return nothing if the resources are empty
+	if r.Empty() {
+		return nil
+	}
+
+	return &pipeline.Resources{
+		Requests: *r.Requests.ToPipeline(),
+		Limits:   *r.Limits.ToPipeline(),
+	}
+}
+
+// ToPipeline converts the ResourceList type
+// to a pipeline ResourceList type.
+func (r *ResourceList) ToPipeline() *pipeline.ResourceList {
+	// invalid quantities are reported when validating the yaml
+	cpu, _ := pipeline.ParseCPU(r.CPU)
+	memory, _ := pipeline.ParseMemory(r.Memory)
+
+	return &pipeline.ResourceList{
+		CPU:    cpu,
+		Memory: memory,
+	}
+}
+
+// validate is a helper function to verify the yaml for the
+// Resources type with paths relative to the provided path.
+func (r *Resources) validate(path, name string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// return early if the resources are empty
+	if r.Empty() {
+		return diagnostics
+	}
+
+	requests := r.Requests.validate(joinPath(path, "requests"), name, &diagnostics)
+	limits := r.Limits.validate(joinPath(path, "limits"), name, &diagnostics)
+
+	// verify the cpu request does not exceed the cpu limit
+	if limits.CPU > 0 && requests.CPU > limits.CPU {
+		diagnostics.errorf(joinPath(joinPath(path, "requests"), "cpu"), "cpu request %s exceeds cpu limit %s for %s", r.Requests.CPU, r.Limits.CPU, name)
+	}
+
+	// verify the memory request does not exceed the memory limit
+	if limits.Memory > 0 && requests.Memory > limits.Memory {
+		diagnostics.errorf(joinPath(joinPath(path, "requests"), "memory"), "memory request %s exceeds memory limit %s for %s", r.Requests.Memory, r.Limits.Memory, name)
+	}
+
+	return diagnostics
+}
+
+// validate is a helper function to verify every quantity for the
+// ResourceList type with paths relative to the provided path. The
+// quantities that could be parsed are returned.
+func (r *ResourceList) validate(path, name string, diagnostics *Diagnostics) *pipeline.ResourceList {
+	list := new(pipeline.ResourceList)
+
+	// verify the cpu quantity can be parsed
+	if len(r.CPU) > 0 {
+		cpu, err := pipeline.ParseCPU(r.CPU)
+		if err != nil {
+			diagnostics.errorf(joinPath(path, "cpu"), "%v for %s", err, name)
+		}
+
+		list.CPU = cpu
+	}
+
+	// verify the memory quantity can be parsed
+	if len(r.Memory) > 0 {
+		memory, err := pipeline.ParseMemory(r.Memory)
+		if err != nil {
+			diagnostics.errorf(joinPath(path, "memory"), "%v for %s", err, name)
+		}
+
+		list.Memory = memory
+	}
+
+	return list
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/pipeline"
+)
+
+func TestYaml_Resources_ToPipeline(t *testing.T) {
+	// setup types
+	steps := new(StepSlice)
+
+	b, err := os.ReadFile("testdata/step_resources.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(b, steps)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	want := &pipeline.Resources{
+		Requests: pipeline.ResourceList{CPU: 500, Memory: 1 << 30},
+		Limits:   pipeline.ResourceList{CPU: 2000, Memory: 4 << 30},
+	}
+
+	// run test
+	got := (*steps.ToPipeline())[0].Resources
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToPipeline is %v, want %v", got, want)
+	}
+
+	if new(Resources).ToPipeline() != nil {
+		t.Errorf("ToPipeline for empty resources should return nil")
+	}
+}
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+)
+
+// RetryBackoff is the yaml representation of the retry_backoff
+// block for a step or service in a pipeline.
+//
+// Deprecated: use RetryBackoff from github.com/go-vela/server/compiler/types/yaml instead.
+type RetryBackoff struct {
+	Type string `yaml:"type,omitempty"  json:"type,omitempty" jsonschema:"enum=fixed,enum=exponential,default=fixed,description=Method used to compute the delay between retries.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-retry_backoff-key"`
+	// Delay and Max are durations, like 10s or 1h30m, or a number
+	// of minutes when no unit is provided, like 10. An invalid value
+	// is ignored by ToPipeline and only reported by Validate, so the
+	// zero duration is used instead.
+	Delay string `yaml:"delay,omitempty" json:"delay,omitempty" jsonschema:"example=10s,description=Delay to wait before the first retry.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-retry_backoff-key"`
+	Max   string `yaml:"max,omitempty"   json:"max,omitempty" jsonschema:"example=5m,description=Maximum delay to wait between retries.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-retry_backoff-key"`
+}
+
+// Empty returns true if the provided retry backoff is empty.
+func (r *RetryBackoff) Empty() bool {
+	// return true if the retry backoff is nil
+	if r == nil {
+		return true
+	}
+
+	// return true if every retry backoff field is empty
+	if len(r.Type) == 0 &&
+		len(r.Delay) == 0 &&
+		len(r.Max) == 0 {
+		return true
+	}
+
+	return false
+}
+
+// ToPipeline converts the RetryBackoff type
+// to a pipeline RetryBackoff type. When the
+// retry backoff is empty, nil is returned.
+func (r *RetryBackoff) ToPipeline() *pipeline.RetryBackoff {
+	// return nothing if the retry backoff is empty
+	if r.Empty() {
+		return nil
+	}
+
+	backoff := &pipeline.RetryBackoff{
+		Type: r.Type,
+	}
+
+	// implicitly set `type` field if empty
+	if len(backoff.Type) == 0 {
+		backoff.Type = constants.BackoffFixed
+	}
+
+	// invalid durations are reported when validating the yaml
+	backoff.Delay, _ = parseDuration(r.Delay)
+	backoff.Max, _ = parseDuration(r.Max)
+
+	return backoff
+}
+
+// parseDuration is a helper function to parse the provided value as a
+// duration, like `90s` or `1h30m`. A value without a unit, like `10`,
+// is treated as a number of minutes to match the build timeout for a
+// repo. An empty value returns a zero duration.
+func parseDuration(value string) (time.Duration, error) {
+	// return a zero duration if the value is empty
+	if len(value) == 0 {
+		return 0, nil
+	}
+
+	// attempt to parse the value as a number of minutes
+	minutes, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return time.Duration(minutes) * time.Minute, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %s", value)
+	}
+
+	return duration, nil
+}
+
+// validateRetry is a helper function to verify the timeout, retries
+// and retry backoff for a container with paths relative to the
+// provided path.
+func validateRetry(path, name, timeout string, retries int, backoff *RetryBackoff) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// verify the timeout for the container
+	if len(timeout) > 0 {
+		duration, err := parseDuration(timeout)
+
+		switch {
+		case err != nil:
+			diagnostics.errorf(joinPath(path, "timeout"), "%v for %s", err, name)
+		case duration <= 0:
+			diagnostics.errorf(joinPath(path, "timeout"), "timeout for %s must be greater than zero", name)
+		case duration > constants.BuildTimeoutMax*time.Minute:
+			diagnostics.errorf(joinPath(path, "timeout"), "timeout for %s exceeds the maximum build timeout of %d minutes", name, constants.BuildTimeoutMax)
+		}
+	}
+
+	// verify the number of retries for the container
+	if retries < 0 || retries > constants.StepRetriesMax {
+		diagnostics.errorf(joinPath(path, "retries"), "retries for %s must be between 0 and %d", name, constants.StepRetriesMax)
+	}
+
+	// return early if no retry backoff was provided
+	if backoff.Empty() {
+		return diagnostics
+	}
+
+	backoffPath := joinPath(path, "retry_backoff")
+
+	// verify the retry backoff is used
+	if retries == 0 {
+		diagnostics.warnf(backoffPath, "retry_backoff has no effect for %s without retries", name)
+	}
+
+	// verify the type for the retry backoff
+	switch backoff.Type {
+	case "", constants.BackoffFixed, constants.BackoffExponential:
+	default:
+		diagnostics.errorf(joinPath(backoffPath, "type"), "invalid retry backoff type %s for %s", backoff.Type, name)
+	}
+
+	delay, err := parseDuration(backoff.Delay)
+	if err != nil {
+		diagnostics.errorf(joinPath(backoffPath, "delay"), "%v for %s", err, name)
+	}
+
+	limit, err := parseDuration(backoff.Max)
+	if err != nil {
+		diagnostics.errorf(joinPath(backoffPath, "max"), "%v for %s", err, name)
+	}
+
+	// verify the max delay is not less than the delay
+	if limit > 0 && limit < delay {
+		diagnostics.errorf(joinPath(backoffPath, "max"), "retry backoff max for %s is less than the delay", name)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/buildkite/yaml"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/pipeline"
+)
+
+func TestYaml_RetryBackoff_ToPipeline(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		backoff *RetryBackoff
+		want    *pipeline.RetryBackoff
+	}{
+		{
+			backoff: new(RetryBackoff),
+			want:    nil,
+		},
+		{
+			backoff: &RetryBackoff{Delay: "10s"},
+			want:    &pipeline.RetryBackoff{Type: constants.BackoffFixed, Delay: 10 * time.Second},
+		},
+		{
+			backoff: &RetryBackoff{Type: "exponential", Delay: "1", Max: "1h"},
+			want:    &pipeline.RetryBackoff{Type: constants.BackoffExponential, Delay: time.Minute, Max: time.Hour},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := test.backoff.ToPipeline()
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ToPipeline is %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestYaml_StepSlice_ToPipeline_Retry(t *testing.T) {
+	// setup types
+	steps := new(StepSlice)
+
+	b, err := os.ReadFile("testdata/step_retry.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	err = yaml.Unmarshal(b, steps)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	// run test
+	got := *steps.ToPipeline()
+
+	if len(got) != 3 {
+		t.Fatalf("ToPipeline returned %d containers, want 3", len(got))
+	}
+
+	if got[0].Timeout != 10*time.Minute {
+		t.Errorf("ToPipeline timeout is %v, want %v", got[0].Timeout, 10*time.Minute)
+	}
+
+	if got[0].Retries != 3 {
+		t.Errorf("ToPipeline retries is %v, want %v", got[0].Retries, 3)
+	}
+
+	want := &pipeline.RetryBackoff{
+		Type:  constants.BackoffExponential,
+		Delay: 10 * time.Second,
+		Max:   time.Minute,
+	}
+
+	if !reflect.DeepEqual(got[0].RetryBackoff, want) {
+		t.Errorf("ToPipeline retry backoff is %v, want %v", got[0].RetryBackoff, want)
+	}
+
+	if got[1].Timeout != 90*time.Second {
+		t.Errorf("ToPipeline timeout is %v, want %v", got[1].Timeout, 90*time.Second)
+	}
+}
+
+func TestYaml_parseDuration(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		value   string
+		want    time.Duration
+		failure bool
+	}{
+		{value: "", want: 0},
+		{value: "30", want: 30 * time.Minute},
+		{value: "1h30m", want: 90 * time.Minute},
+		{value: "foo", failure: true},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got, err := parseDuration(test.value)
+
+		if test.failure {
+			if err == nil {
+				t.Errorf("parseDuration for %s should have returned err", test.value)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseDuration for %s returned err: %v", test.value, err)
+		}
+
+		if got != test.want {
+			t.Errorf("parseDuration for %s is %v, want %v", test.value, got, test.want)
+		}
+	}
+}
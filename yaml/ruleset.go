@@ -3,6 +3,9 @@
 package yaml
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/go-vela/types/constants"
 	"github.com/go-vela/types/pipeline"
 	"github.com/go-vela/types/raw"
@@ -16,7 +19,7 @@ type (
 	Ruleset struct {
 		If       Rules  `yaml:"if,omitempty"       json:"if,omitempty" jsonschema:"description=Limit execution to when all rules match.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
 		Unless   Rules  `yaml:"unless,omitempty"   json:"unless,omitempty" jsonschema:"description=Limit execution to when all rules do not match.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Matcher  string `yaml:"matcher,omitempty"  json:"matcher,omitempty" jsonschema:"enum=filepath,enum=regexp,default=filepath,description=Use the defined matching method.\nReference: coming soon"`
+		Matcher  string `yaml:"matcher,omitempty"  json:"matcher,omitempty" jsonschema:"enum=filepath,enum=regexp,enum=glob,default=filepath,description=Use the defined matching method.\nReference: coming soon"`
 		Operator string `yaml:"operator,omitempty" json:"operator,omitempty" jsonschema:"enum=or,enum=and,default=and,description=Whether all rule conditions must be met or just any one of them.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
 		Continue bool   `yaml:"continue,omitempty" json:"continue,omitempty" jsonschema:"default=false,description=Limits the execution of a step to continuing on any failure.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
 	}
@@ -26,19 +29,60 @@ type (
 	//
 	// Deprecated: use Rules from github.com/go-vela/server/compiler/types/yaml instead.
 	Rules struct {
-		Branch   []string `yaml:"branch,omitempty,flow"   json:"branch,omitempty" jsonschema:"description=Limits the execution of a step to matching build branches.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Comment  []string `yaml:"comment,omitempty,flow"  json:"comment,omitempty" jsonschema:"description=Limits the execution of a step to matching a pull request comment.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Event    []string `yaml:"event,omitempty,flow"    json:"event,omitempty" jsonschema:"description=Limits the execution of a step to matching build events.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Path     []string `yaml:"path,omitempty,flow"     json:"path,omitempty" jsonschema:"description=Limits the execution of a step to matching files changed in a repository.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Repo     []string `yaml:"repo,omitempty,flow"     json:"repo,omitempty" jsonschema:"description=Limits the execution of a step to matching repos.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Status   []string `yaml:"status,omitempty,flow"   json:"status,omitempty" jsonschema:"enum=[failure],enum=[success],description=Limits the execution of a step to matching build statuses.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Tag      []string `yaml:"tag,omitempty,flow"      json:"tag,omitempty" jsonschema:"description=Limits the execution of a step to matching build tag references.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Target   []string `yaml:"target,omitempty,flow"   json:"target,omitempty" jsonschema:"description=Limits the execution of a step to matching build deployment targets.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Label    []string `yaml:"label,omitempty,flow"    json:"label,omitempty" jsonschema:"description=Limits step execution to match on pull requests labels.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
-		Instance []string `yaml:"instance,omitempty,flow" json:"instance,omitempty" jsonschema:"description=Limits step execution to match on certain instances.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Branch       []string            `yaml:"branch,omitempty,flow"        json:"branch,omitempty" jsonschema:"description=Limits the execution of a step to matching build branches.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Comment      []string            `yaml:"comment,omitempty,flow"       json:"comment,omitempty" jsonschema:"description=Limits the execution of a step to matching a pull request comment.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Event        []string            `yaml:"event,omitempty,flow"         json:"event,omitempty" jsonschema:"description=Limits the execution of a step to matching build events.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Path         []string            `yaml:"path,omitempty,flow"          json:"path,omitempty" jsonschema:"description=Limits the execution of a step to matching files changed in a repository.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Repo         []string            `yaml:"repo,omitempty,flow"          json:"repo,omitempty" jsonschema:"description=Limits the execution of a step to matching repos.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Status       []string            `yaml:"status,omitempty,flow"        json:"status,omitempty" jsonschema:"enum=[failure],enum=[success],description=Limits the execution of a step to matching build statuses.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Tag          []string            `yaml:"tag,omitempty,flow"           json:"tag,omitempty" jsonschema:"description=Limits the execution of a step to matching build tag references.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Target       []string            `yaml:"target,omitempty,flow"        json:"target,omitempty" jsonschema:"description=Limits the execution of a step to matching build deployment targets.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Label        []string            `yaml:"label,omitempty,flow"         json:"label,omitempty" jsonschema:"description=Limits step execution to match on pull requests labels.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Instance     []string            `yaml:"instance,omitempty,flow"      json:"instance,omitempty" jsonschema:"description=Limits step execution to match on certain instances.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Message      []string            `yaml:"message,omitempty,flow"       json:"message,omitempty" jsonschema:"description=Limits step execution to match on the build commit message.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Author       []string            `yaml:"author,omitempty,flow"        json:"author,omitempty" jsonschema:"description=Limits step execution to match on the build commit author.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Sender       []string            `yaml:"sender,omitempty,flow"        json:"sender,omitempty" jsonschema:"description=Limits step execution to match on the user that triggered the build.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		BaseRef      []string            `yaml:"base_ref,omitempty,flow"      json:"base_ref,omitempty" jsonschema:"description=Limits step execution to match on the base reference for a pull request.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		ChangedFiles []string            `yaml:"changed_files,omitempty,flow" json:"changed_files,omitempty" jsonschema:"description=Limits step execution to match on thresholds for the number of changed files, i.e. <10.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		Env          map[string][]string `yaml:"env,omitempty"                json:"env,omitempty" jsonschema:"description=Limits step execution to match on environment variables for the step, i.e. DEPLOY_ENABLED: true.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
+		// Expression is set when the rules are provided as
+		// a string, i.e. `if: branch == 'main' && event == 'push'`.
+		Expression string `yaml:"-" json:"expression,omitempty" jsonschema:"description=Limits the execution of a step to when the expression is true.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-ruleset-key"`
 	}
 )
 
+// eventShorthands are the events that are expanded
+// to scoped events when parsing the rules.
+//
+// backwards compatibility
+// pull_request = pull_request:opened + pull_request:synchronize + pull_request:reopened
+// deployment = deployment:created
+// comment = comment:created + comment:edited
+var eventShorthands = []struct {
+	event  string
+	scoped []string
+}{
+	{
+		event: constants.EventPull,
+		scoped: []string{
+			constants.EventPull + ":" + constants.ActionOpened,
+			constants.EventPull + ":" + constants.ActionSynchronize,
+			constants.EventPull + ":" + constants.ActionReopened,
+		},
+	},
+	{
+		event:  constants.EventDeploy,
+		scoped: []string{constants.EventDeploy + ":" + constants.ActionCreated},
+	},
+	{
+		event: constants.EventComment,
+		scoped: []string{
+			constants.EventComment + ":" + constants.ActionCreated,
+			constants.EventComment + ":" + constants.ActionEdited,
+		},
+	},
+}
+
 // ToPipeline converts the Ruleset type
 // to a pipeline Ruleset type.
 func (r *Ruleset) ToPipeline() *pipeline.Ruleset {
@@ -92,6 +136,25 @@ func (r *Ruleset) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	advanced.If.Target = append(advanced.If.Target, simple.Target...)
 	advanced.If.Label = append(advanced.If.Label, simple.Label...)
 	advanced.If.Instance = append(advanced.If.Instance, simple.Instance...)
+	advanced.If.Message = append(advanced.If.Message, simple.Message...)
+	advanced.If.Author = append(advanced.If.Author, simple.Author...)
+	advanced.If.Sender = append(advanced.If.Sender, simple.Sender...)
+	advanced.If.BaseRef = append(advanced.If.BaseRef, simple.BaseRef...)
+	advanced.If.ChangedFiles = append(advanced.If.ChangedFiles, simple.ChangedFiles...)
+
+	// implicitly add simple environment variable rules to the advanced ruleset
+	for name, patterns := range simple.Env {
+		if advanced.If.Env == nil {
+			advanced.If.Env = make(map[string][]string)
+		}
+
+		advanced.If.Env[name] = append(advanced.If.Env[name], patterns...)
+	}
+
+	// implicitly add simple expression to the advanced ruleset
+	if len(advanced.If.Expression) == 0 {
+		advanced.If.Expression = simple.Expression
+	}
 
 	// set ruleset `if` to advanced `if` rules
 	r.If = advanced.If
@@ -109,41 +172,198 @@ func (r *Ruleset) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// validate is a helper function to verify the yaml for
+// the Ruleset type with paths relative to the provided path.
+func (r *Ruleset) validate(path string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// verify the matcher for the ruleset
+	switch r.Matcher {
+	case "", constants.MatcherFilepath:
+	case constants.MatcherRegex, "regex", constants.MatcherGlob:
+		// verify the patterns for the ruleset
+		diagnostics = append(diagnostics, r.If.validatePatterns(joinPath(path, "if"), r.Matcher)...)
+		diagnostics = append(diagnostics, r.Unless.validatePatterns(joinPath(path, "unless"), r.Matcher)...)
+	default:
+		diagnostics.errorf(joinPath(path, "matcher"), "invalid matcher %s", r.Matcher)
+	}
+
+	// verify the operator for the ruleset
+	switch r.Operator {
+	case "", constants.OperatorAnd, constants.OperatorOr:
+	default:
+		diagnostics.errorf(joinPath(path, "operator"), "invalid operator %s", r.Operator)
+	}
+
+	// verify the thresholds for the changed files
+	for i, threshold := range r.If.ChangedFiles {
+		_, err := (&pipeline.Ruletype{threshold}).MatchCount(0, constants.OperatorAnd)
+		if err != nil {
+			diagnostics.errorf(indexPath(joinPath(joinPath(path, "if"), "changed_files"), i), "%v", err)
+		}
+	}
+
+	for i, threshold := range r.Unless.ChangedFiles {
+		_, err := (&pipeline.Ruletype{threshold}).MatchCount(0, constants.OperatorAnd)
+		if err != nil {
+			diagnostics.errorf(indexPath(joinPath(joinPath(path, "unless"), "changed_files"), i), "%v", err)
+		}
+	}
+
+	// verify the expression for the ruleset if conditions
+	if len(r.If.Expression) > 0 {
+		_, err := pipeline.ParseExpression(r.If.Expression)
+		if err != nil {
+			diagnostics.errorf(joinPath(path, "if"), "%v", err)
+		}
+	}
+
+	// verify the expression for the ruleset unless conditions
+	if len(r.Unless.Expression) > 0 {
+		_, err := pipeline.ParseExpression(r.Unless.Expression)
+		if err != nil {
+			diagnostics.errorf(joinPath(path, "unless"), "%v", err)
+		}
+	}
+
+	return diagnostics
+}
+
 // ToPipeline converts the Rules
 // type to a pipeline Rules type.
 func (r *Rules) ToPipeline() *pipeline.Rules {
+	var env map[string]pipeline.Ruletype
+
+	// convert the environment variable rules
+	if len(r.Env) > 0 {
+		env = make(map[string]pipeline.Ruletype)
+
+		for name, patterns := range r.Env {
+			env[name] = patterns
+		}
+	}
+
 	return &pipeline.Rules{
-		Branch:   r.Branch,
-		Comment:  r.Comment,
-		Event:    r.Event,
-		Path:     r.Path,
-		Repo:     r.Repo,
-		Status:   r.Status,
-		Tag:      r.Tag,
-		Target:   r.Target,
-		Label:    r.Label,
-		Instance: r.Instance,
+		Branch:       r.Branch,
+		Comment:      r.Comment,
+		Event:        r.Event,
+		Path:         r.Path,
+		Repo:         r.Repo,
+		Status:       r.Status,
+		Tag:          r.Tag,
+		Target:       r.Target,
+		Label:        r.Label,
+		Instance:     r.Instance,
+		Message:      r.Message,
+		Author:       r.Author,
+		Sender:       r.Sender,
+		BaseRef:      r.BaseRef,
+		ChangedFiles: r.ChangedFiles,
+		Env:          env,
+		Expression:   r.Expression,
 	}
 }
 
+// validatePatterns is a helper function to verify the patterns for
+// the provided matcher for the Rules type with paths relative to the
+// provided path.
+func (r *Rules) validatePatterns(path, matcher string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture every ruletype that supports patterns
+	ruletypes := []struct {
+		path     string
+		patterns []string
+	}{
+		{path: joinPath(path, "branch"), patterns: r.Branch},
+		{path: joinPath(path, "comment"), patterns: r.Comment},
+		{path: joinPath(path, "event"), patterns: r.Event},
+		{path: joinPath(path, "path"), patterns: r.Path},
+		{path: joinPath(path, "repo"), patterns: r.Repo},
+		{path: joinPath(path, "status"), patterns: r.Status},
+		{path: joinPath(path, "tag"), patterns: r.Tag},
+		{path: joinPath(path, "target"), patterns: r.Target},
+		{path: joinPath(path, "label"), patterns: r.Label},
+		{path: joinPath(path, "instance"), patterns: r.Instance},
+		{path: joinPath(path, "message"), patterns: r.Message},
+		{path: joinPath(path, "author"), patterns: r.Author},
+		{path: joinPath(path, "sender"), patterns: r.Sender},
+		{path: joinPath(path, "base_ref"), patterns: r.BaseRef},
+	}
+
+	// capture every environment variable ruletype in a consistent order
+	names := []string{}
+
+	for name := range r.Env {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		ruletypes = append(ruletypes, struct {
+			path     string
+			patterns []string
+		}{path: joinPath(joinPath(path, "env"), name), patterns: r.Env[name]})
+	}
+
+	// iterate through each pattern for each ruletype
+	for _, ruletype := range ruletypes {
+		for i, pattern := range ruletype.patterns {
+			var err error
+
+			// compile the pattern based off the matcher provided
+			switch matcher {
+			case constants.MatcherGlob:
+				_, err = pipeline.MatchGlob(strings.TrimPrefix(pattern, "!"), "")
+			default:
+				_, err = pipeline.MatchRegexp(pattern, "")
+			}
+
+			if err != nil {
+				diagnostics.errorf(indexPath(ruletype.path, i), "%v", err)
+			}
+		}
+	}
+
+	return diagnostics
+}
+
 // UnmarshalYAML implements the Unmarshaler interface for the Rules type.
 func (r *Rules) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	// expression we try unmarshalling to
+	expression := ""
+
+	// attempt to unmarshal rules as an expression
+	err := unmarshal(&expression)
+	if err == nil {
+		r.Expression = expression
+
+		return nil
+	}
+
 	// rules struct we try unmarshalling to
 	rules := new(struct {
-		Branch   raw.StringSlice
-		Comment  raw.StringSlice
-		Event    raw.StringSlice
-		Path     raw.StringSlice
-		Repo     raw.StringSlice
-		Status   raw.StringSlice
-		Tag      raw.StringSlice
-		Target   raw.StringSlice
-		Label    raw.StringSlice
-		Instance raw.StringSlice
+		Branch       raw.StringSlice
+		Comment      raw.StringSlice
+		Event        raw.StringSlice
+		Path         raw.StringSlice
+		Repo         raw.StringSlice
+		Status       raw.StringSlice
+		Tag          raw.StringSlice
+		Target       raw.StringSlice
+		Label        raw.StringSlice
+		Instance     raw.StringSlice
+		Message      raw.StringSlice
+		Author       raw.StringSlice
+		Sender       raw.StringSlice
+		BaseRef      raw.StringSlice `yaml:"base_ref"`
+		ChangedFiles raw.StringSlice `yaml:"changed_files"`
+		Env          map[string]raw.StringSlice
 	})
 
 	// attempt to unmarshal rules
-	err := unmarshal(rules)
+	err = unmarshal(rules)
 	if err == nil {
 		r.Branch = rules.Branch
 		r.Comment = rules.Comment
@@ -154,30 +374,26 @@ func (r *Rules) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		r.Target = rules.Target
 		r.Label = rules.Label
 		r.Instance = rules.Instance
+		r.Message = rules.Message
+		r.Author = rules.Author
+		r.Sender = rules.Sender
+		r.BaseRef = rules.BaseRef
+		r.ChangedFiles = rules.ChangedFiles
+
+		// capture the environment variable rules
+		if len(rules.Env) > 0 {
+			r.Env = make(map[string][]string)
+
+			for name, patterns := range rules.Env {
+				r.Env[name] = patterns
+			}
+		}
 
 		// account for users who use non-scoped pull_request event
 		events := []string{}
 
 		for _, e := range rules.Event {
-			switch e {
-			// backwards compatibility
-			// pull_request = pull_request:opened + pull_request:synchronize + pull_request:reopened
-			// comment = comment:created + comment:edited
-			case constants.EventPull:
-				events = append(events,
-					constants.EventPull+":"+constants.ActionOpened,
-					constants.EventPull+":"+constants.ActionSynchronize,
-					constants.EventPull+":"+constants.ActionReopened)
-			case constants.EventDeploy:
-				events = append(events,
-					constants.EventDeploy+":"+constants.ActionCreated)
-			case constants.EventComment:
-				events = append(events,
-					constants.EventComment+":"+constants.ActionCreated,
-					constants.EventComment+":"+constants.ActionEdited)
-			default:
-				events = append(events, e)
-			}
+			events = append(events, expandEvent(e)...)
 		}
 
 		r.Event = events
@@ -185,3 +401,28 @@ func (r *Rules) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	return err
 }
+
+// expandEvent is a helper function to return the scoped
+// events for the provided event when it is a shorthand.
+func expandEvent(event string) []string {
+	for _, shorthand := range eventShorthands {
+		if event == shorthand.event {
+			return shorthand.scoped
+		}
+	}
+
+	return []string{event}
+}
+
+// MarshalYAML implements the marshaler interface for the Rules type.
+func (r Rules) MarshalYAML() (interface{}, error) {
+	// marshal the rules as a string when an expression is provided
+	if len(r.Expression) > 0 {
+		return r.Expression, nil
+	}
+
+	// alias type to avoid recursively calling this function
+	type rules Rules
+
+	return rules(r), nil
+}
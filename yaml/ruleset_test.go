@@ -129,6 +129,59 @@ func TestYaml_Ruleset_UnmarshalYAML(t *testing.T) {
 				Continue: true,
 			},
 		},
+		{
+			file: "testdata/ruleset_expression.yml",
+			want: &Ruleset{
+				If: Rules{
+					Expression: "branch == 'main' && (event == 'push' || matches(tag, '^refs/tags/v'))",
+				},
+				Unless: Rules{
+					Expression: "contains(comment, 'skip') || env.DEPLOY == 'false'",
+				},
+				Matcher:  "filepath",
+				Operator: "or",
+			},
+		},
+		{
+			file: "testdata/ruleset_commit.yml",
+			want: &Ruleset{
+				If: Rules{
+					Event:        []string{},
+					Author:       []string{"dependabot[[]bot[]]", "renovate[[]bot[]]"},
+					Message:      []string{"docs:*"},
+					BaseRef:      []string{"main"},
+					ChangedFiles: []string{"<= 20"},
+				},
+				Unless: Rules{
+					Event:        []string{},
+					Sender:       []string{"octocat"},
+					ChangedFiles: []string{"many"},
+				},
+				Matcher:  "filepath",
+				Operator: "and",
+			},
+		},
+		{
+			file: "testdata/ruleset_env.yml",
+			want: &Ruleset{
+				If: Rules{
+					Branch: []string{"main"},
+					Event:  []string{},
+					Env: map[string][]string{
+						"DEPLOY_ENABLED":   {"true"},
+						"VELA_REPO_TOPICS": {"*cloud*", "*security*"},
+					},
+				},
+				Unless: Rules{
+					Event: []string{},
+					Env: map[string][]string{
+						"SKIP_DEPLOY": {"true"},
+					},
+				},
+				Matcher:  "filepath",
+				Operator: "and",
+			},
+		},
 		{
 			file: "testdata/ruleset_regex.yml",
 			want: &Ruleset{
@@ -194,6 +247,18 @@ func TestYaml_Rules_ToPipeline(t *testing.T) {
 				Label:   []string{"enhancement"},
 			},
 		},
+		{
+			rules: &Rules{
+				Author:       []string{"*[bot]"},
+				ChangedFiles: []string{"<10"},
+				Env:          map[string][]string{"DEPLOY_ENABLED": {"true"}},
+			},
+			want: &pipeline.Rules{
+				Author:       []string{"*[bot]"},
+				ChangedFiles: []string{"<10"},
+				Env:          map[string]pipeline.Ruletype{"DEPLOY_ENABLED": {"true"}},
+			},
+		},
 	}
 
 	// run tests
@@ -272,3 +337,100 @@ func TestYaml_Rules_UnmarshalYAML(t *testing.T) {
 		}
 	}
 }
+
+func TestYaml_Ruleset_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		ruleset *Ruleset
+		want    []string
+	}{
+		{
+			ruleset: &Ruleset{
+				If:     Rules{Expression: "branch == 'main' && event in ['push', 'tag']"},
+				Unless: Rules{Expression: "startsWith(env.TARGET, 'prod')"},
+			},
+			want: []string{},
+		},
+		{
+			ruleset: &Ruleset{
+				If:     Rules{Expression: "branch == 'main' &&"},
+				Unless: Rules{Expression: "branch"},
+			},
+			want: []string{
+				"error: ruleset.if: invalid expression \"branch == 'main' &&\" at column 20: unexpected end of expression",
+				"error: ruleset.unless: invalid expression \"branch\" at column 1: expression must produce a boolean, got string",
+			},
+		},
+		{
+			ruleset: &Ruleset{
+				If:     Rules{ChangedFiles: []string{"<10", "> 100"}, Author: []string{"*[bot]"}},
+				Unless: Rules{ChangedFiles: []string{"many"}},
+			},
+			want: []string{
+				"error: ruleset.unless.changed_files[0]: invalid changed files threshold: many",
+			},
+		},
+		{
+			ruleset: &Ruleset{
+				If:      Rules{Path: []string{"src/**/*.go", "!src/**/*_test.go"}, Branch: []string{"{main,dev"}},
+				Unless:  Rules{Tag: []string{"v[0-9"}},
+				Matcher: "glob",
+			},
+			want: []string{
+				"error: ruleset.if.branch[0]: invalid glob pattern {main,dev: unterminated brace expansion",
+				"error: ruleset.unless.tag[0]: invalid glob pattern v[0-9: unterminated character class",
+			},
+		},
+		{
+			ruleset: &Ruleset{
+				If:      Rules{Branch: []string{"^main$", "release/("}},
+				Unless:  Rules{Env: map[string][]string{"DEPLOY.TARGET": {"prod-[0-9"}}},
+				Matcher: "regexp",
+			},
+			want: []string{
+				"error: ruleset.if.branch[1]: error in regex pattern release/(: error parsing regexp: missing closing ): `release/(`",
+				"error: ruleset.unless.env.\"DEPLOY.TARGET\"[0]: error in regex pattern prod-[0-9: error parsing regexp: missing closing ]: `[0-9`",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := []string{}
+
+		for _, diagnostic := range test.ruleset.validate("ruleset") {
+			got = append(got, diagnostic.String())
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("validate is %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestYaml_Rules_MarshalYAML(t *testing.T) {
+	// setup types
+	want := &Ruleset{
+		If:       Rules{Expression: "branch == 'main'"},
+		Unless:   Rules{Event: []string{"tag"}},
+		Matcher:  "filepath",
+		Operator: "and",
+	}
+
+	// run test
+	out, err := yaml.Marshal(want)
+	if err != nil {
+		t.Errorf("MarshalYAML returned err: %v", err)
+	}
+
+	got := new(Ruleset)
+
+	err = yaml.Unmarshal(out, got)
+	if err != nil {
+		t.Errorf("UnmarshalYAML returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalYAML is %v, want %v", got, want)
+	}
+}
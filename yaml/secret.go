@@ -139,6 +139,64 @@ func (s *SecretSlice) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// Validate verifies the yaml for every secret in the
+// SecretSlice type and returns every problem found.
+func (s *SecretSlice) Validate() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// iterate through each secret in the secret slice
+	for i, secret := range *s {
+		secretPath := indexPath("secrets", i)
+
+		// verify the secret plugin when an origin is provided
+		if !secret.Origin.Empty() {
+			originPath := joinPath(secretPath, "origin")
+
+			// verify a name was provided for the secret origin
+			if len(secret.Origin.Name) == 0 {
+				diagnostics.errorf(joinPath(originPath, "name"), "no name provided for secret origin")
+			}
+
+			// verify an image was provided for the secret origin
+			if len(secret.Origin.Image) == 0 {
+				diagnostics.errorf(joinPath(originPath, "image"), "no image provided for secret origin %s", secret.Origin.Name)
+			}
+
+			// verify the pull policy for the secret origin
+			if len(secret.Origin.Pull) > 0 && !validPull(secret.Origin.Pull) {
+				diagnostics.errorf(joinPath(originPath, "pull"), "invalid pull policy %s for secret origin %s", secret.Origin.Pull, secret.Origin.Name)
+			}
+
+			diagnostics = append(diagnostics, secret.Origin.Ruleset.validate(joinPath(originPath, "ruleset"))...)
+
+			continue
+		}
+
+		// verify the engine for the secret
+		switch secret.Engine {
+		case constants.DriverNative, constants.DriverVault:
+		default:
+			diagnostics.errorf(joinPath(secretPath, "engine"), "invalid engine %s for secret %s", secret.Engine, secret.Name)
+		}
+
+		// verify the type for the secret
+		switch secret.Type {
+		case constants.SecretRepo, constants.SecretOrg, constants.SecretShared:
+		default:
+			diagnostics.errorf(joinPath(secretPath, "type"), "invalid type %s for secret %s", secret.Type, secret.Name)
+		}
+
+		// verify the pull policy for the secret
+		switch secret.Pull {
+		case constants.SecretPullBuild, constants.SecretPullStep:
+		default:
+			diagnostics.errorf(joinPath(secretPath, "pull"), "invalid pull policy %s for secret %s", secret.Pull, secret.Name)
+		}
+	}
+
+	return diagnostics
+}
+
 // Empty returns true if the provided origin is empty.
 func (o *Origin) Empty() bool {
 	// return true if the origin is nil
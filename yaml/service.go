@@ -31,6 +31,14 @@ type (
 		Pull        string             `yaml:"pull,omitempty"        json:"pull,omitempty" jsonschema:"enum=always,enum=not_present,enum=on_start,enum=never,default=not_present,description=Declaration to configure if and when the Docker image is pulled.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-pul-key"`
 		Ulimits     UlimitSlice        `yaml:"ulimits,omitempty"     json:"ulimits,omitempty" jsonschema:"description=Set the user limits for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-ulimits-key"`
 		User        string             `yaml:"user,omitempty"        json:"user,omitempty" jsonschema:"description=Set the user for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-user-key"`
+		// Timeout is a duration, like 90s or 1h30m, or a number
+		// of minutes when no unit is provided, like 10. An invalid
+		// timeout is ignored by ToPipeline and only reported by
+		// Validate, so the service runs without a timeout.
+		Timeout      string       `yaml:"timeout,omitempty"     json:"timeout,omitempty" jsonschema:"example=10m,description=Maximum time the container may run before it is stopped.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-timeout-key"`
+		Retries      int          `yaml:"retries,omitempty"     json:"retries,omitempty" jsonschema:"minimum=0,maximum=10,description=Number of times to restart the container when it fails.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-retries-key"`
+		RetryBackoff RetryBackoff `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty" jsonschema:"description=Delay to wait between restarts of the container.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-retry_backoff-key"`
+		Resources    Resources    `yaml:"resources,omitempty"     json:"resources,omitempty" jsonschema:"description=Compute resources requested by and allowed for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/services/#the-resources-key"`
 	}
 )
 
@@ -42,17 +50,24 @@ func (s *ServiceSlice) ToPipeline() *pipeline.ContainerSlice {
 
 	// iterate through each element in the service slice
 	for _, service := range *s {
+		// invalid timeouts are reported when validating the yaml
+		timeout, _ := parseDuration(service.Timeout)
+
 		// append the element to the pipeline container slice
 		*serviceSlice = append(*serviceSlice, &pipeline.Container{
-			Detach:      true,
-			Image:       service.Image,
-			Name:        service.Name,
-			Entrypoint:  service.Entrypoint,
-			Environment: service.Environment,
-			Ports:       service.Ports,
-			Pull:        service.Pull,
-			Ulimits:     *service.Ulimits.ToPipeline(),
-			User:        service.User,
+			Detach:       true,
+			Image:        service.Image,
+			Name:         service.Name,
+			Entrypoint:   service.Entrypoint,
+			Environment:  service.Environment,
+			Ports:        service.Ports,
+			Pull:         service.Pull,
+			Ulimits:      *service.Ulimits.ToPipeline(),
+			User:         service.User,
+			Timeout:      timeout,
+			Retries:      service.Retries,
+			RetryBackoff: service.RetryBackoff.ToPipeline(),
+			Resources:    service.Resources.ToPipeline(),
 		})
 	}
 
@@ -109,6 +124,44 @@ func (s *ServiceSlice) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// Validate verifies the yaml for every service in the
+// ServiceSlice type and returns every problem found.
+func (s *ServiceSlice) Validate() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture the index for every service name
+	names := make(map[string]int)
+
+	// iterate through each service in the service slice
+	for i, service := range *s {
+		servicePath := indexPath("services", i)
+
+		// verify a name was provided for the service
+		if len(service.Name) == 0 {
+			diagnostics.errorf(joinPath(servicePath, "name"), "no name provided")
+		} else if first, ok := names[service.Name]; ok {
+			diagnostics.errorf(joinPath(servicePath, "name"), "duplicate service name %s (first declared at %s)", service.Name, indexPath("services", first))
+		} else {
+			names[service.Name] = i
+		}
+
+		// verify an image was provided for the service
+		if len(service.Image) == 0 {
+			diagnostics.errorf(joinPath(servicePath, "image"), "no image provided for service %s", service.Name)
+		}
+
+		// verify the pull policy for the service
+		if len(service.Pull) > 0 && !validPull(service.Pull) {
+			diagnostics.errorf(joinPath(servicePath, "pull"), "invalid pull policy %s for service %s", service.Pull, service.Name)
+		}
+
+		diagnostics = append(diagnostics, validateRetry(servicePath, "service "+service.Name, service.Timeout, service.Retries, &service.RetryBackoff)...)
+		diagnostics = append(diagnostics, service.Resources.validate(joinPath(servicePath, "resources"), "service "+service.Name)...)
+	}
+
+	return diagnostics
+}
+
 // MergeEnv takes a list of environment variables and attempts
 // to set them in the service environment. If the environment
 // variable already exists in the service, than this will
@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// SourceMap is a representation of the raw YAML document
+// for a pipeline that is able to resolve a path, like
+// `stages.test.steps[2].pull`, to a position in the document.
+// A key containing a dot or bracket is quoted in the path,
+// like `stages."s.x".steps[2].pull`. A path to the if rules
+// of a ruleset, like `steps[0].ruleset.if.branch`, resolves
+// to the rules defined on a simple ruleset as well.
+type SourceMap struct {
+	root *yamlv3.Node
+}
+
+// NewSourceMap parses the provided raw YAML document
+// and returns a SourceMap for resolving positions.
+func NewSourceMap(data []byte) (*SourceMap, error) {
+	root := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(data, root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse source document: %w", err)
+	}
+
+	return &SourceMap{root: root}, nil
+}
+
+// Locate returns the line and column for the provided path in
+// the document. When the full path can not be found, the position
+// of the deepest element of the path that exists is returned. When
+// nothing can be resolved, the function returns zero for both.
+func (s *SourceMap) Locate(path string) (int, int) {
+	node := s.Node(path)
+	if node == nil {
+		return 0, 0
+	}
+
+	return node.Line, node.Column
+}
+
+// Node returns the deepest node from the document that can be
+// resolved for the provided path. For a mapping key, the node
+// for the key is returned so the position points at the key.
+func (s *SourceMap) Node(path string) *yamlv3.Node {
+	// return nothing if the source map is empty
+	if s == nil || s.root == nil {
+		return nil
+	}
+
+	node := resolve(s.root)
+	if node == nil {
+		return nil
+	}
+
+	// capture the closest node we have found so far
+	closest := node
+
+	// capture the name of the previous segment
+	parent := ""
+
+	for _, segment := range splitPath(path) {
+		// handle the segment as an index into a sequence
+		if index, ok := segment.index(); ok {
+			if node.Kind != yamlv3.SequenceNode || index < 0 || index >= len(node.Content) {
+				return closest
+			}
+
+			node = resolve(node.Content[index])
+			closest = node
+			parent = ""
+
+			continue
+		}
+
+		// handle the segment as a key in a mapping
+		key, value := lookup(node, segment.name)
+
+		// the rules for a simple ruleset are defined on the ruleset
+		if key == nil && segment.name == "if" && parent == "ruleset" {
+			parent = segment.name
+
+			continue
+		}
+
+		if key == nil {
+			return closest
+		}
+
+		node = value
+		closest = key
+		parent = segment.name
+	}
+
+	return closest
+}
+
+// pathSegment represents one element of a path
+// which is either a mapping key or a sequence index.
+type pathSegment struct {
+	name    string
+	isIndex bool
+}
+
+// index returns the sequence index for the segment.
+func (p pathSegment) index() (int, bool) {
+	if !p.isIndex {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(p.name)
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// splitPath is a helper function to break a path like
+// `stages.test.steps[2].pull` into its segments. A key
+// quoted with double quotes, like `stages."s.x".steps`,
+// may contain dots and brackets.
+func splitPath(path string) []pathSegment {
+	segments := []pathSegment{}
+
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '"':
+			// capture the quoted key
+			quoted, err := strconv.QuotedPrefix(path[i:])
+			if err != nil {
+				segments = append(segments, pathSegment{name: path[i:]})
+
+				return segments
+			}
+
+			name, _ := strconv.Unquote(quoted)
+
+			segments = append(segments, pathSegment{name: name})
+
+			i += len(quoted)
+		case '[':
+			// capture the index
+			end := strings.Index(path[i:], "]")
+			if end < 0 {
+				segments = append(segments, pathSegment{name: path[i:]})
+
+				return segments
+			}
+
+			segments = append(segments, pathSegment{name: path[i+1 : i+end], isIndex: true})
+
+			i += end + 1
+		default:
+			// capture the key up to the next separator
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+
+			segments = append(segments, pathSegment{name: path[i : i+end]})
+
+			i += end
+		}
+	}
+
+	return segments
+}
+
+// resolve is a helper function to return the underlying
+// content for document and alias nodes.
+func resolve(node *yamlv3.Node) *yamlv3.Node {
+	for node != nil {
+		switch node.Kind {
+		case yamlv3.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+
+			node = node.Content[0]
+		case yamlv3.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+
+	return nil
+}
+
+// lookup is a helper function to find the key and value nodes
+// for the provided name in a mapping node. Keys brought into
+// the mapping with a `<<` merge key are searched as well.
+func lookup(node *yamlv3.Node, name string) (*yamlv3.Node, *yamlv3.Node) {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil, nil
+	}
+
+	// keys defined directly on the mapping take precedence
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == name && node.Content[i].Tag != "!!merge" {
+			return node.Content[i], resolve(node.Content[i+1])
+		}
+	}
+
+	// fall back to the keys from any merged mappings
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Tag != "!!merge" {
+			continue
+		}
+
+		merged := resolve(node.Content[i+1])
+		if merged == nil {
+			continue
+		}
+
+		switch merged.Kind {
+		case yamlv3.MappingNode:
+			key, value := lookup(merged, name)
+			if key != nil {
+				return key, value
+			}
+		case yamlv3.SequenceNode:
+			for _, m := range merged.Content {
+				key, value := lookup(resolve(m), name)
+				if key != nil {
+					return key, value
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"os"
+	"testing"
+)
+
+func TestYaml_SourceMap_Locate(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/build_anchor_stage.yml")
+	if err != nil {
+		t.Errorf("unable to read file: %v", err)
+	}
+
+	source, err := NewSourceMap(data)
+	if err != nil {
+		t.Errorf("NewSourceMap returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		path   string
+		line   int
+		column int
+	}{
+		{path: "version", line: 2, column: 1},
+		{path: "stages.test", line: 26, column: 3},
+		{path: "stages.test.needs[0]", line: 27, column: 14},
+		{path: "stages.test.steps[0].pull", line: 33, column: 9},
+		// key brought in from the merge key
+		{path: "stages.test.steps[0].image", line: 11, column: 3},
+		// deepest element that exists
+		{path: "stages.test.steps[0].privileged", line: 29, column: 9},
+		{path: "stages.test.steps[4]", line: 28, column: 5},
+		{path: "", line: 2, column: 1},
+	}
+
+	// run tests
+	for _, test := range tests {
+		line, column := source.Locate(test.path)
+
+		if line != test.line || column != test.column {
+			t.Errorf("Locate for %s is %d:%d, want %d:%d", test.path, line, column, test.line, test.column)
+		}
+	}
+}
+
+func TestYaml_SourceMap_Locate_Quoted(t *testing.T) {
+	// setup types
+	source, err := NewSourceMap([]byte("stages:\n  s.x:\n    steps:\n      - name: test\n        env.ID: 1\n"))
+	if err != nil {
+		t.Errorf("NewSourceMap returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		path   string
+		line   int
+		column int
+	}{
+		{path: joinPath("stages", "s.x"), line: 2, column: 3},
+		{path: joinPath(indexPath(joinPath(joinPath("stages", "s.x"), "steps"), 0), "env.ID"), line: 5, column: 9},
+		{path: `stages."s.x".steps[0].name`, line: 4, column: 9},
+		// unterminated quote
+		{path: `stages."s.x`, line: 1, column: 1},
+	}
+
+	// run tests
+	for _, test := range tests {
+		line, column := source.Locate(test.path)
+
+		if line != test.line || column != test.column {
+			t.Errorf("Locate for %s is %d:%d, want %d:%d", test.path, line, column, test.line, test.column)
+		}
+	}
+}
+
+func TestYaml_SourceMap_Locate_Ruleset(t *testing.T) {
+	// setup types
+	source, err := NewSourceMap([]byte("steps:\n  - name: simple\n    ruleset:\n      status: [ failure ]\n  - name: advanced\n    ruleset:\n      if:\n        status: [ failure ]\n"))
+	if err != nil {
+		t.Errorf("NewSourceMap returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		path   string
+		line   int
+		column int
+	}{
+		{path: "steps[0].ruleset.if.status", line: 4, column: 7},
+		{path: "steps[0].ruleset.status", line: 4, column: 7},
+		{path: "steps[1].ruleset.if.status", line: 8, column: 9},
+		{path: "steps[1].ruleset.unless.status", line: 6, column: 5},
+	}
+
+	// run tests
+	for _, test := range tests {
+		line, column := source.Locate(test.path)
+
+		if line != test.line || column != test.column {
+			t.Errorf("Locate for %s is %d:%d, want %d:%d", test.path, line, column, test.line, test.column)
+		}
+	}
+}
+
+func TestYaml_NewSourceMap_Invalid(t *testing.T) {
+	_, err := NewSourceMap([]byte("steps: [ foo"))
+	if err == nil {
+		t.Errorf("NewSourceMap should have returned err")
+	}
+}
@@ -28,26 +28,65 @@ type (
 		Needs       raw.StringSlice    `yaml:"needs,omitempty,flow"  json:"needs,omitempty"       jsonschema:"description=Stages that must complete before starting the current one.\nReference: https://go-vela.github.io/docs/reference/yaml/stages/#the-needs-key"`
 		Independent bool               `yaml:"independent,omitempty" json:"independent,omitempty" jsonschema:"description=Stage will continue executing if other stage fails"`
 		Steps       StepSlice          `yaml:"steps,omitempty"       json:"steps,omitempty"       jsonschema:"required,description=Sequential execution instructions for the stage.\nReference: https://go-vela.github.io/docs/reference/yaml/stages/#the-steps-key"`
+		Matrix      Matrix             `yaml:"matrix,omitempty"      json:"matrix,omitempty"      jsonschema:"description=Run the stage once for every combination of values.\nReference: https://go-vela.github.io/docs/reference/yaml/stages/#the-matrix-key"`
 	}
 )
 
 // ToPipeline converts the StageSlice type
-// to a pipeline StageSlice type.
+// to a pipeline StageSlice type. A stage
+// with a matrix is expanded to one stage
+// for every combination of the matrix.
 func (s *StageSlice) ToPipeline() *pipeline.StageSlice {
 	// stage slice we want to return
 	stageSlice := new(pipeline.StageSlice)
 
+	// capture the names every matrix stage was expanded to
+	expanded := make(map[string][]string)
+
 	// iterate through each element in the stage slice
 	for _, stage := range *s {
 		// append the element to the pipeline stage slice
-		*stageSlice = append(*stageSlice, &pipeline.Stage{
-			Done:        make(chan error, 1),
-			Environment: stage.Environment,
-			Name:        stage.Name,
-			Needs:       stage.Needs,
-			Independent: stage.Independent,
-			Steps:       *stage.Steps.ToPipeline(),
-		})
+		if stage.Matrix.Empty() {
+			*stageSlice = append(*stageSlice, &pipeline.Stage{
+				Done:        make(chan error, 1),
+				Environment: stage.Environment,
+				Name:        stage.Name,
+				Needs:       stage.Needs,
+				Independent: stage.Independent,
+				Steps:       *stage.Steps.ToPipeline(),
+			})
+
+			continue
+		}
+
+		// append an element for every combination of the matrix
+		for _, combination := range stage.Matrix.Combinations() {
+			name := stage.Matrix.Name(stage.Name, combination)
+			env := stage.Matrix.Environment(combination)
+
+			steps := *stage.Steps.ToPipeline()
+
+			// inject the matrix values into every step for the stage
+			for _, step := range steps {
+				step.Environment = mergeEnvironment(step.Environment, env)
+			}
+
+			*stageSlice = append(*stageSlice, &pipeline.Stage{
+				Done:        make(chan error, 1),
+				Environment: mergeEnvironment(stage.Environment, env),
+				Name:        name,
+				Needs:       stage.Needs,
+				Independent: stage.Independent,
+				Steps:       steps,
+			})
+
+			expanded[stage.Name] = append(expanded[stage.Name], name)
+		}
+	}
+
+	// replace the needs on the expanded matrix stages
+	for _, stage := range *stageSlice {
+		stage.Needs = expandNeeds(stage.Needs, expanded)
 	}
 
 	return stageSlice
@@ -120,6 +159,9 @@ func (s StageSlice) MarshalYAML() (interface{}, error) {
 		// add the existing steps to the new stage
 		outputStage.Steps = inputStage.Steps
 
+		// add the existing matrix to the new stage
+		outputStage.Matrix = inputStage.Matrix
+
 		// append stage to MapSlice
 		output = append(output, yaml.MapItem{Key: inputStage.Name, Value: outputStage})
 	}
@@ -127,6 +169,46 @@ func (s StageSlice) MarshalYAML() (interface{}, error) {
 	return output, nil
 }
 
+// Validate verifies the yaml for every stage in the StageSlice
+// type, including the steps for each stage, and returns every
+// problem found.
+func (s *StageSlice) Validate() Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture every stage name
+	names := make(map[string]bool)
+
+	// iterate through each stage in the stage slice
+	for _, stage := range *s {
+		stagePath := joinPath("stages", stage.Name)
+
+		// verify the stage name is unique
+		if names[stage.Name] {
+			diagnostics.errorf(stagePath, "duplicate stage name %s", stage.Name)
+		}
+
+		names[stage.Name] = true
+
+		// verify the matrix for the stage
+		if !stage.Matrix.Empty() {
+			diagnostics = append(diagnostics, stage.Matrix.validate(joinPath(stagePath, "matrix"), stage.Name)...)
+		}
+
+		// verify steps were provided for the stage
+		if len(stage.Steps) == 0 {
+			diagnostics.errorf(joinPath(stagePath, "steps"), "no steps provided for stage %s", stage.Name)
+
+			continue
+		}
+
+		diagnostics = append(diagnostics, stage.Steps.validate(joinPath(stagePath, "steps"))...)
+	}
+
+	diagnostics = append(diagnostics, s.validateConsume()...)
+
+	return diagnostics
+}
+
 // MergeEnv takes a list of environment variables and attempts
 // to set them in the stage environment. If the environment
 // variable already exists in the stage, than this will
@@ -0,0 +1,421 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/buildkite/yaml"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+	"go.starlark.net/starlarkstruct"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/library"
+)
+
+var (
+	// ErrStarlarkMain defines the error type when a Starlark
+	// program does not define a callable main function.
+	ErrStarlarkMain = errors.New("starlark program does not define a main function")
+
+	// ErrStarlarkResult defines the error type when the main
+	// function of a Starlark program returns an unsupported value.
+	ErrStarlarkResult = errors.New("starlark main function must return a dict or list")
+
+	// ErrStarlarkExecLimit defines the error type when a Starlark
+	// program exceeds the maximum number of execution steps.
+	ErrStarlarkExecLimit = errors.New("starlark execution step limit exceeded")
+
+	// ErrStarlarkOutputLimit defines the error type when the pipeline
+	// returned by a Starlark program exceeds the maximum size.
+	ErrStarlarkOutputLimit = errors.New("starlark output size limit exceeded")
+)
+
+// Starlark evaluates Starlark programs to a Build type.
+//
+// A program runs in a sandbox without access to the host: loading
+// other modules is not allowed, output from print is discarded and
+// the execution steps and size of the returned pipeline are limited.
+type Starlark struct {
+	build       *library.Build
+	repo        *library.Repo
+	execLimit   uint64
+	outputLimit int
+}
+
+// NewStarlark returns a Starlark evaluator exposing the provided build
+// and repo, which may be nil, to the programs it evaluates with the
+// default limits for the execution steps and size of the output.
+func NewStarlark(b *library.Build, r *library.Repo) *Starlark {
+	return &Starlark{
+		build:       b,
+		repo:        r,
+		execLimit:   constants.StarlarkExecLimitDefault,
+		outputLimit: constants.StarlarkOutputLimitDefault,
+	}
+}
+
+// SetExecLimit sets the maximum number of execution steps for a program.
+func (s *Starlark) SetExecLimit(limit uint64) {
+	s.execLimit = limit
+}
+
+// SetOutputLimit sets the maximum size in bytes for the pipeline returned by a program.
+func (s *Starlark) SetOutputLimit(limit int) {
+	s.outputLimit = limit
+}
+
+// Evaluate runs the provided Starlark program and unmarshals the value
+// returned by its main function to a Build type. The main function is
+// called with a `ctx` dict with the `build` and `repo` for the evaluator
+// and the provided `vars`. The main function returns the pipeline as a
+// dict or, for a template, the list of steps.
+func (s *Starlark) Evaluate(name string, source []byte, variables map[string]interface{}) (*Build, error) {
+	exceeded := false
+
+	thread := &starlark.Thread{
+		Name: name,
+		// discard the output from the program
+		Print: func(*starlark.Thread, string) {},
+		// stop the program when the limit is exceeded
+		OnMaxSteps: func(thread *starlark.Thread) {
+			exceeded = true
+
+			thread.Cancel("too many steps")
+		},
+	}
+
+	// verify a limit is always set since zero disables the limit
+	thread.SetMaxExecutionSteps(max(s.execLimit, 1))
+
+	predeclared := starlark.StringDict{
+		"struct": starlark.NewBuiltin("struct", starlarkstruct.Make),
+		"json":   starlarkjson.Module,
+	}
+
+	globals, err := starlark.ExecFile(thread, name, source, predeclared)
+	if err != nil {
+		return nil, s.limitError(exceeded, err)
+	}
+
+	main, ok := globals["main"].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStarlarkMain, name)
+	}
+
+	ctx, err := s.context(thread, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := starlark.Call(thread, main, starlark.Tuple{ctx}, nil)
+	if err != nil {
+		return nil, s.limitError(exceeded, err)
+	}
+
+	// wrap the steps returned for a template in a pipeline
+	switch v := value.(type) {
+	case *starlark.Dict:
+	case *starlark.List:
+		pipeline := starlark.NewDict(2)
+
+		_ = pipeline.SetKey(starlark.String("version"), starlark.String("1"))
+		_ = pipeline.SetKey(starlark.String("steps"), v)
+
+		value = pipeline
+	default:
+		return nil, fmt.Errorf("%w: %s returned %s", ErrStarlarkResult, name, value.Type())
+	}
+
+	buffer := &limitedBuffer{limit: s.outputLimit, err: ErrStarlarkOutputLimit}
+
+	// encode the returned value as a JSON document within the size limit
+	err = (&starlarkEncoder{buffer: buffer}).encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the pipeline from %s: %w", name, err)
+	}
+
+	b := new(Build)
+
+	// unmarshal the JSON document, which is valid YAML, as a build type
+	err = yaml.Unmarshal(buffer.Bytes(), b)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the pipeline from %s: %w", name, err)
+	}
+
+	return b, nil
+}
+
+// context is a helper function to create the `ctx` dict
+// provided to the main function of a Starlark program.
+func (s *Starlark) context(thread *starlark.Thread, variables map[string]interface{}) (*starlark.Dict, error) {
+	build := s.build
+	if build == nil {
+		build = new(library.Build)
+	}
+
+	repo := s.repo
+	if repo == nil {
+		repo = new(library.Repo)
+	}
+
+	vars := variables
+	if vars == nil {
+		vars = make(map[string]interface{})
+	}
+
+	ctx := starlark.NewDict(3)
+
+	for _, field := range []struct {
+		key   string
+		value interface{}
+	}{
+		{key: "build", value: build},
+		{key: "repo", value: repo},
+		{key: "vars", value: fnJSONValue(vars)},
+	} {
+		value, err := starlarkValue(thread, field.value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to provide %s to the starlark program: %w", field.key, err)
+		}
+
+		err = ctx.SetKey(starlark.String(field.key), value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ctx, nil
+}
+
+// limitError is a helper function to return the error
+// for a program that exceeded the execution step limit.
+func (s *Starlark) limitError(exceeded bool, err error) error {
+	if exceeded {
+		return fmt.Errorf("%w: %d steps", ErrStarlarkExecLimit, s.execLimit)
+	}
+
+	return err
+}
+
+// starlarkValue is a helper function to convert the provided
+// value to a Starlark value through its JSON representation.
+func starlarkValue(thread *starlark.Thread, value interface{}) (starlark.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.Call(thread, starlarkjson.Module.Members["decode"], starlark.Tuple{starlark.String(data)}, nil)
+}
+
+// starlarkEncoder encodes the value returned by a Starlark program
+// as a JSON document to a buffer. The size of every value is checked
+// against the limit for the buffer before it is encoded, so an oversized
+// string, bytes, list or dict is rejected without copying it.
+type starlarkEncoder struct {
+	buffer *limitedBuffer
+	// path holds the values being encoded to detect
+	// a value containing itself
+	path []starlark.Value
+}
+
+// encode is a helper function to encode the provided value.
+func (e *starlarkEncoder) encode(value starlark.Value) error {
+	// verify the value does not contain itself
+	switch value.(type) {
+	case *starlark.List, *starlark.Dict, *starlarkstruct.Struct:
+		for _, v := range e.path {
+			if v == value {
+				return fmt.Errorf("cycle in %s", value.Type())
+			}
+		}
+
+		e.path = append(e.path, value)
+		defer func() { e.path = e.path[:len(e.path)-1] }()
+	}
+
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return e.write("null")
+	case starlark.Bool:
+		return e.write(strconv.FormatBool(bool(v)))
+	case starlark.Int:
+		return e.write(v.String())
+	case starlark.Float:
+		if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+			return fmt.Errorf("unable to encode non-finite float %v", v)
+		}
+
+		return e.write(v.String())
+	case starlark.String:
+		return e.encodeString(string(v))
+	case starlark.Bytes:
+		return e.encodeString(string(v))
+	case starlark.IterableMapping:
+		return e.encodeMapping(v)
+	case starlark.Sequence:
+		return e.encodeSequence(v)
+	case starlark.HasAttrs:
+		return e.encodeAttrs(v)
+	default:
+		return fmt.Errorf("unable to encode %s as JSON", value.Type())
+	}
+}
+
+// encodeString is a helper function to encode the provided
+// string as a JSON string when it is within the size limit.
+func (e *starlarkEncoder) encodeString(s string) error {
+	// verify the string is within the size limit before quoting it
+	err := e.buffer.check(len(s) + 2)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.buffer.Write(data)
+
+	return err
+}
+
+// encodeMapping is a helper function to encode the provided dict,
+// which must have string keys, as a JSON object with sorted keys.
+func (e *starlarkEncoder) encodeMapping(v starlark.IterableMapping) error {
+	items := v.Items()
+
+	// verify the dict is within the size limit before encoding it
+	err := e.buffer.check(2 + 4*len(items))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if _, ok := item[0].(starlark.String); !ok {
+			return fmt.Errorf("%s has %s key, want string", v.Type(), item[0].Type())
+		}
+	}
+
+	// encode the keys in a consistent order
+	sort.Slice(items, func(i, j int) bool {
+		return items[i][0].(starlark.String) < items[j][0].(starlark.String)
+	})
+
+	err = e.write("{")
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		err = e.field(i, string(item[0].(starlark.String)), item[1])
+		if err != nil {
+			return fmt.Errorf("in %s key %s: %w", v.Type(), item[0], err)
+		}
+	}
+
+	return e.write("}")
+}
+
+// encodeSequence is a helper function to encode the
+// provided list or tuple as a JSON array.
+func (e *starlarkEncoder) encodeSequence(v starlark.Sequence) error {
+	// verify the list is within the size limit before encoding it
+	err := e.buffer.check(2 + 2*v.Len())
+	if err != nil {
+		return err
+	}
+
+	err = e.write("[")
+	if err != nil {
+		return err
+	}
+
+	iter := v.Iterate()
+	defer iter.Done()
+
+	var elem starlark.Value
+
+	for i := 0; iter.Next(&elem); i++ {
+		if i > 0 {
+			err = e.write(",")
+			if err != nil {
+				return err
+			}
+		}
+
+		err = e.encode(elem)
+		if err != nil {
+			return fmt.Errorf("at %s index %d: %w", v.Type(), i, err)
+		}
+	}
+
+	return e.write("]")
+}
+
+// encodeAttrs is a helper function to encode the provided
+// struct as a JSON object with sorted attribute names.
+func (e *starlarkEncoder) encodeAttrs(v starlark.HasAttrs) error {
+	names := v.AttrNames()
+
+	// encode the attributes in a consistent order
+	sort.Strings(names)
+
+	err := e.write("{")
+	if err != nil {
+		return err
+	}
+
+	for i, name := range names {
+		attr, err := v.Attr(name)
+		if err != nil || attr == nil {
+			return fmt.Errorf("unable to access attribute %s.%s", v.Type(), name)
+		}
+
+		err = e.field(i, name, attr)
+		if err != nil {
+			return fmt.Errorf("in field .%s: %w", name, err)
+		}
+	}
+
+	return e.write("}")
+}
+
+// field is a helper function to encode the provided
+// key and value as the field at the index of an object.
+func (e *starlarkEncoder) field(index int, key string, value starlark.Value) error {
+	if index > 0 {
+		err := e.write(",")
+		if err != nil {
+			return err
+		}
+	}
+
+	err := e.encodeString(key)
+	if err != nil {
+		return err
+	}
+
+	err = e.write(":")
+	if err != nil {
+		return err
+	}
+
+	return e.encode(value)
+}
+
+// write is a helper function to write the provided
+// string to the buffer within the size limit.
+func (e *starlarkEncoder) write(s string) error {
+	_, err := e.buffer.Write([]byte(s))
+
+	return err
+}
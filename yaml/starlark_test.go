@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/types/library"
+	"github.com/go-vela/types/raw"
+)
+
+func TestYaml_Starlark_Evaluate(t *testing.T) {
+	// setup types
+	b := new(library.Build)
+	b.SetEvent("push")
+	b.SetBranch("main")
+	b.SetCommit("a1b2c3d4e5f6")
+
+	r := new(library.Repo)
+	r.SetFullName("github/octocat")
+	r.SetBranch("main")
+
+	// setup tests
+	tests := []struct {
+		file      string
+		variables map[string]interface{}
+		want      []string
+	}{
+		{
+			file:      "testdata/starlark/pipeline.star",
+			variables: map[string]interface{}{"version": "1.22"},
+			want:      []string{"test", "publish"},
+		},
+		{
+			file: "testdata/starlark/steps.star",
+			variables: map[string]interface{}{
+				"names": []interface{}{"lint", "test"},
+			},
+			want: []string{"lint", "test"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			source, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Fatalf("unable to read file: %v", err)
+			}
+
+			got, err := NewStarlark(b, r).Evaluate(test.file, source, test.variables)
+			if err != nil {
+				t.Fatalf("Evaluate returned err: %v", err)
+			}
+
+			if got.Version != "1" {
+				t.Errorf("Evaluate version is %s, want 1", got.Version)
+			}
+
+			steps := []string{}
+			for _, step := range got.Steps {
+				steps = append(steps, step.Name)
+			}
+
+			if !reflect.DeepEqual(steps, test.want) {
+				t.Errorf("Evaluate steps are %v, want %v", steps, test.want)
+			}
+		})
+	}
+}
+
+func TestYaml_Starlark_Evaluate_Context(t *testing.T) {
+	// setup types
+	b := new(library.Build)
+	b.SetEvent("push")
+	b.SetBranch("main")
+	b.SetCommit("a1b2c3d4e5f6")
+
+	r := new(library.Repo)
+	r.SetFullName("github/octocat")
+	r.SetBranch("main")
+
+	source, err := os.ReadFile("testdata/starlark/pipeline.star")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	got, err := NewStarlark(b, r).Evaluate("pipeline.star", source, nil)
+	if err != nil {
+		t.Fatalf("Evaluate returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(got.Environment, raw.StringSliceMap{"GOOS": "linux"}) {
+		t.Errorf("Evaluate environment is %v, want GOOS=linux", got.Environment)
+	}
+
+	if got.Steps[0].Image != "golang:1.23" {
+		t.Errorf("Evaluate image is %s, want golang:1.23", got.Steps[0].Image)
+	}
+
+	if got.Steps[1].Parameters["repo"] != "github/octocat" {
+		t.Errorf("Evaluate repo is %v, want github/octocat", got.Steps[1].Parameters["repo"])
+	}
+
+	if !reflect.DeepEqual(got.Steps[1].Parameters["tags"], []interface{}{"a1b2c3d"}) {
+		t.Errorf("Evaluate tags are %v, want [a1b2c3d]", got.Steps[1].Parameters["tags"])
+	}
+
+	// run test without a build
+	got, err = NewStarlark(nil, r).Evaluate("pipeline.star", source, nil)
+	if err != nil {
+		t.Fatalf("Evaluate returned err: %v", err)
+	}
+
+	if len(got.Steps) != 1 {
+		t.Errorf("Evaluate returned %d steps, want 1", len(got.Steps))
+	}
+}
+
+func TestYaml_Starlark_Evaluate_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		file   string
+		exec   uint64
+		output int
+		want   error
+	}{
+		{
+			file: "testdata/starlark/no_main.star",
+			want: ErrStarlarkMain,
+		},
+		{
+			file: "testdata/starlark/result.star",
+			want: ErrStarlarkResult,
+		},
+		{
+			file: "testdata/starlark/loop.star",
+			exec: 1000,
+			want: ErrStarlarkExecLimit,
+		},
+		{
+			file:   "testdata/starlark/pipeline.star",
+			output: 16,
+			want:   ErrStarlarkOutputLimit,
+		},
+		{
+			file: "testdata/starlark/oversized.star",
+			want: ErrStarlarkOutputLimit,
+		},
+		{
+			file: "testdata/starlark/load.star",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			source, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Fatalf("unable to read file: %v", err)
+			}
+
+			s := NewStarlark(nil, nil)
+
+			if test.exec > 0 {
+				s.SetExecLimit(test.exec)
+			}
+
+			if test.output > 0 {
+				s.SetOutputLimit(test.output)
+			}
+
+			_, err = s.Evaluate(test.file, source, nil)
+			if err == nil {
+				t.Fatalf("Evaluate should have returned err")
+			}
+
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("Evaluate returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@
 package yaml
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/go-vela/types/constants"
 	"github.com/go-vela/types/pipeline"
@@ -40,40 +42,99 @@ type (
 		User        string                 `yaml:"user,omitempty"        json:"user,omitempty" jsonschema:"description=Set the user for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-user-key"`
 		ReportAs    string                 `yaml:"report_as,omitempty" json:"report_as,omitempty" jsonschema:"description=Set the name of the step to report as.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-report_as-key"`
 		IDRequest   string                 `yaml:"id_request,omitempty" json:"id_request,omitempty" jsonschema:"description=Request ID Request Token for the step.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-id_request-key"`
+		Needs       raw.StringSlice        `yaml:"needs,omitempty,flow"  json:"needs,omitempty" jsonschema:"description=Steps that must complete before starting the current one.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-needs-key"`
+		Matrix      Matrix                 `yaml:"matrix,omitempty"      json:"matrix,omitempty" jsonschema:"description=Run the step once for every combination of values.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-matrix-key"`
+		// Timeout is a duration, like 90s or 1h30m, or a number
+		// of minutes when no unit is provided, like 10. An invalid
+		// timeout is ignored by ToPipeline and only reported by
+		// Validate, so the step runs without a timeout.
+		Timeout      string          `yaml:"timeout,omitempty"     json:"timeout,omitempty" jsonschema:"example=10m,description=Maximum time the container may run before it is stopped.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-timeout-key"`
+		Retries      int             `yaml:"retries,omitempty"     json:"retries,omitempty" jsonschema:"minimum=0,maximum=10,description=Number of times to retry the container when it fails.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-retries-key"`
+		RetryBackoff RetryBackoff    `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty" jsonschema:"description=Delay to wait between retries of the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-retry_backoff-key"`
+		Resources    Resources       `yaml:"resources,omitempty"     json:"resources,omitempty" jsonschema:"description=Compute resources requested by and allowed for the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-resources-key"`
+		Cache        CacheSlice      `yaml:"cache,omitempty"         json:"cache,omitempty" jsonschema:"description=Caches restored before and saved after the container.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-cache-key"`
+		Artifacts    ArtifactSlice   `yaml:"artifacts,omitempty"     json:"artifacts,omitempty" jsonschema:"description=Artifacts uploaded after the container completes.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-artifacts-key"`
+		Consume      raw.StringSlice `yaml:"consume,omitempty,flow"  json:"consume,omitempty" jsonschema:"description=Names of artifacts downloaded before the container starts.\nReference: https://go-vela.github.io/docs/reference/yaml/steps/#the-consume-key"`
 	}
 )
 
 // ToPipeline converts the StepSlice type
-// to a pipeline ContainerSlice type.
+// to a pipeline ContainerSlice type. A step
+// with a matrix is expanded to one container
+// for every combination of the matrix.
 func (s *StepSlice) ToPipeline() *pipeline.ContainerSlice {
 	// step slice we want to return
 	stepSlice := new(pipeline.ContainerSlice)
 
+	// capture the names every matrix step was expanded to
+	expanded := make(map[string][]string)
+
 	// iterate through each element in the step slice
 	for _, step := range *s {
 		// append the element to the pipeline container slice
-		*stepSlice = append(*stepSlice, &pipeline.Container{
-			Commands:    step.Commands,
-			Detach:      step.Detach,
-			Entrypoint:  step.Entrypoint,
-			Environment: step.Environment,
-			Image:       step.Image,
-			Name:        step.Name,
-			Privileged:  step.Privileged,
-			Pull:        step.Pull,
-			Ruleset:     *step.Ruleset.ToPipeline(),
-			Secrets:     *step.Secrets.ToPipeline(),
-			Ulimits:     *step.Ulimits.ToPipeline(),
-			Volumes:     *step.Volumes.ToPipeline(),
-			User:        step.User,
-			ReportAs:    step.ReportAs,
-			IDRequest:   step.IDRequest,
-		})
+		if step.Matrix.Empty() {
+			*stepSlice = append(*stepSlice, step.toContainer(step.Name, step.Environment))
+
+			continue
+		}
+
+		// append an element for every combination of the matrix
+		for _, combination := range step.Matrix.Combinations() {
+			name := step.Matrix.Name(step.Name, combination)
+
+			*stepSlice = append(*stepSlice, step.toContainer(name, mergeEnvironment(step.Environment, step.Matrix.Environment(combination))))
+
+			expanded[step.Name] = append(expanded[step.Name], name)
+		}
+	}
+
+	// replace the needs on the expanded matrix steps
+	for _, container := range *stepSlice {
+		container.Needs = expandNeeds(container.Needs, expanded)
 	}
 
 	return stepSlice
 }
 
+// toContainer is a helper function to convert the Step
+// type to a pipeline Container type with the provided
+// name and environment.
+func (s *Step) toContainer(name string, environment map[string]string) *pipeline.Container {
+	return &pipeline.Container{
+		Commands:    s.Commands,
+		Detach:      s.Detach,
+		Entrypoint:  s.Entrypoint,
+		Environment: environment,
+		Image:       s.Image,
+		Name:        name,
+		Needs:       s.Needs,
+		Privileged:  s.Privileged,
+		Pull:        s.Pull,
+		Ruleset:     *s.Ruleset.ToPipeline(),
+		Secrets:     *s.Secrets.ToPipeline(),
+		Ulimits:     *s.Ulimits.ToPipeline(),
+		Volumes:     *s.Volumes.ToPipeline(),
+		User:        s.User,
+		ReportAs:    s.ReportAs,
+		IDRequest:   s.IDRequest,
+		// invalid timeouts are reported when validating the yaml
+		Timeout:      s.timeout(),
+		Retries:      s.Retries,
+		RetryBackoff: s.RetryBackoff.ToPipeline(),
+		Resources:    s.Resources.ToPipeline(),
+		Cache:        *s.Cache.ToPipeline(),
+		Artifacts:    *s.Artifacts.ToPipeline(),
+		Consume:      s.Consume,
+	}
+}
+
+// timeout is a helper function to parse the timeout for the step.
+func (s *Step) timeout() time.Duration {
+	timeout, _ := parseDuration(s.Timeout)
+
+	return timeout
+}
+
 // UnmarshalYAML implements the Unmarshaler interface for the StepSlice type.
 //
 //nolint:dupl // accepting duplicative code that exits in service.go as well
@@ -124,6 +185,116 @@ func (s *StepSlice) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	return nil
 }
 
+// Validate verifies the yaml for every step in the StepSlice
+// type and returns every problem found.
+func (s *StepSlice) Validate() Diagnostics {
+	diagnostics := s.validate("steps")
+
+	diagnostics = append(diagnostics, s.validateConsume("steps", nil, nil)...)
+
+	return diagnostics
+}
+
+// validate is a helper function to verify every step in the
+// StepSlice type with paths relative to the provided path.
+func (s *StepSlice) validate(path string) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// capture the index for every step name
+	names := make(map[string]int)
+
+	// iterate through each step in the step slice
+	for i, step := range *s {
+		stepPath := indexPath(path, i)
+
+		// verify a name was provided for the step
+		if len(step.Name) == 0 {
+			diagnostics.errorf(joinPath(stepPath, "name"), "no name provided")
+		} else if first, ok := names[step.Name]; ok {
+			diagnostics.errorf(joinPath(stepPath, "name"), "duplicate step name %s (first declared at %s)", step.Name, indexPath(path, first))
+		} else {
+			names[step.Name] = i
+		}
+
+		// verify the matrix for the step
+		if !step.Matrix.Empty() {
+			diagnostics = append(diagnostics, step.Matrix.validate(joinPath(stepPath, "matrix"), step.Name)...)
+
+			// verify the names for the expanded matrix steps are unique
+			for _, combination := range step.Matrix.Combinations() {
+				name := step.Matrix.Name(step.Name, combination)
+
+				if first, ok := names[name]; ok && name != step.Name {
+					diagnostics.errorf(joinPath(stepPath, "matrix"), "duplicate step name %s (first declared at %s)", name, indexPath(path, first))
+				}
+			}
+		}
+
+		// verify an image or template was provided for the step
+		if len(step.Image) == 0 && len(step.Template.Name) == 0 {
+			diagnostics.errorf(joinPath(stepPath, "image"), "no image provided for step %s", step.Name)
+		}
+
+		// verify the pull policy for the step
+		if len(step.Pull) > 0 && !validPull(step.Pull) {
+			diagnostics.errorf(joinPath(stepPath, "pull"), "invalid pull policy %s for step %s", step.Pull, step.Name)
+		}
+
+		diagnostics = append(diagnostics, step.Ruleset.validate(joinPath(stepPath, "ruleset"))...)
+		diagnostics = append(diagnostics, validateRetry(stepPath, "step "+step.Name, step.Timeout, step.Retries, &step.RetryBackoff)...)
+		diagnostics = append(diagnostics, step.Cache.validate(joinPath(stepPath, "cache"))...)
+		diagnostics = append(diagnostics, step.Artifacts.validate(joinPath(stepPath, "artifacts"), step.Name)...)
+		diagnostics = append(diagnostics, step.Resources.validate(joinPath(stepPath, "resources"), "step "+step.Name)...)
+	}
+
+	diagnostics = append(diagnostics, s.validateNeeds(path, names)...)
+
+	return diagnostics
+}
+
+// validateNeeds is a helper function to verify the needs for
+// every step in the StepSlice type reference other steps and
+// do not contain a cycle.
+func (s *StepSlice) validateNeeds(path string, names map[string]int) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	// iterate through each step in the step slice
+	for i, step := range *s {
+		for j, need := range step.Needs {
+			needPath := indexPath(joinPath(indexPath(path, i), "needs"), j)
+
+			// verify the step does not need itself
+			if need == step.Name {
+				diagnostics.errorf(needPath, "step %s needs itself", step.Name)
+
+				continue
+			}
+
+			// verify the step needs an existing step
+			if _, ok := names[need]; !ok {
+				diagnostics.errorf(needPath, "step %s needs unknown step %s", step.Name, need)
+			}
+		}
+	}
+
+	// skip checking for a cycle when the references are invalid
+	if len(diagnostics) > 0 || len(names) != len(*s) {
+		return diagnostics
+	}
+
+	// verify the needs for the steps do not contain a cycle
+	_, err := s.ToPipeline().DAG()
+	if err != nil {
+		var cycle *pipeline.CycleError
+
+		if errors.As(err, &cycle) {
+			diagnostics.errorf(joinPath(indexPath(path, names[cycle.Path[0]]), "needs"), "%v", err)
+		}
+	}
+
+	return diagnostics
+}
+
 // MergeEnv takes a list of environment variables and attempts
 // to set them in the step environment. If the environment
 // variable already exists in the step, than this will
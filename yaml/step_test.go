@@ -28,6 +28,7 @@ func TestYaml_StepSlice_ToPipeline(t *testing.T) {
 					Environment: map[string]string{"FOO": "bar"},
 					Image:       "alpine:latest",
 					Name:        "echo",
+					Needs:       []string{"clone"},
 					Privileged:  false,
 					Pull:        "not_present",
 					ReportAs:    "my-step",
@@ -86,6 +87,7 @@ func TestYaml_StepSlice_ToPipeline(t *testing.T) {
 					Environment: map[string]string{"FOO": "bar"},
 					Image:       "alpine:latest",
 					Name:        "echo",
+					Needs:       []string{"clone"},
 					Privileged:  false,
 					Pull:        "not_present",
 					ReportAs:    "my-step",
@@ -261,6 +263,74 @@ func TestYaml_StepSlice_UnmarshalYAML(t *testing.T) {
 	}
 }
 
+func TestYaml_StepSlice_Validate(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: "testdata/step.yml",
+			want: []string{},
+		},
+		{
+			file: "testdata/step_needs.yml",
+			want: []string{
+				"error: steps[2].needs[1]: step web needs itself",
+				"error: steps[2].needs[2]: step web needs unknown step lint",
+			},
+		},
+		{
+			file: "testdata/step_needs_cycle.yml",
+			want: []string{
+				"error: steps[1].needs: dependency cycle detected: api -> deploy -> api",
+			},
+		},
+		{
+			file: "testdata/step_retry.yml",
+			want: []string{
+				"error: steps[1].retries: retries for step test must be between 0 and 10",
+				"error: steps[1].retry_backoff.type: invalid retry backoff type linear for step test",
+				"error: steps[1].retry_backoff.max: retry backoff max for step test is less than the delay",
+				"error: steps[2].timeout: invalid duration forever for step build",
+				"warning: steps[2].retry_backoff: retry_backoff has no effect for step build without retries",
+			},
+		},
+		{
+			file: "testdata/step_resources.yml",
+			want: []string{
+				"error: steps[1].resources.requests.memory: invalid resource quantity: lots for step test",
+				"error: steps[1].resources.requests.cpu: cpu request 4 exceeds cpu limit 1 for step test",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		steps := new(StepSlice)
+
+		b, err := os.ReadFile(test.file)
+		if err != nil {
+			t.Errorf("unable to read file: %v", err)
+		}
+
+		err = yaml.Unmarshal(b, steps)
+		if err != nil {
+			t.Errorf("UnmarshalYAML returned err: %v", err)
+		}
+
+		got := []string{}
+
+		for _, diagnostic := range steps.Validate() {
+			got = append(got, diagnostic.String())
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Validate for %s is %v, want %v", test.file, got, test.want)
+		}
+	}
+}
+
 func TestYaml_Step_MergeEnv(t *testing.T) {
 	// setup tests
 	tests := []struct {
@@ -0,0 +1,304 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/buildkite/yaml"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/go-vela/types/constants"
+)
+
+// schema is the JSON schema for a pipeline generated by
+// the schema package, which is verified to be up to date
+// by the tests for the schema package.
+//
+//go:embed schema.json
+var schema []byte
+
+// ErrStrict defines the error type when a
+// pipeline does not match the JSON schema.
+var ErrStrict = errors.New("pipeline does not match schema")
+
+// compiled represents the compiled JSON schema for a pipeline.
+var compiled = sync.OnceValues(func() (*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, err
+	}
+
+	c := jsonschema.NewCompiler()
+
+	err = c.AddResource("schema.json", doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Compile("schema.json")
+})
+
+// printer is used to produce the message for a diagnostic.
+var printer = message.NewPrinter(language.English)
+
+// Schema returns the JSON schema for a pipeline.
+func Schema() []byte {
+	return bytes.Clone(schema)
+}
+
+// UnmarshalStrict validates the provided raw YAML document against
+// the JSON schema for a pipeline before unmarshaling it to a Build
+// type. Unlike unmarshaling the document directly, unknown keys and
+// values of the wrong type are reported as a diagnostic, with the
+// line and column resolved from the document, and an error wrapping
+// ErrStrict is returned without a Build type.
+func UnmarshalStrict(data []byte) (*Build, Diagnostics, error) {
+	root := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(data, root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse document: %w", err)
+	}
+
+	s, err := compiled()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to compile schema: %w", err)
+	}
+
+	doc := instance(resolve(root))
+
+	// validate the document against the schema
+	err = s.Validate(doc)
+	if err != nil {
+		var verr *jsonschema.ValidationError
+		if !errors.As(err, &verr) {
+			return nil, nil, err
+		}
+
+		diagnostics := Diagnostics{}
+		seen := make(map[string]bool)
+
+		for _, cause := range leaves(verr) {
+			for _, diagnostic := range diagnose(doc, cause) {
+				// skip problems reported through more than one reference
+				if seen[diagnostic.String()] {
+					continue
+				}
+
+				seen[diagnostic.String()] = true
+
+				diagnostics = append(diagnostics, diagnostic)
+			}
+		}
+
+		diagnostics.Locate(&SourceMap{root: root})
+
+		// order the diagnostics by their position in the document
+		sort.SliceStable(diagnostics, func(i, j int) bool {
+			if diagnostics[i].Line != diagnostics[j].Line {
+				return diagnostics[i].Line < diagnostics[j].Line
+			}
+
+			return diagnostics[i].Column < diagnostics[j].Column
+		})
+
+		return nil, diagnostics, fmt.Errorf("%w:\n%s", ErrStrict, diagnostics)
+	}
+
+	b := new(Build)
+
+	// attempt to unmarshal the document as a build type
+	err = yaml.Unmarshal(data, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return b, Diagnostics{}, nil
+}
+
+// instance is a helper function to convert the provided node to
+// the value validated against the schema. Aliases are resolved
+// and keys from a `<<` merge key are added to the mapping.
+func instance(node *yamlv3.Node) interface{} {
+	if node == nil {
+		return nil
+	}
+
+	switch node.Kind {
+	case yamlv3.MappingNode:
+		m := make(map[string]interface{})
+
+		// keys defined directly on the mapping take precedence
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Tag != "!!merge" {
+				m[node.Content[i].Value] = instance(resolve(node.Content[i+1]))
+			}
+		}
+
+		// add the keys from any merged mappings
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Tag != "!!merge" {
+				continue
+			}
+
+			merged := resolve(node.Content[i+1])
+			if merged == nil {
+				continue
+			}
+
+			mappings := []*yamlv3.Node{merged}
+			if merged.Kind == yamlv3.SequenceNode {
+				mappings = merged.Content
+			}
+
+			for _, mapping := range mappings {
+				values, ok := instance(resolve(mapping)).(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				for key, value := range values {
+					if _, ok := m[key]; !ok {
+						m[key] = value
+					}
+				}
+			}
+		}
+
+		return m
+	case yamlv3.SequenceNode:
+		s := make([]interface{}, 0, len(node.Content))
+
+		for _, item := range node.Content {
+			s = append(s, instance(resolve(item)))
+		}
+
+		return s
+	default:
+		var value interface{}
+
+		// capture the value for the scalar with its resolved type
+		err := node.Decode(&value)
+		if err != nil {
+			return node.Value
+		}
+
+		// timestamps are unmarshaled as strings for a pipeline
+		if _, ok := value.(string); !ok && node.ShortTag() == "!!timestamp" {
+			return node.Value
+		}
+
+		return value
+	}
+}
+
+// leaves is a helper function to return the errors from the
+// provided validation error that describe a single problem.
+//
+// When a value is allowed in more than one form, the forms
+// of a different type are ignored and the errors from the
+// form with the fewest problems are returned.
+func leaves(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+
+	switch err.ErrorKind.(type) {
+	case *kind.AnyOf, *kind.OneOf:
+		var best []*jsonschema.ValidationError
+
+		for _, cause := range err.Causes {
+			// skip forms with a different type than the value
+			if _, ok := cause.ErrorKind.(*kind.Type); ok && slices.Equal(cause.InstanceLocation, err.InstanceLocation) {
+				continue
+			}
+
+			problems := leaves(cause)
+
+			if best == nil || len(problems) < len(best) {
+				best = problems
+			}
+		}
+
+		// fall back to the error itself when no form has the same type
+		if best == nil {
+			return []*jsonschema.ValidationError{err}
+		}
+
+		return best
+	}
+
+	problems := []*jsonschema.ValidationError{}
+
+	for _, cause := range err.Causes {
+		problems = append(problems, leaves(cause)...)
+	}
+
+	return problems
+}
+
+// diagnose is a helper function to return the diagnostics
+// for the provided validation error of the document.
+func diagnose(doc interface{}, err *jsonschema.ValidationError) Diagnostics {
+	path := documentPath(doc, err.InstanceLocation)
+
+	// report every unknown key at the position of the key
+	if additional, ok := err.ErrorKind.(*kind.AdditionalProperties); ok {
+		diagnostics := Diagnostics{}
+
+		for _, key := range additional.Properties {
+			diagnostics = append(diagnostics, &Diagnostic{
+				Severity: constants.SeverityError,
+				Path:     joinPath(path, key),
+				Message:  fmt.Sprintf("unknown key %s", key),
+			})
+		}
+
+		return diagnostics
+	}
+
+	return Diagnostics{{
+		Severity: constants.SeverityError,
+		Path:     path,
+		Message:  err.ErrorKind.LocalizedString(printer),
+	}}
+}
+
+// documentPath is a helper function to convert the provided
+// location in the document to a path like `steps[0].pull`.
+func documentPath(doc interface{}, location []string) string {
+	path := ""
+
+	for _, token := range location {
+		switch value := doc.(type) {
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(value) {
+				return path
+			}
+
+			path = indexPath(path, index)
+			doc = value[index]
+		case map[string]interface{}:
+			path = joinPath(path, token)
+			doc = value[token]
+		default:
+			return path
+		}
+	}
+
+	return path
+}
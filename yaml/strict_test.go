@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestYaml_UnmarshalStrict(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: "testdata/build_anchor_extension.yml",
+			want: []string{},
+		},
+		{
+			file: "testdata/build_anchor_stage.yml",
+			want: []string{},
+		},
+		{
+			file: "testdata/build_empty_env.yml",
+			want: []string{},
+		},
+		{
+			// duplicate merge keys are accepted by the parser
+			file: "testdata/merge_anchor.yml",
+			want: []string{},
+		},
+		{
+			file: "testdata/strict_unknown_key.yml",
+			want: []string{
+				"6:5: error: steps[0].enviroment: unknown key enviroment",
+				"13:5: error: steps[1].pulll: unknown key pulll",
+			},
+		},
+		{
+			file: "testdata/strict_type.yml",
+			want: []string{
+				"8:9: error: stages.test.steps[0].retries: got string, want integer",
+				"9:9: error: stages.test.steps[0].privileged: got string, want boolean",
+			},
+		},
+		{
+			file: "testdata/strict_stage_dot.yml",
+			want: []string{
+				"8:9: error: stages.\"s.x\".steps[0].retries: got string, want integer",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			data, err := os.ReadFile(test.file)
+			if err != nil {
+				t.Errorf("unable to read file: %v", err)
+			}
+
+			b, diagnostics, err := UnmarshalStrict(data)
+
+			got := []string{}
+
+			for _, diagnostic := range diagnostics {
+				got = append(got, diagnostic.String())
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("UnmarshalStrict is %v, want %v", got, test.want)
+			}
+
+			if len(test.want) > 0 {
+				if !errors.Is(err, ErrStrict) {
+					t.Errorf("UnmarshalStrict returned err %v, want %v", err, ErrStrict)
+				}
+
+				if b != nil {
+					t.Errorf("UnmarshalStrict returned build %v, want nil", b)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("UnmarshalStrict returned err: %v", err)
+			}
+
+			if b == nil {
+				t.Errorf("UnmarshalStrict returned nil build")
+			}
+		})
+	}
+}
+
+func TestYaml_UnmarshalStrict_Invalid(t *testing.T) {
+	_, _, err := UnmarshalStrict([]byte("steps: [ foo"))
+	if err == nil {
+		t.Errorf("UnmarshalStrict should have returned err")
+	}
+
+	if errors.Is(err, ErrStrict) {
+		t.Errorf("UnmarshalStrict returned %v for a document that can not be parsed", ErrStrict)
+	}
+}
+
+func TestYaml_Schema(t *testing.T) {
+	if !json.Valid(Schema()) {
+		t.Errorf("Schema is not a valid JSON document")
+	}
+}
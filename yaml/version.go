@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/go-vela/types/constants"
+)
+
+var (
+	// ErrVersionUnknown defines the error type when
+	// the syntax version for a pipeline is not supported.
+	ErrVersionUnknown = errors.New("unknown pipeline version")
+
+	// ErrVersionRegistered defines the error type when
+	// registering a syntax version that already exists.
+	ErrVersionRegistered = errors.New("pipeline version already registered")
+)
+
+// Upgrade is a transform rewriting the node tree for a document
+// written for the previous syntax version to the syntax version
+// it is registered for. The provided node is the top level mapping
+// of the document. An upgrade is also applied to documents already
+// written for its version, so applying it more than once must not
+// change the document any further.
+type Upgrade struct {
+	// Description summarizes the changes made by the upgrade.
+	Description string
+	// Apply rewrites the provided top level mapping in place.
+	Apply func(node *yamlv3.Node) error
+}
+
+// syntaxVersion is a supported syntax version for a pipeline with the
+// upgrades migrating a document from the previous syntax version.
+type syntaxVersion struct {
+	name     string
+	upgrades []Upgrade
+}
+
+var (
+	// versionsMutex guards the registered syntax versions.
+	versionsMutex sync.RWMutex
+
+	// versions are the supported syntax versions for a
+	// pipeline ordered from the oldest to the latest.
+	versions = []syntaxVersion{
+		{
+			name: constants.PipelineVersion1,
+		},
+		{
+			name: constants.PipelineVersion2,
+			upgrades: []Upgrade{
+				{
+					Description: "replace event shorthands with the scoped events they are expanded to",
+					Apply:       upgradeEvents,
+				},
+			},
+		},
+	}
+)
+
+// RegisterVersion adds the syntax version with the provided name as
+// the latest supported syntax version. The provided upgrades migrate
+// a document from the previous syntax version and are applied in order.
+func RegisterVersion(name string, upgrades ...Upgrade) error {
+	versionsMutex.Lock()
+	defer versionsMutex.Unlock()
+
+	// verify the syntax version does not already exist
+	if versionIndex(name) >= 0 {
+		return fmt.Errorf("%w: %s", ErrVersionRegistered, name)
+	}
+
+	versions = append(versions, syntaxVersion{
+		name:     name,
+		upgrades: slices.Clone(upgrades),
+	})
+
+	return nil
+}
+
+// Versions returns the supported syntax versions
+// for a pipeline ordered from the oldest to the latest.
+func Versions() []string {
+	versionsMutex.RLock()
+	defer versionsMutex.RUnlock()
+
+	names := []string{}
+
+	for _, version := range versions {
+		names = append(names, version.name)
+	}
+
+	return names
+}
+
+// LatestVersion returns the latest supported syntax version for a pipeline.
+func LatestVersion() string {
+	versionsMutex.RLock()
+	defer versionsMutex.RUnlock()
+
+	return versions[len(versions)-1].name
+}
+
+// CheckVersion verifies the provided syntax version for a pipeline
+// is supported. A pipeline without a version is evaluated with the
+// oldest syntax version so an empty version is supported as well.
+func CheckVersion(version string) error {
+	versionsMutex.RLock()
+	defer versionsMutex.RUnlock()
+
+	if len(version) == 0 || versionIndex(version) >= 0 {
+		return nil
+	}
+
+	return unknownVersion(version)
+}
+
+// Migrate returns the provided raw YAML document rewritten to the
+// latest syntax version. The upgrades registered for every syntax
+// version after the version of the document are applied in order
+// and the version of the document is updated. The key order,
+// comments and empty lines of the document are preserved. A
+// document without a version is migrated from the oldest syntax
+// version.
+func Migrate(src []byte) ([]byte, error) {
+	doc := new(yamlv3.Node)
+
+	// attempt to parse the document into a node tree
+	err := yamlv3.Unmarshal(src, doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse source document: %w", err)
+	}
+
+	// verify the document contains a pipeline
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("unable to migrate source document: document is not a pipeline")
+	}
+
+	root := doc.Content[0]
+
+	versionsMutex.RLock()
+	defer versionsMutex.RUnlock()
+
+	version := constants.PipelineVersion1
+
+	_, value := lookup(root, "version")
+	if value != nil {
+		version = value.Value
+	}
+
+	index := versionIndex(version)
+	if index < 0 {
+		return nil, unknownVersion(version)
+	}
+
+	// apply the upgrades for every later syntax version
+	for _, next := range versions[index+1:] {
+		err = applyUpgrades(root, next)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	latest := versions[len(versions)-1].name
+
+	// update the version of the document
+	if value != nil {
+		value.Kind = yamlv3.ScalarNode
+		value.Tag = "!!str"
+		value.Value = latest
+	} else {
+		root.Content = append([]*yamlv3.Node{scalarNode("version"), scalarNode(latest)}, root.Content...)
+	}
+
+	out, err := encodeNode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return keepEmptyLines(src, out), nil
+}
+
+// upgradeNode is a helper function to apply the upgrades for every
+// syntax version up to the provided version to a top level mapping
+// so the canonical form of a pipeline is valid for its version.
+func upgradeNode(node *yamlv3.Node, version string) error {
+	versionsMutex.RLock()
+	defer versionsMutex.RUnlock()
+
+	// iterate through each syntax version up to the provided version
+	for _, v := range versions[:versionIndex(version)+1] {
+		err := applyUpgrades(node, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyUpgrades is a helper function to apply the upgrades
+// for the provided syntax version to a top level mapping.
+func applyUpgrades(node *yamlv3.Node, version syntaxVersion) error {
+	for _, upgrade := range version.upgrades {
+		err := upgrade.Apply(node)
+		if err != nil {
+			return fmt.Errorf("unable to upgrade document to version %s: %s: %w", version.name, upgrade.Description, err)
+		}
+	}
+
+	return nil
+}
+
+// versionIndex is a helper function to return the index of the
+// syntax version with the provided name or -1 when it does not
+// exist. The caller is responsible for holding the versionsMutex.
+func versionIndex(name string) int {
+	return slices.IndexFunc(versions, func(v syntaxVersion) bool { return v.name == name })
+}
+
+// unknownVersion is a helper function to return the error for an
+// unsupported syntax version listing the supported versions. The
+// caller is responsible for holding the versionsMutex.
+func unknownVersion(version string) error {
+	names := []string{}
+
+	for _, v := range versions {
+		names = append(names, v.name)
+	}
+
+	return fmt.Errorf("%w: %s (supported versions: %s)", ErrVersionUnknown, version, strings.Join(names, ", "))
+}
+
+// upgradeEvents is an upgrade to replace every event shorthand
+// in the rulesets for the steps and secret origins with the
+// scoped events it is expanded to when parsing the rules.
+func upgradeEvents(node *yamlv3.Node) error {
+	containers := append(stepNodes(node), originNodes(node)...)
+
+	// iterate through each step and secret origin
+	for _, container := range containers {
+		_, ruleset := lookup(container, "ruleset")
+		if ruleset == nil || ruleset.Kind != yamlv3.MappingNode {
+			continue
+		}
+
+		// the simple form of a ruleset contains the rules directly
+		for _, rules := range []*yamlv3.Node{ruleset, mappingValue(ruleset, "if"), mappingValue(ruleset, "unless")} {
+			_, events := lookup(rules, "event")
+			if events == nil {
+				continue
+			}
+
+			switch events.Kind {
+			case yamlv3.ScalarNode:
+				scoped := expandEvent(events.Value)
+				if slices.Equal(scoped, []string{events.Value}) {
+					continue
+				}
+
+				// replace the event with a sequence of the scoped events
+				expanded := flow(sequenceNode())
+
+				for _, event := range scoped {
+					expanded.Content = append(expanded.Content, scalarNode(event))
+				}
+
+				copyComments(expanded, events)
+				expanded.Anchor = events.Anchor
+
+				*events = *expanded
+			case yamlv3.SequenceNode:
+				content := []*yamlv3.Node{}
+
+				for _, event := range events.Content {
+					scoped := expandEvent(event.Value)
+					if event.Kind != yamlv3.ScalarNode || slices.Equal(scoped, []string{event.Value}) {
+						content = append(content, event)
+
+						continue
+					}
+
+					// the comments for the event are kept on the first scoped event
+					for i, value := range scoped {
+						item := scalarNode(value)
+						if i == 0 {
+							copyComments(item, event)
+						}
+
+						content = append(content, item)
+					}
+				}
+
+				events.Content = content
+			}
+		}
+	}
+
+	return nil
+}
+
+// stepNodes is a helper function to return the node for every
+// step in the provided top level mapping, including the steps
+// for every stage.
+func stepNodes(node *yamlv3.Node) []*yamlv3.Node {
+	steps := sequenceValue(node, "steps")
+
+	_, stages := lookup(node, "stages")
+	if stages != nil && stages.Kind == yamlv3.MappingNode {
+		// iterate through each stage
+		for i := 1; i < len(stages.Content); i += 2 {
+			steps = append(steps, sequenceValue(resolve(stages.Content[i]), "steps")...)
+		}
+	}
+
+	return steps
+}
+
+// originNodes is a helper function to return the node for the
+// origin of every secret in the provided top level mapping.
+func originNodes(node *yamlv3.Node) []*yamlv3.Node {
+	origins := []*yamlv3.Node{}
+
+	// iterate through each secret
+	for _, secret := range sequenceValue(node, "secrets") {
+		if origin := mappingValue(secret, "origin"); origin != nil {
+			origins = append(origins, origin)
+		}
+	}
+
+	return origins
+}
+
+// sequenceValue is a helper function to return the
+// mapping items of the sequence for the provided key.
+func sequenceValue(node *yamlv3.Node, key string) []*yamlv3.Node {
+	items := []*yamlv3.Node{}
+
+	_, value := lookup(node, key)
+	if value == nil || value.Kind != yamlv3.SequenceNode {
+		return items
+	}
+
+	for _, item := range value.Content {
+		if item = resolve(item); item != nil && item.Kind == yamlv3.MappingNode {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// mappingValue is a helper function to return the
+// mapping for the provided key when it exists.
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	_, value := lookup(node, key)
+	if value == nil || value.Kind != yamlv3.MappingNode {
+		return nil
+	}
+
+	return value
+}
@@ -0,0 +1,390 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yaml
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/buildkite/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/go-vela/types/constants"
+	"github.com/go-vela/types/raw"
+)
+
+func TestYaml_Migrate(t *testing.T) {
+	// setup types
+	data, err := os.ReadFile("testdata/version/migrate.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	want, err := os.ReadFile("testdata/version/migrate_want.yml")
+	if err != nil {
+		t.Fatalf("unable to read file: %v", err)
+	}
+
+	// run test
+	got, err := Migrate(data)
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Migrate is %s, want %s", got, want)
+	}
+
+	if strings.Contains(string(got), "!!merge") {
+		t.Errorf("Migrate is %s, want merge keys without a tag", got)
+	}
+
+	// verify migrating is idempotent
+	again, err := Migrate(got)
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	if string(again) != string(want) {
+		t.Errorf("Migrate is %s, want %s", again, want)
+	}
+
+	// verify the migrated document parses to the same pipeline
+	original := new(Build)
+
+	err = yaml.Unmarshal(data, original)
+	if err != nil {
+		t.Fatalf("unable to unmarshal original document: %v", err)
+	}
+
+	migrated := new(Build)
+
+	err = yaml.Unmarshal(got, migrated)
+	if err != nil {
+		t.Fatalf("unable to unmarshal migrated document: %v", err)
+	}
+
+	if migrated.Version != LatestVersion() {
+		t.Errorf("Migrate version is %s, want %s", migrated.Version, LatestVersion())
+	}
+
+	original.Version = migrated.Version
+
+	// the extensions are kept as written, including the steps anchored in them
+	original.Extensions, migrated.Extensions = nil, nil
+
+	if !reflect.DeepEqual(migrated, original) {
+		t.Errorf("Migrate parsed is %v, want %v", migrated, original)
+	}
+}
+
+func TestYaml_Migrate_NoVersion(t *testing.T) {
+	// setup types
+	data := []byte("steps:\n  - name: test\n    image: alpine\n    pull: true\n")
+
+	// run test
+	got, err := Migrate(data)
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	want := "version: \"2\"\nsteps:\n  - name: test\n    image: alpine\n    pull: true\n"
+
+	if string(got) != want {
+		t.Errorf("Migrate is %q, want %q", got, want)
+	}
+
+	// verify the upgrades for every version are applied
+	registerTestVersion(t)
+
+	got, err = Migrate(data)
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	want = "version: \"3\"\nsteps:\n  - name: test\n    image: alpine\n    pull: always\n"
+
+	if string(got) != want {
+		t.Errorf("Migrate is %q, want %q", got, want)
+	}
+}
+
+func TestYaml_Migrate_Failure(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		data string
+		want error
+	}{
+		{
+			name: "unknown version",
+			data: "version: \"4\"\nsteps: []\n",
+			want: ErrVersionUnknown,
+		},
+		{
+			name: "invalid document",
+			data: "steps: [",
+		},
+		{
+			name: "empty document",
+			data: "",
+		},
+		{
+			name: "not a pipeline",
+			data: "- version: \"1\"\n",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Migrate([]byte(test.data))
+			if err == nil {
+				t.Fatalf("Migrate should have returned err")
+			}
+
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("Migrate returned err %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestYaml_CheckVersion(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		version string
+		want    error
+	}{
+		{version: "", want: nil},
+		{version: "1", want: nil},
+		{version: "1.0", want: ErrVersionUnknown},
+		{version: "2", want: nil},
+		{version: "3", want: ErrVersionUnknown},
+	}
+
+	// run tests
+	for _, test := range tests {
+		err := CheckVersion(test.version)
+		if !errors.Is(err, test.want) {
+			t.Errorf("CheckVersion for %q returned err %v, want %v", test.version, err, test.want)
+		}
+	}
+
+	if !reflect.DeepEqual(Versions(), []string{"1", "2"}) {
+		t.Errorf("Versions is %v, want [1 2]", Versions())
+	}
+}
+
+func TestYaml_RegisterVersion(t *testing.T) {
+	// setup types
+	registerTestVersion(t)
+
+	// run test
+	err := RegisterVersion("4", renameCommands)
+	if err != nil {
+		t.Fatalf("RegisterVersion returned err: %v", err)
+	}
+
+	err = RegisterVersion("4")
+	if !errors.Is(err, ErrVersionRegistered) {
+		t.Errorf("RegisterVersion returned err %v, want %v", err, ErrVersionRegistered)
+	}
+
+	if LatestVersion() != "4" {
+		t.Errorf("LatestVersion is %s, want 4", LatestVersion())
+	}
+
+	got, err := Migrate([]byte("version: \"3\"\nsteps:\n  - name: test\n    commands: [ echo ]\n"))
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	want := "version: \"4\"\nsteps:\n  - name: test\n    run: [echo]\n"
+
+	if string(got) != want {
+		t.Errorf("Migrate is %q, want %q", got, want)
+	}
+
+	// verify the upgrades for every later version are applied
+	got, err = Migrate([]byte("version: \"1\"\nsteps:\n  - name: test\n    pull: true\n    ruleset:\n      event: deployment\n    commands: [ echo ]\n"))
+	if err != nil {
+		t.Fatalf("Migrate returned err: %v", err)
+	}
+
+	want = "version: \"4\"\nsteps:\n  - name: test\n    pull: always\n    ruleset:\n      event: ['deployment:created']\n    run: [echo]\n"
+
+	if string(got) != want {
+		t.Errorf("Migrate is %q, want %q", got, want)
+	}
+
+	// verify a failed upgrade is wrapped with the version
+	versions[len(versions)-1].upgrades[0].Apply = func(*yamlv3.Node) error {
+		return errors.New("failed")
+	}
+
+	_, err = Migrate([]byte("version: \"3\"\nsteps: []\n"))
+	if err == nil || !strings.Contains(err.Error(), "version 4: rename commands to run") {
+		t.Errorf("Migrate returned err %v, want upgrade error", err)
+	}
+}
+
+func TestYaml_Encode_Version(t *testing.T) {
+	// setup types
+	restoreVersions(t)
+
+	err := RegisterVersion("3", renameCommands)
+	if err != nil {
+		t.Fatalf("RegisterVersion returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{
+			version: "1",
+			want:    "commands:",
+		},
+		{
+			version: "2",
+			want:    "commands:",
+		},
+		{
+			version: "3",
+			want:    "run:",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			b := &Build{
+				Version: test.version,
+				Steps: StepSlice{
+					{
+						Name:     "test",
+						Image:    "alpine",
+						Commands: raw.StringSlice{"echo hello"},
+					},
+				},
+			}
+
+			got, err := Encode(b)
+			if err != nil {
+				t.Fatalf("Encode returned err: %v", err)
+			}
+
+			if !strings.Contains(string(got), test.want) {
+				t.Errorf("Encode is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestYaml_keepEmptyLines(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		src  string
+		out  string
+		want string
+	}{
+		{
+			src:  "a: 1\nb: 2\n",
+			out:  "a: 1\nb: 2\n",
+			want: "a: 1\nb: 2\n",
+		},
+		{
+			src:  "\na: 1\n\n\nb: [ x ]\n\nc: true\n",
+			out:  "a: 1\nb: [x]\nc: always\n",
+			want: "a: 1\n\n\nb: [x]\n\nc: always\n",
+		},
+		{
+			src:  "a: |\n  x\n\n  y\n",
+			out:  "a: |\n  x\n\n  y\n",
+			want: "a: |\n  x\n\n  y\n",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := keepEmptyLines([]byte(test.src), []byte(test.out))
+
+		if string(got) != test.want {
+			t.Errorf("keepEmptyLines is %q, want %q", got, test.want)
+		}
+	}
+}
+
+// restoreVersions is a helper function to restore the
+// registered syntax versions when the test completes.
+func restoreVersions(t *testing.T) {
+	registered := slices.Clone(versions)
+
+	t.Cleanup(func() {
+		versions = registered
+	})
+}
+
+// registerTestVersion is a helper function to register a syntax
+// version replacing boolean pull policies for the test.
+func registerTestVersion(t *testing.T) {
+	restoreVersions(t)
+
+	err := RegisterVersion("3", Upgrade{
+		Description: "replace boolean pull policies with the policy they are parsed as",
+		Apply:       upgradePull,
+	})
+	if err != nil {
+		t.Fatalf("RegisterVersion returned err: %v", err)
+	}
+}
+
+// renameCommands is an upgrade to rename the `commands` key for steps to `run`.
+var renameCommands = Upgrade{
+	Description: "rename commands to run",
+	Apply: func(node *yamlv3.Node) error {
+		for _, step := range stepNodes(node) {
+			key, _ := lookup(step, "commands")
+			if key != nil {
+				key.Value = "run"
+			}
+		}
+
+		return nil
+	},
+}
+
+// upgradePull is an upgrade to replace every boolean pull
+// policy for the steps and services with the pull policy
+// it is converted to when parsing the steps and services.
+func upgradePull(node *yamlv3.Node) error {
+	containers := append(stepNodes(node), sequenceValue(node, "services")...)
+
+	// iterate through each step and service
+	for _, container := range containers {
+		_, pull := lookup(container, "pull")
+		if pull == nil || pull.Kind != yamlv3.ScalarNode {
+			continue
+		}
+
+		switch {
+		case strings.EqualFold(pull.Value, "true"):
+			pull.Value = constants.PullAlways
+		case strings.EqualFold(pull.Value, "false"):
+			pull.Value = constants.PullNotPresent
+		default:
+			continue
+		}
+
+		pull.Tag = "!!str"
+		pull.Style = 0
+	}
+
+	return nil
+}